@@ -72,7 +72,7 @@ func NewServiceHub(c *etc.Config, limiter ServiceHub.Limiter) ServiceHub.IServic
 // 生成单节索引
 func NewIndexer(c *etc.Config) *index_service.Indexer {
 	indexer := &index_service.Indexer{}
-	err := indexer.Init(c.ReverseIndex.DocNumEstimate, c.ForwardIndex.Dbtype, c.GetDateDir())
+	err := indexer.Init(c)
 	if err != nil {
 		util.Log.Fatalf("kvdb连接错误")
 	}
@@ -90,3 +90,12 @@ func (r *Research) SetLoadBalancer(lb ServiceHub.LoadBalancer) error {
 	sentinel.IServiceHub.SetLoadBalancer(lb)
 	return nil
 }
+
+// 返回操作指定索引的Research，集群模式下才支持多个索引
+func (r *Research) Index(name string) (*Research, error) {
+	sentinel, ok := r.IIndexer.(*index_service.Sentinel)
+	if !ok {
+		return nil, errors.New("集群模式下才能使用命名索引")
+	}
+	return &Research{IIndexer: sentinel.Index(name)}, nil
+}
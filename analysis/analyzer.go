@@ -0,0 +1,80 @@
+package analysis
+
+// 文本分析：字符过滤 -> 分词 -> 词过滤
+// 添加文档时把原始文本转为倒排索引的关键词，查询时用同样的分析器处理查询词，保证两边一致
+
+// 分词结果
+type Token struct {
+	Term     string // 词
+	Position int    // 词在文本中的位置，停用词删除后留下空位，同义词和原词位置相同
+	Start    int    // 词在文本中的起始字节偏移
+	End      int    // 词在文本中的结束字节偏移
+}
+
+// 字符过滤器，分词前处理原始文本
+type ICharFilter interface {
+	Filter(text string) string
+}
+
+// 分词器
+type ITokenizer interface {
+	Tokenize(text string) []*Token
+}
+
+// 词过滤器，处理分词结果
+type ITokenFilter interface {
+	Filter(tokens []*Token) []*Token
+}
+
+// 分析器，由字符过滤器、分词器、词过滤器组成
+type Analyzer struct {
+	charFilters  []ICharFilter
+	tokenizer    ITokenizer
+	tokenFilters []ITokenFilter
+}
+
+func NewAnalyzer(tokenizer ITokenizer) *Analyzer {
+	return &Analyzer{tokenizer: tokenizer}
+}
+
+func (a *Analyzer) WithCharFilters(filters ...ICharFilter) *Analyzer {
+	a.charFilters = append(a.charFilters, filters...)
+	return a
+}
+
+func (a *Analyzer) WithTokenFilters(filters ...ITokenFilter) *Analyzer {
+	a.tokenFilters = append(a.tokenFilters, filters...)
+	return a
+}
+
+// 分析文本，返回的偏移量基于字符过滤之后的文本
+func (a *Analyzer) Analyze(text string) []*Token {
+	return a.Tokenize(a.Filter(text))
+}
+
+// 字符过滤
+func (a *Analyzer) Filter(text string) string {
+	for _, filter := range a.charFilters {
+		text = filter.Filter(text)
+	}
+	return text
+}
+
+// 对字符过滤之后的文本分词和词过滤，返回的偏移量基于text
+func (a *Analyzer) Tokenize(text string) []*Token {
+	tokens := a.tokenizer.Tokenize(text)
+	for _, filter := range a.tokenFilters {
+		tokens = filter.Filter(tokens)
+	}
+	return tokens
+}
+
+// 分析文本，只返回词
+func (a *Analyzer) Terms(text string) []string {
+	tokens := a.Analyze(text)
+	terms := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		terms = append(terms, token.Term)
+	}
+	return terms
+}
@@ -0,0 +1,38 @@
+package analysis
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var htmlTag = regexp.MustCompile(`(?s)<!--.*?-->|<[^>]*>`)
+
+// 去掉html标签，并把实体转为字符
+type HtmlStripCharFilter struct{}
+
+func NewHtmlStripCharFilter() *HtmlStripCharFilter {
+	return &HtmlStripCharFilter{}
+}
+
+func (f *HtmlStripCharFilter) Filter(text string) string {
+	// 标签替换为空格，避免前后的词连在一起
+	return html.UnescapeString(htmlTag.ReplaceAllString(text, " "))
+}
+
+// 按映射表替换字符串，例如全角转半角
+type MappingCharFilter struct {
+	replacer *strings.Replacer
+}
+
+func NewMappingCharFilter(mapping map[string]string) *MappingCharFilter {
+	pairs := make([]string, 0, 2*len(mapping))
+	for from, to := range mapping {
+		pairs = append(pairs, from, to)
+	}
+	return &MappingCharFilter{replacer: strings.NewReplacer(pairs...)}
+}
+
+func (f *MappingCharFilter) Filter(text string) string {
+	return f.replacer.Replace(text)
+}
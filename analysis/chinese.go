@@ -0,0 +1,139 @@
+package analysis
+
+import (
+	"bufio"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 中文分词器，基于词典的最大概率切分
+// 汉字片段按词频计算每种切分的概率，取概率最大的切分；其他字符按标准分词器处理
+// 没有词典时每个汉字单独成词，项目不附带词典，内置的chinese分析器就是这样
+// 需要按词切分时，在自定义分析器中用dict指定词典文件
+type ChineseTokenizer struct {
+	freqs      map[string]float64 // 词频
+	total      float64            // 词频总和
+	maxWordLen int                // 词典中最长的词包含的汉字数
+	standard   *StandardTokenizer
+}
+
+func NewChineseTokenizer() *ChineseTokenizer {
+	return &ChineseTokenizer{
+		freqs:      make(map[string]float64),
+		maxWordLen: 1,
+		standard:   NewStandardTokenizer(),
+	}
+}
+
+// 添加词典中的词，freq不大于0时按1计算
+func (t *ChineseTokenizer) WithWord(word string, freq float64) *ChineseTokenizer {
+	if len(word) == 0 {
+		return t
+	}
+	if freq <= 0 {
+		freq = 1
+	}
+	t.total += freq - t.freqs[word]
+	t.freqs[word] = freq
+	if n := utf8.RuneCountInString(word); n > t.maxWordLen {
+		t.maxWordLen = n
+	}
+	return t
+}
+
+// 从文件加载词典，每行一个词，词后面可以跟词频，用空白分隔，其余的列忽略
+func (t *ChineseTokenizer) WithDict(path string) (*ChineseTokenizer, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		freq := 0.0
+		if len(fields) > 1 {
+			freq, _ = strconv.ParseFloat(fields[1], 64)
+		}
+		t.WithWord(fields[0], freq)
+	}
+	return t, scanner.Err()
+}
+
+func (t *ChineseTokenizer) Tokenize(text string) []*Token {
+	tokens := make([]*Token, 0)
+	// 把文本切成汉字片段和非汉字片段，分别处理
+	start, inHan := 0, false
+	flush := func(end int) {
+		if start >= end {
+			return
+		}
+		if inHan {
+			tokens = t.segment(text, start, end, tokens)
+		} else {
+			for _, token := range t.standard.Tokenize(text[start:end]) {
+				token.Start += start
+				token.End += start
+				token.Position = len(tokens)
+				tokens = append(tokens, token)
+			}
+		}
+	}
+	for i, r := range text {
+		if han := unicode.Is(unicode.Han, r); han != inHan {
+			flush(i)
+			start, inHan = i, han
+		}
+	}
+	flush(len(text))
+	return tokens
+}
+
+// 对text[start:end]中的汉字做最大概率切分
+func (t *ChineseTokenizer) segment(text string, start, end int, tokens []*Token) []*Token {
+	// 每个汉字的字节偏移
+	offsets := make([]int, 0, end-start)
+	for i := range text[start:end] {
+		offsets = append(offsets, start+i)
+	}
+	offsets = append(offsets, end)
+	n := len(offsets) - 1
+
+	// 从后向前动态规划，best[i]是从第i个字开始到结尾的最大对数概率，next[i]是第i个字所在词的结尾
+	logTotal := math.Log(t.total + 1)
+	best := make([]float64, n+1)
+	next := make([]int, n+1)
+	for i := n - 1; i >= 0; i-- {
+		best[i] = math.Inf(-1)
+		for j := i + 1; j <= n && j-i <= t.maxWordLen; j++ {
+			freq, exist := t.freqs[text[offsets[i]:offsets[j]]]
+			if !exist {
+				if j > i+1 {
+					continue
+				}
+				// 不在词典中的单字
+				freq = 1
+			}
+			if score := math.Log(freq) - logTotal + best[j]; score > best[i] {
+				best[i], next[i] = score, j
+			}
+		}
+	}
+
+	for i := 0; i < n; i = next[i] {
+		tokens = append(tokens, &Token{
+			Term:     text[offsets[i]:offsets[next[i]]],
+			Position: len(tokens),
+			Start:    offsets[i],
+			End:      offsets[next[i]],
+		})
+	}
+	return tokens
+}
@@ -0,0 +1,277 @@
+package analysis
+
+import (
+	"Research/etc"
+	"Research/types/doc"
+	"Research/types/term_query"
+	"fmt"
+)
+
+// 内置分析器
+const (
+	STANDARD   = "standard"   // 标准分词+小写
+	WHITESPACE = "whitespace" // 空白分词
+	CHINESE    = "chinese"    // 中文分词+小写，不带词典，每个汉字单独成词
+	ENGLISH    = "english"    // 标准分词+小写+停用词+词干
+)
+
+// 创建内置分析器，名称不存在时返回nil
+func NewBuiltinAnalyzer(name string) *Analyzer {
+	switch name {
+	case STANDARD:
+		return NewAnalyzer(NewStandardTokenizer()).WithTokenFilters(NewLowercaseFilter())
+	case WHITESPACE:
+		return NewAnalyzer(NewWhitespaceTokenizer())
+	case CHINESE:
+		return NewAnalyzer(NewChineseTokenizer()).WithTokenFilters(NewLowercaseFilter())
+	case ENGLISH:
+		return NewAnalyzer(NewStandardTokenizer()).WithTokenFilters(NewLowercaseFilter(), NewStopFilter(), NewStemmerFilter())
+	}
+	return nil
+}
+
+// 根据配置创建分析器
+func NewAnalyzerFromConfig(conf *etc.Analyzer) (*Analyzer, error) {
+	var tokenizer ITokenizer
+	switch conf.Tokenizer {
+	case "", "standard":
+		tokenizer = NewStandardTokenizer()
+	case "whitespace":
+		tokenizer = NewWhitespaceTokenizer()
+	case "chinese":
+		chinese := NewChineseTokenizer()
+		if len(conf.Dict) > 0 {
+			if _, err := chinese.WithDict(conf.Dict); err != nil {
+				return nil, err
+			}
+		}
+		tokenizer = chinese
+	default:
+		return nil, fmt.Errorf("unknown tokenizer %s", conf.Tokenizer)
+	}
+	analyzer := NewAnalyzer(tokenizer)
+
+	for _, name := range conf.CharFilters {
+		switch name {
+		case "html":
+			analyzer.WithCharFilters(NewHtmlStripCharFilter())
+		default:
+			return nil, fmt.Errorf("unknown char filter %s", name)
+		}
+	}
+
+	for _, name := range conf.TokenFilters {
+		switch name {
+		case "lowercase":
+			analyzer.WithTokenFilters(NewLowercaseFilter())
+		case "stop":
+			var stopwords []string
+			if len(conf.Stopwords) > 0 {
+				var err error
+				if stopwords, err = loadStopwords(conf.Stopwords); err != nil {
+					return nil, err
+				}
+			}
+			analyzer.WithTokenFilters(NewStopFilter(stopwords...))
+		case "synonym":
+			if len(conf.Synonyms) == 0 {
+				return nil, fmt.Errorf("synonym filter needs synonyms file")
+			}
+			synonyms, err := loadSynonyms(conf.Synonyms)
+			if err != nil {
+				return nil, err
+			}
+			analyzer.WithTokenFilters(synonyms)
+		case "stemmer":
+			analyzer.WithTokenFilters(NewStemmerFilter())
+		default:
+			return nil, fmt.Errorf("unknown token filter %s", name)
+		}
+	}
+	return analyzer, nil
+}
+
+// 按field选择分析器，没有配置的field使用默认分析器
+type FieldAnalyzer struct {
+	defaultAnalyzer *Analyzer
+	fields          map[string]*Analyzer
+}
+
+func NewFieldAnalyzer(conf *etc.Analysis) (*FieldAnalyzer, error) {
+	// 先创建所有用到的分析器，同名的分析器只创建一次
+	analyzers := make(map[string]*Analyzer)
+	get := func(name string) (*Analyzer, error) {
+		if analyzer, exist := analyzers[name]; exist {
+			return analyzer, nil
+		}
+		analyzer := NewBuiltinAnalyzer(name)
+		if analyzer == nil {
+			custom, exist := conf.Analyzers[name]
+			if !exist {
+				return nil, fmt.Errorf("unknown analyzer %s", name)
+			}
+			var err error
+			if analyzer, err = NewAnalyzerFromConfig(&custom); err != nil {
+				return nil, fmt.Errorf("create analyzer %s failed: %w", name, err)
+			}
+		}
+		analyzers[name] = analyzer
+		return analyzer, nil
+	}
+
+	f := &FieldAnalyzer{fields: make(map[string]*Analyzer, len(conf.Fields))}
+	if len(conf.Default) > 0 {
+		analyzer, err := get(conf.Default)
+		if err != nil {
+			return nil, err
+		}
+		f.defaultAnalyzer = analyzer
+	}
+	for field, name := range conf.Fields {
+		analyzer, err := get(name)
+		if err != nil {
+			return nil, err
+		}
+		f.fields[field] = analyzer
+	}
+	return f, nil
+}
+
+// 返回field使用的分析器，不需要分析时返回nil
+func (f *FieldAnalyzer) Get(field string) *Analyzer {
+	if analyzer, exist := f.fields[field]; exist {
+		return analyzer
+	}
+	return f.defaultAnalyzer
+}
+
+// 分析文档的原始文本，生成带位置的关键词，同一field的多段文本位置连续
+func (f *FieldAnalyzer) Keywords(texts []*doc.TextField) []*doc.KeyWord {
+	keywords := make([]*doc.KeyWord, 0)
+	nextPosition := make(map[string]int) // 每个field下一段文本的起始位置
+	for _, text := range texts {
+		analyzer := f.Get(text.Field)
+		if analyzer == nil {
+			continue
+		}
+		base := nextPosition[text.Field]
+		for _, token := range analyzer.Analyze(text.Text) {
+			position := base + token.Position
+			keywords = append(keywords, &doc.KeyWord{Field: text.Field, Word: token.Term, Positions: []uint32{uint32(position)}})
+			if position >= nextPosition[text.Field] {
+				nextPosition[text.Field] = position + 1
+			}
+		}
+	}
+	return keywords
+}
+
+// 用和文档相同的分析器处理查询中的关键词和短语，返回新的查询，不修改q
+func (f *FieldAnalyzer) AnalyzeQuery(q *term_query.TermQuery) *term_query.TermQuery {
+	if res := f.analyzeQuery(q); res != nil {
+		return res
+	}
+	// 所有词都被过滤掉了，返回没有结果的查询
+	return &term_query.TermQuery{}
+}
+
+// 分析后没有词的条件返回nil
+func (f *FieldAnalyzer) analyzeQuery(q *term_query.TermQuery) *term_query.TermQuery {
+	if q == nil {
+		return nil
+	}
+	if q.Keyword != nil {
+		return f.analyzeKeyword(q)
+	}
+	if q.Phrase != nil {
+		return f.analyzePhrase(q)
+	}
+	if q.MultiTerm != nil || q.Range != nil {
+		return q
+	}
+
+	res := &term_query.TermQuery{}
+	for _, val := range q.Must {
+		if analyzed := f.analyzeQuery(val); analyzed != nil {
+			res.Must = append(res.Must, analyzed)
+		}
+	}
+	for _, val := range q.Should {
+		if analyzed := f.analyzeQuery(val); analyzed != nil {
+			res.Should = append(res.Should, analyzed)
+		}
+	}
+	if len(res.Must) == 0 && len(res.Should) == 0 {
+		return nil
+	}
+	for _, val := range q.MustNot {
+		if analyzed := f.analyzeQuery(val); analyzed != nil {
+			res.MustNot = append(res.MustNot, analyzed)
+		}
+	}
+	return res
+}
+
+// 关键词分成多个词时，不同位置的词求交集，同一位置的同义词求并集
+func (f *FieldAnalyzer) analyzeKeyword(q *term_query.TermQuery) *term_query.TermQuery {
+	field := q.Keyword.Field
+	analyzer := f.Get(field)
+	if analyzer == nil {
+		return q
+	}
+	tokens := analyzer.Analyze(q.Keyword.Word)
+	if len(tokens) == 0 {
+		return nil
+	}
+	groups := make([]*term_query.TermQuery, 0, len(tokens))
+	for i := 0; i < len(tokens); {
+		j := i
+		should := make([]*term_query.TermQuery, 0, 1)
+		for ; j < len(tokens) && tokens[j].Position == tokens[i].Position; j++ {
+			should = append(should, term_query.NewTermQuery(field, tokens[j].Term))
+		}
+		if len(should) == 1 {
+			groups = append(groups, should[0])
+		} else {
+			groups = append(groups, &term_query.TermQuery{Should: should})
+		}
+		i = j
+	}
+	if len(groups) == 1 {
+		return groups[0]
+	}
+	return &term_query.TermQuery{Must: groups}
+}
+
+// 短语中的每个位置取第一个词，被停用词过滤掉的位置计入slop
+func (f *FieldAnalyzer) analyzePhrase(q *term_query.TermQuery) *term_query.TermQuery {
+	phrase := q.Phrase
+	analyzer := f.Get(phrase.Field)
+	if analyzer == nil {
+		return q
+	}
+	text := ""
+	for i, word := range phrase.Words {
+		if i > 0 {
+			text += " "
+		}
+		text += word
+	}
+	tokens := analyzer.Analyze(text)
+	if len(tokens) == 0 {
+		return nil
+	}
+	words := make([]string, 0, len(tokens))
+	lastPosition := -1
+	for _, token := range tokens {
+		if token.Position != lastPosition {
+			words = append(words, token.Term)
+			lastPosition = token.Position
+		}
+	}
+	if len(words) == 1 {
+		return term_query.NewTermQuery(phrase.Field, words[0])
+	}
+	gaps := tokens[len(tokens)-1].Position - tokens[0].Position + 1 - len(words)
+	return term_query.NewPhraseQuery(phrase.Field, int(phrase.Slop)+gaps, words...)
+}
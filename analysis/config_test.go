@@ -0,0 +1,136 @@
+package analysis
+
+import (
+	"Research/etc"
+	"Research/types/doc"
+	"Research/types/term_query"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// title使用english，body使用带同义词quick,fast的自定义分析器，其他field不分析
+func newTestFieldAnalyzer(t *testing.T) *FieldAnalyzer {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "syn.txt")
+	if err := os.WriteFile(path, []byte("quick, fast\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := NewFieldAnalyzer(&etc.Analysis{
+		Fields: map[string]string{"title": ENGLISH, "body": "syn"},
+		Analyzers: map[string]etc.Analyzer{
+			"syn": {TokenFilters: []string{"lowercase", "stop", "synonym"}, Synonyms: path},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// 把表达式中的分隔符换成:，方便对比
+func expr(q *term_query.TermQuery) string {
+	return strings.ReplaceAll(q.ToString(), "\001", ":")
+}
+
+func keyword(field, word string) *term_query.TermQuery {
+	return term_query.NewTermQuery(field, word)
+}
+
+func phrase(field string, slop int, words ...string) *term_query.TermQuery {
+	return term_query.NewPhraseQuery(field, slop, words...)
+}
+
+func TestAnalyzeQuery(t *testing.T) {
+	cases := []struct {
+		name  string
+		query *term_query.TermQuery
+		want  string // 为空表示没有结果的查询
+	}{
+		{"keyword", keyword("title", "Running"), "title:run"},
+		// 分成多个词时不同位置求交集
+		{"keyword words", keyword("title", "The Quick Foxes"), "(title:quick&title:fox)"},
+		{"keyword stopword", keyword("title", "the"), ""},
+		// 同一位置的同义词求并集
+		{"keyword synonym", keyword("body", "Quick"), "(body:quick|body:fast)"},
+		{"keyword synonym words", keyword("body", "quick fox"), "((body:quick|body:fast)&body:fox)"},
+		{"keyword not analyzed", keyword("tag", "Go Lang"), "tag:Go Lang"},
+		// 开头的停用词不计入slop
+		{"phrase leading stopword", phrase("title", 0, "the", "quick", "fox"), `title:"quick fox"`},
+		// 中间被删除的停用词位置计入slop
+		{"phrase inner stopword", phrase("title", 1, "quick", "the", "fox"), `title:"quick fox"~2`},
+		{"phrase inner stopwords", phrase("title", 0, "quick", "in the", "fox"), `title:"quick fox"~2`},
+		// 同一位置只取第一个词
+		{"phrase synonym", phrase("body", 0, "quick", "fox"), `body:"quick fox"`},
+		{"phrase one word", phrase("title", 3, "the", "Foxes"), "title:fox"},
+		{"phrase stopwords", phrase("title", 0, "the", "a"), ""},
+		{"phrase not analyzed", phrase("tag", 1, "Go", "Lang"), `tag:"Go Lang"~1`},
+		{"multi term", term_query.NewPrefixQuery("title", "Qui"), "title:Qui*"},
+		// 被过滤掉的条件从Must、Should、MustNot中去掉
+		{"should", keyword("title", "the").Or(keyword("title", "Foxes")), "title:fox"},
+		{"nested", &term_query.TermQuery{
+			Must:    []*term_query.TermQuery{keyword("title", "the"), keyword("title", "Running").Or(keyword("body", "quick"))},
+			MustNot: []*term_query.TermQuery{keyword("title", "the"), keyword("title", "Cats")},
+		}, "((title:run|(body:quick|body:fast))&!title:cat)"},
+		// 只剩下MustNot时没有结果
+		{"only must not", keyword("title", "the").Not(keyword("title", "fox")), ""},
+	}
+	f := newTestFieldAnalyzer(t)
+	for _, c := range cases {
+		before := expr(c.query)
+		res := f.AnalyzeQuery(c.query)
+		if c.want == "" {
+			if !res.Empty() {
+				t.Errorf("%s: got %q, want empty query", c.name, expr(res))
+			}
+		} else if got := expr(res); got != c.want {
+			t.Errorf("%s: got %q, want %q", c.name, got, c.want)
+		}
+		if after := expr(c.query); after != before {
+			t.Errorf("%s: query is modified from %q to %q", c.name, before, after)
+		}
+	}
+}
+
+// 同一field的多段文本位置连续，停用词留下的空位保留
+func TestFieldAnalyzerKeywords(t *testing.T) {
+	f := newTestFieldAnalyzer(t)
+	keywords := f.Keywords([]*doc.TextField{
+		{Field: "title", Text: "The Quick Foxes"},
+		{Field: "body", Text: "quick"},
+		{Field: "title", Text: "the Cats"},
+		{Field: "tag", Text: "not analyzed"},
+	})
+	got := make([]string, 0, len(keywords))
+	for _, keyword := range keywords {
+		if len(keyword.Positions) != 1 {
+			t.Fatalf("%s: got %d positions", keyword.Word, len(keyword.Positions))
+		}
+		got = append(got, fmt.Sprintf("%s:%s@%d", keyword.Field, keyword.Word, keyword.Positions[0]))
+	}
+	want := "title:quick@1 title:fox@2 body:quick@0 body:fast@0 title:cat@4"
+	if strings.Join(got, " ") != want {
+		t.Errorf("got %q, want %q", strings.Join(got, " "), want)
+	}
+}
+
+func TestAnalyzerConfigErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		conf etc.Analysis
+	}{
+		{"unknown analyzer", etc.Analysis{Default: "none"}},
+		{"unknown tokenizer", etc.Analysis{Fields: map[string]string{"a": "x"}, Analyzers: map[string]etc.Analyzer{"x": {Tokenizer: "none"}}}},
+		{"unknown char filter", etc.Analysis{Fields: map[string]string{"a": "x"}, Analyzers: map[string]etc.Analyzer{"x": {CharFilters: []string{"none"}}}}},
+		{"unknown token filter", etc.Analysis{Fields: map[string]string{"a": "x"}, Analyzers: map[string]etc.Analyzer{"x": {TokenFilters: []string{"none"}}}}},
+		{"synonym without file", etc.Analysis{Fields: map[string]string{"a": "x"}, Analyzers: map[string]etc.Analyzer{"x": {TokenFilters: []string{"synonym"}}}}},
+		{"missing dict", etc.Analysis{Fields: map[string]string{"a": "x"}, Analyzers: map[string]etc.Analyzer{"x": {Tokenizer: "chinese", Dict: "/nonexistent/dict.txt"}}}},
+	}
+	for _, c := range cases {
+		if _, err := NewFieldAnalyzer(&c.conf); err == nil {
+			t.Errorf("%s: want error", c.name)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package analysis
+
+import "strings"
+
+// Porter词干提取算法，只处理由小写英文字母组成的词，其他词原样返回
+func porterStem(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+	for i := 0; i < len(word); i++ {
+		if word[i] < 'a' || word[i] > 'z' {
+			return word
+		}
+	}
+	w := []byte(word)
+	w = stemStep1a(w)
+	w = stemStep1b(w)
+	w = stemStep1c(w)
+	w = stemReplace(w, step2Rules, 0)
+	w = stemReplace(w, step3Rules, 0)
+	w = stemStep4(w)
+	w = stemStep5(w)
+	return string(w)
+}
+
+// 第i个字母是否是辅音
+func isConsonant(w []byte, i int) bool {
+	switch w[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		return i == 0 || !isConsonant(w, i-1)
+	}
+	return true
+}
+
+// 词的度量，即[C](VC){m}[V]中的m
+func measure(w []byte) int {
+	n, i, m := len(w), 0, 0
+	for i < n && isConsonant(w, i) {
+		i++
+	}
+	for i < n {
+		for i < n && !isConsonant(w, i) {
+			i++
+		}
+		if i >= n {
+			break
+		}
+		for i < n && isConsonant(w, i) {
+			i++
+		}
+		m++
+	}
+	return m
+}
+
+func containsVowel(w []byte) bool {
+	for i := range w {
+		if !isConsonant(w, i) {
+			return true
+		}
+	}
+	return false
+}
+
+// 以两个相同的辅音结尾
+func endsDoubleConsonant(w []byte) bool {
+	n := len(w)
+	return n >= 2 && w[n-1] == w[n-2] && isConsonant(w, n-1)
+}
+
+// 以辅音-元音-辅音结尾，且最后一个辅音不是w、x、y
+func endsCVC(w []byte) bool {
+	n := len(w)
+	if n < 3 || !isConsonant(w, n-3) || isConsonant(w, n-2) || !isConsonant(w, n-1) {
+		return false
+	}
+	return w[n-1] != 'w' && w[n-1] != 'x' && w[n-1] != 'y'
+}
+
+func hasSuffix(w []byte, suffix string) bool {
+	return strings.HasSuffix(string(w), suffix)
+}
+
+func stemStep1a(w []byte) []byte {
+	switch {
+	case hasSuffix(w, "sses"), hasSuffix(w, "ies"):
+		return w[:len(w)-2]
+	case hasSuffix(w, "ss"):
+		return w
+	case hasSuffix(w, "s"):
+		return w[:len(w)-1]
+	}
+	return w
+}
+
+func stemStep1b(w []byte) []byte {
+	if hasSuffix(w, "eed") {
+		if measure(w[:len(w)-3]) > 0 {
+			return w[:len(w)-1]
+		}
+		return w
+	}
+	var stem []byte
+	if hasSuffix(w, "ed") && containsVowel(w[:len(w)-2]) {
+		stem = w[:len(w)-2]
+	} else if hasSuffix(w, "ing") && containsVowel(w[:len(w)-3]) {
+		stem = w[:len(w)-3]
+	} else {
+		return w
+	}
+	switch {
+	case hasSuffix(stem, "at"), hasSuffix(stem, "bl"), hasSuffix(stem, "iz"):
+		return append(stem, 'e')
+	case endsDoubleConsonant(stem):
+		if last := stem[len(stem)-1]; last != 'l' && last != 's' && last != 'z' {
+			return stem[:len(stem)-1]
+		}
+	case measure(stem) == 1 && endsCVC(stem):
+		return append(stem, 'e')
+	}
+	return stem
+}
+
+func stemStep1c(w []byte) []byte {
+	if hasSuffix(w, "y") && containsVowel(w[:len(w)-1]) {
+		w[len(w)-1] = 'i'
+	}
+	return w
+}
+
+// 后缀替换规则，同一步中较长的后缀排在前面
+type stemRule struct {
+	suffix, replacement string
+}
+
+var step2Rules = []stemRule{
+	{"ational", "ate"}, {"tional", "tion"}, {"enci", "ence"}, {"anci", "ance"}, {"izer", "ize"},
+	{"abli", "able"}, {"alli", "al"}, {"entli", "ent"}, {"eli", "e"}, {"ousli", "ous"},
+	{"ization", "ize"}, {"ation", "ate"}, {"ator", "ate"}, {"alism", "al"}, {"iveness", "ive"},
+	{"fulness", "ful"}, {"ousness", "ous"}, {"aliti", "al"}, {"iviti", "ive"}, {"biliti", "ble"},
+}
+
+var step3Rules = []stemRule{
+	{"icate", "ic"}, {"ative", ""}, {"alize", "al"}, {"iciti", "ic"}, {"ical", "ic"}, {"ful", ""}, {"ness", ""},
+}
+
+// 匹配到第一个后缀后，词干的度量大于minMeasure才替换
+func stemReplace(w []byte, rules []stemRule, minMeasure int) []byte {
+	for _, rule := range rules {
+		if hasSuffix(w, rule.suffix) {
+			stem := w[:len(w)-len(rule.suffix)]
+			if measure(stem) > minMeasure {
+				return append(stem, rule.replacement...)
+			}
+			return w
+		}
+	}
+	return w
+}
+
+var step4Suffixes = []string{
+	"al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement", "ment", "ent",
+	"ion", "ou", "ism", "ate", "iti", "ous", "ive", "ize",
+}
+
+func stemStep4(w []byte) []byte {
+	for _, suffix := range step4Suffixes {
+		if !hasSuffix(w, suffix) {
+			continue
+		}
+		stem := w[:len(w)-len(suffix)]
+		if suffix == "ion" && !(hasSuffix(stem, "s") || hasSuffix(stem, "t")) {
+			continue
+		}
+		if measure(stem) > 1 {
+			return stem
+		}
+		return w
+	}
+	return w
+}
+
+func stemStep5(w []byte) []byte {
+	if hasSuffix(w, "e") {
+		stem := w[:len(w)-1]
+		if m := measure(stem); m > 1 || m == 1 && !endsCVC(stem) {
+			w = stem
+		}
+	}
+	if measure(w) > 1 && endsDoubleConsonant(w) && w[len(w)-1] == 'l' {
+		w = w[:len(w)-1]
+	}
+	return w
+}
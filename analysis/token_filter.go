@@ -0,0 +1,159 @@
+package analysis
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// 转小写
+type LowercaseFilter struct{}
+
+func NewLowercaseFilter() *LowercaseFilter {
+	return &LowercaseFilter{}
+}
+
+func (f *LowercaseFilter) Filter(tokens []*Token) []*Token {
+	for _, token := range tokens {
+		token.Term = strings.ToLower(token.Term)
+	}
+	return tokens
+}
+
+// 英文默认停用词
+var englishStopwords = []string{
+	"a", "an", "and", "are", "as", "at", "be", "but", "by", "for", "if", "in", "into", "is", "it",
+	"no", "not", "of", "on", "or", "such", "that", "the", "their", "then", "there", "these",
+	"they", "this", "to", "was", "will", "with",
+}
+
+// 去掉停用词，保留其他词的位置
+type StopFilter struct {
+	stopwords map[string]struct{}
+}
+
+// 不传停用词时使用英文默认停用词
+func NewStopFilter(stopwords ...string) *StopFilter {
+	if len(stopwords) == 0 {
+		stopwords = englishStopwords
+	}
+	f := &StopFilter{stopwords: make(map[string]struct{}, len(stopwords))}
+	for _, word := range stopwords {
+		f.stopwords[word] = struct{}{}
+	}
+	return f
+}
+
+func (f *StopFilter) Filter(tokens []*Token) []*Token {
+	res := tokens[:0]
+	for _, token := range tokens {
+		if _, stop := f.stopwords[token.Term]; !stop {
+			res = append(res, token)
+		}
+	}
+	return res
+}
+
+// 同义词，同义词和原词在同一个位置
+type SynonymFilter struct {
+	synonyms map[string][]string
+}
+
+func NewSynonymFilter() *SynonymFilter {
+	return &SynonymFilter{synonyms: make(map[string][]string)}
+}
+
+// 添加一组互为同义词的词
+func (f *SynonymFilter) WithSynonyms(words ...string) *SynonymFilter {
+	for _, word := range words {
+		for _, synonym := range words {
+			if synonym != word {
+				f.synonyms[word] = append(f.synonyms[word], synonym)
+			}
+		}
+	}
+	return f
+}
+
+// 添加单向的同义词，from出现时同时生成to
+func (f *SynonymFilter) WithMapping(from string, to ...string) *SynonymFilter {
+	f.synonyms[from] = append(f.synonyms[from], to...)
+	return f
+}
+
+func (f *SynonymFilter) Filter(tokens []*Token) []*Token {
+	res := make([]*Token, 0, len(tokens))
+	for _, token := range tokens {
+		res = append(res, token)
+		for _, synonym := range f.synonyms[token.Term] {
+			res = append(res, &Token{Term: synonym, Position: token.Position, Start: token.Start, End: token.End})
+		}
+	}
+	return res
+}
+
+// 英文词干提取，使用Porter算法
+type StemmerFilter struct{}
+
+func NewStemmerFilter() *StemmerFilter {
+	return &StemmerFilter{}
+}
+
+func (f *StemmerFilter) Filter(tokens []*Token) []*Token {
+	for _, token := range tokens {
+		token.Term = porterStem(token.Term)
+	}
+	return tokens
+}
+
+// 读取停用词文件，每行一个词
+func loadStopwords(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	words := make([]string, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if word := strings.TrimSpace(scanner.Text()); len(word) > 0 && !strings.HasPrefix(word, "#") {
+			words = append(words, word)
+		}
+	}
+	return words, scanner.Err()
+}
+
+// 读取同义词文件，每行一组
+// a, b, c 表示互为同义词；a, b => c, d 表示a和b出现时生成c和d
+func loadSynonyms(path string) (*SynonymFilter, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	split := func(s string) []string {
+		words := make([]string, 0)
+		for _, word := range strings.Split(s, ",") {
+			if word = strings.TrimSpace(word); len(word) > 0 {
+				words = append(words, word)
+			}
+		}
+		return words
+	}
+	f := NewSynonymFilter()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if from, to, found := strings.Cut(line, "=>"); found {
+			for _, word := range split(from) {
+				f.WithMapping(word, split(to)...)
+			}
+		} else {
+			f.WithSynonyms(split(line)...)
+		}
+	}
+	return f, scanner.Err()
+}
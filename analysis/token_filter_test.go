@@ -0,0 +1,101 @@
+package analysis
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// 用空白分词器分词后再经过过滤器
+func filterString(text string, filters ...ITokenFilter) string {
+	return tokenString(NewAnalyzer(NewWhitespaceTokenizer()).WithTokenFilters(filters...).Analyze(text))
+}
+
+func TestTokenFilters(t *testing.T) {
+	synonyms := NewSynonymFilter().
+		WithSynonyms("quick", "fast", "rapid").
+		WithMapping("nyc", "new_york")
+	cases := []struct {
+		name    string
+		text    string
+		filters []ITokenFilter
+		want    string
+	}{
+		{"lowercase", "Hello WORLD ÄÖ", []ITokenFilter{NewLowercaseFilter()}, "hello@0 world@1 äö@2"},
+		// 停用词删除后留下空位，后面的词位置不变
+		{"stop gaps", "the quick fox is in the box", []ITokenFilter{NewStopFilter()}, "quick@1 fox@2 box@6"},
+		{"stop all", "the a an", []ITokenFilter{NewStopFilter()}, ""},
+		{"stop custom", "the quick fox", []ITokenFilter{NewStopFilter("fox")}, "the@0 quick@1"},
+		// 停用词区分大小写，需要先转小写
+		{"stop case", "The fox", []ITokenFilter{NewStopFilter()}, "The@0 fox@1"},
+		{"lowercase then stop", "The fox", []ITokenFilter{NewLowercaseFilter(), NewStopFilter()}, "fox@1"},
+		// 同义词和原词在同一位置，原词在前
+		{"synonym group", "quick fox", []ITokenFilter{synonyms}, "quick@0 fast@0 rapid@0 fox@1"},
+		{"synonym reverse", "rapid fox", []ITokenFilter{synonyms}, "rapid@0 quick@0 fast@0 fox@1"},
+		// 单向映射只从from生成to
+		{"mapping", "nyc new_york", []ITokenFilter{synonyms}, "nyc@0 new_york@0 new_york@1"},
+		{"synonym after stop", "the quick", []ITokenFilter{NewStopFilter(), synonyms}, "quick@1 fast@1 rapid@1"},
+		{"stemmer", "running cats caresses", []ITokenFilter{NewStemmerFilter()}, "run@0 cat@1 caress@2"},
+		{"stemmer keeps non ascii", "Running café", []ITokenFilter{NewStemmerFilter()}, "Running@0 café@1"},
+	}
+	for _, c := range cases {
+		if got := filterString(c.text, c.filters...); got != c.want {
+			t.Errorf("%s: got %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestCharFilters(t *testing.T) {
+	cases := []struct {
+		name   string
+		filter ICharFilter
+		text   string
+		want   string
+	}{
+		// 标签换成空格，前后的词不会连在一起
+		{"html", NewHtmlStripCharFilter(), "<p>hello</p><b>world</b>", " hello  world "},
+		{"html entity", NewHtmlStripCharFilter(), "a &amp; b&lt;c", "a & b<c"},
+		{"html comment", NewHtmlStripCharFilter(), "a<!-- <b>x</b> -->b", "a b"},
+		{"mapping", NewMappingCharFilter(map[string]string{"ａ": "a", "１": "1"}), "ａ１b", "a1b"},
+	}
+	for _, c := range cases {
+		if got := c.filter.Filter(c.text); got != c.want {
+			t.Errorf("%s: got %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestLoadStopwordsAndSynonyms(t *testing.T) {
+	dir := t.TempDir()
+	stopPath := filepath.Join(dir, "stop.txt")
+	synPath := filepath.Join(dir, "syn.txt")
+	if err := os.WriteFile(stopPath, []byte("# comment\nfoo\n\n  bar  \n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(synPath, []byte("# comment\nquick, fast\n\nnyc, ny => new_york\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stopwords, err := loadStopwords(stopPath)
+	if err != nil || len(stopwords) != 2 || stopwords[0] != "foo" || stopwords[1] != "bar" {
+		t.Errorf("stopwords: got %q %v", stopwords, err)
+	}
+	synonyms, err := loadSynonyms(synPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		text, want string
+	}{
+		{"fast", "fast@0 quick@0"},
+		{"ny", "ny@0 new_york@0"},
+		{"new_york", "new_york@0"},
+	}
+	for _, c := range cases {
+		if got := filterString(c.text, synonyms); got != c.want {
+			t.Errorf("%s: got %q, want %q", c.text, got, c.want)
+		}
+	}
+	if _, err = loadSynonyms(filepath.Join(dir, "missing.txt")); err == nil {
+		t.Error("missing synonyms file should fail")
+	}
+}
@@ -0,0 +1,71 @@
+package analysis
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// 标准分词器，连续的字母和数字是一个词，每个汉字单独成词，其他字符作为分隔符
+type StandardTokenizer struct{}
+
+func NewStandardTokenizer() *StandardTokenizer {
+	return &StandardTokenizer{}
+}
+
+func (t *StandardTokenizer) Tokenize(text string) []*Token {
+	tokens := make([]*Token, 0)
+	start := -1 // 当前词的起始位置，-1表示不在词中
+	emit := func(end int) {
+		if start >= 0 {
+			tokens = append(tokens, &Token{Term: text[start:end], Position: len(tokens), Start: start, End: end})
+			start = -1
+		}
+	}
+	for i, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			emit(i)
+			end := i + utf8.RuneLen(r)
+			tokens = append(tokens, &Token{Term: text[i:end], Position: len(tokens), Start: i, End: end})
+		case isWordRune(r):
+			if start < 0 {
+				start = i
+			}
+		default:
+			emit(i)
+		}
+	}
+	emit(len(text))
+	return tokens
+}
+
+// 空白分词器，按空白字符切分
+type WhitespaceTokenizer struct{}
+
+func NewWhitespaceTokenizer() *WhitespaceTokenizer {
+	return &WhitespaceTokenizer{}
+}
+
+func (t *WhitespaceTokenizer) Tokenize(text string) []*Token {
+	tokens := make([]*Token, 0)
+	start := -1
+	for i, r := range text {
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				tokens = append(tokens, &Token{Term: text[start:i], Position: len(tokens), Start: start, End: i})
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, &Token{Term: text[start:], Position: len(tokens), Start: start, End: len(text)})
+	}
+	return tokens
+}
+
+// 组成词的字符，不包括汉字
+func isWordRune(r rune) bool {
+	return (unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)) && !unicode.Is(unicode.Han, r)
+}
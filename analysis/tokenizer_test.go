@@ -0,0 +1,129 @@
+package analysis
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// 把分词结果写成term@position，方便对比
+func tokenString(tokens []*Token) string {
+	res := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		res = append(res, fmt.Sprintf("%s@%d", token.Term, token.Position))
+	}
+	return strings.Join(res, " ")
+}
+
+// 偏移量要能从原文中取回词
+func checkOffsets(t *testing.T, text string, tokens []*Token) {
+	t.Helper()
+	for _, token := range tokens {
+		if text[token.Start:token.End] != token.Term {
+			t.Errorf("%q: offsets [%d,%d) give %q, want %q", text, token.Start, token.End, text[token.Start:token.End], token.Term)
+		}
+	}
+}
+
+func TestStandardTokenizer(t *testing.T) {
+	cases := []struct {
+		text, want string
+	}{
+		{"", ""},
+		{"Hello, World!", "Hello@0 World@1"},
+		{"go1.21 released", "go1@0 21@1 released@2"},
+		{"  spaces\tand\nlines ", "spaces@0 and@1 lines@2"},
+		{"中文abc分词", "中@0 文@1 abc@2 分@3 词@4"},
+		{"café naïve", "café@0 naïve@1"},
+		{"e-mail", "e@0 mail@1"},
+	}
+	tokenizer := NewStandardTokenizer()
+	for _, c := range cases {
+		tokens := tokenizer.Tokenize(c.text)
+		if got := tokenString(tokens); got != c.want {
+			t.Errorf("%q: got %q, want %q", c.text, got, c.want)
+		}
+		checkOffsets(t, c.text, tokens)
+	}
+}
+
+func TestWhitespaceTokenizer(t *testing.T) {
+	cases := []struct {
+		text, want string
+	}{
+		{"", ""},
+		{"   ", ""},
+		{"Hello, World!", "Hello,@0 World!@1"},
+		{"\ta  b\n", "a@0 b@1"},
+		{"中文 分词", "中文@0 分词@1"},
+	}
+	tokenizer := NewWhitespaceTokenizer()
+	for _, c := range cases {
+		tokens := tokenizer.Tokenize(c.text)
+		if got := tokenString(tokens); got != c.want {
+			t.Errorf("%q: got %q, want %q", c.text, got, c.want)
+		}
+		checkOffsets(t, c.text, tokens)
+	}
+}
+
+func TestChineseTokenizer(t *testing.T) {
+	withDict := NewChineseTokenizer().
+		WithWord("中国", 100).
+		WithWord("人民", 80).
+		WithWord("中国人", 10).
+		WithWord("银行", 50).
+		WithWord("人民银行", 200)
+	cases := []struct {
+		name      string
+		tokenizer *ChineseTokenizer
+		text      string
+		want      string
+	}{
+		// 没有词典时每个汉字单独成词
+		{"no dict", NewChineseTokenizer(), "中国人民", "中@0 国@1 人@2 民@3"},
+		// 中国/人民银行的概率高于中国人/民/银行
+		{"max probability", withDict, "中国人民银行", "中国@0 人民银行@1"},
+		{"unknown char", withDict, "中国的银行", "中国@0 的@1 银行@2"},
+		// 非汉字部分按标准分词器处理，位置连续
+		{"mixed", withDict, "Go语言和中国", "Go@0 语@1 言@2 和@3 中国@4"},
+		{"punctuation", withDict, "银行，中国。", "银行@0 中国@1"},
+		{"empty", withDict, "", ""},
+	}
+	for _, c := range cases {
+		tokens := c.tokenizer.Tokenize(c.text)
+		if got := tokenString(tokens); got != c.want {
+			t.Errorf("%s: got %q, want %q", c.name, got, c.want)
+		}
+		checkOffsets(t, c.text, tokens)
+	}
+}
+
+func TestChineseDict(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dict.txt")
+	// 词频可以省略，多余的列忽略
+	if err := os.WriteFile(path, []byte("中国 100 ns\n人民银行\n\n银行 50\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tokenizer, err := NewChineseTokenizer().WithDict(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := tokenizer.Tokenize("中国人民银行"); !slices.Equal(termsOf(got), []string{"中国", "人民银行"}) {
+		t.Errorf("got %q", tokenString(got))
+	}
+	if _, err = NewChineseTokenizer().WithDict(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("missing dict should fail")
+	}
+}
+
+func termsOf(tokens []*Token) []string {
+	terms := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		terms = append(terms, token.Term)
+	}
+	return terms
+}
@@ -25,6 +25,14 @@ type Config struct {
 	Limit Limit
 	// node节点配置
 	Server Server
+	// 预写日志配置
+	Wal Wal
+	// 文本分析配置
+	Analysis Analysis
+	// 索引schema文件，YAML或JSON格式，为空时不校验文档
+	Schema string `yaml:"schema"`
+	// 过期文档清理配置
+	Expire Expire
 }
 
 type Etcd struct {
@@ -50,18 +58,56 @@ type ServiceHub struct {
 
 // 正排索引配置
 type ForwardIndex struct {
-	Dbtype   string `yaml:"dbType"` // 使用数据库类型
-	Addr     string `yaml:"addr"`   // 数据库地址，redis是ip:端口号，;本地数据库，文件地址
-	Password string `yaml:"password"`
-	Dbno     string `yaml:"dbno"`
+	Dbtype       string `yaml:"dbType"` // 使用数据库类型
+	Addr         string `yaml:"addr"`   // 数据库地址，redis是ip:端口号，;本地数据库，文件地址
+	Password     string `yaml:"password"`
+	Dbno         string `yaml:"dbno"`
+	Bucket       string `yaml:"bucket"`       // bolt使用的bucket
+	SyncWrites   bool   `yaml:"syncWrites"`   // badger每次写入是否同步刷盘
+	MemTableSize int64  `yaml:"memTableSize"` // badger内存表大小，单位字节
+	GcInterval   int    `yaml:"gcInterval"`   // badger value log垃圾回收间隔，单位秒
+	Prefix       string `yaml:"prefix"`       // redis的key前缀
+	SkipPrefix   string `yaml:"-"`            // redis遍历时跳过Prefix之后以此开头的key，默认索引用来跳过命名索引的key
 }
 
+// 命名索引在redis中的key放在这个保留前缀下，业务id不能以"\x00research\x00"开头，所以不会与默认索引的文档冲突
+const RedisIndexPrefix = "\x00research\x00index:"
+
 // 倒排索引配置
 type ReverseIndex struct {
-	IndexType      int `yaml:"indexType"`      // 索引类型
+	IndexType      int `yaml:"indexType"`      // 索引类型，1是跳表(默认)，2是roaring bitmap
 	DocNumEstimate int `yaml:"docNumEstimate"` // 文档数量预估值
 }
 
+// 预写日志配置
+type Wal struct {
+	Path               string `yaml:"path"`               // 日志文件路径，为空时不开启
+	CheckpointInterval int    `yaml:"checkpointInterval"` // 检查点间隔，单位秒
+}
+
+// 过期文档清理配置
+type Expire struct {
+	SweepInterval int `yaml:"sweepInterval"` // 清理间隔，单位秒，0使用默认值，小于0不清理
+	BatchSize     int `yaml:"batchSize"`     // 每批删除的文档数，0使用默认值
+}
+
+// 文本分析配置
+type Analysis struct {
+	Default   string              `yaml:"default"`   // 默认分析器名称，为空时只分析Fields中配置的field
+	Fields    map[string]string   `yaml:"fields"`    // field使用的分析器名称
+	Analyzers map[string]Analyzer `yaml:"analyzers"` // 自定义分析器，名称不能和内置分析器重复
+}
+
+// 自定义分析器
+type Analyzer struct {
+	CharFilters  []string `yaml:"charFilters"`  // 字符过滤器：html
+	Tokenizer    string   `yaml:"tokenizer"`    // 分词器：standard(默认)、whitespace、chinese
+	TokenFilters []string `yaml:"tokenFilters"` // 词过滤器：lowercase、stop、synonym、stemmer
+	Stopwords    string   `yaml:"stopwords"`    // 停用词文件，为空时使用英文默认停用词
+	Synonyms     string   `yaml:"synonyms"`     // 同义词文件
+	Dict         string   `yaml:"dict"`         // 中文分词词典文件，为空时每个汉字单独成词
+}
+
 // 令牌桶配置
 type Limit struct {
 	Capacity int64   `yaml:"capacity"` // 令牌桶容量
@@ -114,9 +160,48 @@ func (c *Config) GetEtcdConfig() etcdv3.Config {
 }
 
 func (c *Config) GetDateDir() string {
-	if c.ForwardIndex.Dbtype == "redis" {
-		return c.ForwardIndex.Addr + "/" + c.ForwardIndex.Password + "/" + c.ForwardIndex.Dbno
+	return c.ForwardIndex.GetDateDir()
+}
+
+// 命名索引使用的正排索引配置，redis加上保留前缀和索引名，本地数据库在数据目录的同级目录下建立以索引命名的子目录
+func (f *ForwardIndex) Namespace(name string) ForwardIndex {
+	res := *f
+	if f.Dbtype == "redis" {
+		res.Prefix = f.Prefix + RedisIndexPrefix + name + ":"
+	} else if len(f.Addr) > 0 {
+		res.Addr = namespacePath(f.Addr, name)
+	}
+	return res
+}
+
+// 注册表中默认索引使用的正排索引配置，key前缀不变，已有的数据仍然可以读到
+// redis中命名索引的key也以默认索引的前缀开头，遍历默认索引时跳过；本地数据库每个索引是单独的文件，不需要改变
+func (f *ForwardIndex) DefaultNamespace() ForwardIndex {
+	res := *f
+	if f.Dbtype == "redis" {
+		res.SkipPrefix = RedisIndexPrefix
+	}
+	return res
+}
+
+// 命名索引使用的预写日志配置
+func (w *Wal) Namespace(name string) Wal {
+	res := *w
+	if len(w.Path) > 0 {
+		res.Path = namespacePath(w.Path, name)
+	}
+	return res
+}
+
+// dir/base 转换为 dir/name/base
+func namespacePath(path, name string) string {
+	return filepath.Join(filepath.Dir(path), name, filepath.Base(path))
+}
+
+func (f *ForwardIndex) GetDateDir() string {
+	if f.Dbtype == "redis" {
+		return f.Addr + "/" + f.Password + "/" + f.Dbno
 	} else {
-		return c.ForwardIndex.Addr
+		return f.Addr
 	}
 }
@@ -0,0 +1,32 @@
+package etc
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestForwardIndexNamespace(t *testing.T) {
+	redis := ForwardIndex{Dbtype: "redis", Addr: "127.0.0.1:6379", Prefix: "app:"}
+	def := redis.DefaultNamespace()
+	named := redis.Namespace("products")
+	// 默认索引保持原来的前缀，已有的数据不受影响
+	if def.Prefix != "app:" || def.SkipPrefix != RedisIndexPrefix {
+		t.Fatalf("default: prefix %q, skip %q", def.Prefix, def.SkipPrefix)
+	}
+	if named.Prefix != "app:"+RedisIndexPrefix+"products:" || len(named.SkipPrefix) > 0 {
+		t.Fatalf("named: prefix %q, skip %q", named.Prefix, named.SkipPrefix)
+	}
+	// 命名索引的key都在默认索引跳过的前缀下
+	if !strings.HasPrefix(named.Prefix+"doc1", def.Prefix+def.SkipPrefix) {
+		t.Errorf("named index key %q is not skipped by default index", named.Prefix)
+	}
+
+	local := ForwardIndex{Dbtype: "bolt", Addr: filepath.Join("data", "bolt", "db")}
+	if got := local.DefaultNamespace(); got != local {
+		t.Errorf("local default: got %+v, want %+v", got, local)
+	}
+	if got, want := local.Namespace("products").Addr, filepath.Join("data", "bolt", "products", "db"); got != want {
+		t.Errorf("local named addr: got %q, want %q", got, want)
+	}
+}
@@ -2,10 +2,12 @@ package hash
 
 import (
 	"Research/types/doc"
+	"Research/types/index"
 	"Research/types/term_query"
 	"Research/util"
 	"github.com/huandu/skiplist"
 	"hash/crc32"
+	"sort"
 	"strconv"
 )
 
@@ -59,15 +61,25 @@ func (h *HashService) DeleteDoc(Id string) int {
 	return 0
 }
 
-func (h *HashService) Search(query *term_query.TermQuery, onFlag *util.Bitmap, offFlag *util.Bitmap, orFlags []*util.Bitmap) []*doc.Document {
-	search := h.search(query, onFlag, offFlag, orFlags)
-	res := make([]*doc.Document, search.Len())
+// 按关键词到对应的Colony上查找，合并后按相关性得分降序，只保留TopK个
+func (h *HashService) Search(request *index.SearchRequest) *index.SearchResult {
+	search := h.search(request.Query, request)
+	if search == nil {
+		return &index.SearchResult{}
+	}
+	res := make([]*doc.Document, 0, search.Len())
 	font := search.Front()
 	for font != nil {
 		res = append(res, font.Value.(*doc.Document))
 		font = font.Next()
 	}
-	return res
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Score > res[j].Score
+	})
+	if request.TopK > 0 && len(res) > int(request.TopK) {
+		res = res[:request.TopK]
+	}
+	return &index.SearchResult{Results: res}
 }
 
 func (h *HashService) Count() int {
@@ -92,34 +104,79 @@ func (h *HashService) Close() error {
 	return nil
 }
 
-func (h *HashService) search(q *term_query.TermQuery, onFlag *util.Bitmap, offFlag *util.Bitmap, orFlags []*util.Bitmap) *skiplist.SkipList {
+// request提供过滤条件，q是当前要查找的查询表达式
+func (h *HashService) search(q *term_query.TermQuery, request *index.SearchRequest) *skiplist.SkipList {
+	res := h.searchPositive(q, request)
+	if res == nil || len(q.MustNot) == 0 {
+		return res
+	}
+	//4、mustNot关系，排除的关键词可能在其他Colony上，分别查找后求差集
+	excludes := make([]*skiplist.SkipList, 0, len(q.MustNot))
+	for _, val := range q.MustNot {
+		excludes = append(excludes, h.search(val, &index.SearchRequest{}))
+	}
+	return util.DifferenceOfSkipList(res, excludes...)
+}
+
+// 在一个Colony上查找叶子结点
+func (h *HashService) searchColony(colony *Colony, q *term_query.TermQuery, request *index.SearchRequest) *skiplist.SkipList {
+	res := skiplist.New(skiplist.Uint64)
+	// 每个关键词需要全部结果才能求交集并集，不限制TopK
+	search, err := colony.Sentinel.Search(&index.SearchRequest{
+		Query:   q,
+		OnFlag:  request.OnFlag,
+		OffFlag: request.OffFlag,
+		OrFlags: request.OrFlags,
+	})
+	if err != nil {
+		util.Log.Printf("search colony failed: %s", err)
+	}
+	for _, document := range search.Results {
+		if _, ok := h.DelId[document.Id]; ok {
+			// 懒删除
+			colony.DeleteDoc(strconv.FormatUint(document.IntId, 10))
+			continue
+		}
+		res.Set(document.Id, document)
+	}
+	return res
+}
+
+// 查找Keyword、Must、Should的结果
+func (h *HashService) searchPositive(q *term_query.TermQuery, request *index.SearchRequest) *skiplist.SkipList {
 	//根据查询表达式分三种情况
-	//1、存在关键字
-	if q.Keyword != nil {
-		res := skiplist.New(skiplist.Uint64)
-		key := q.Keyword.ToString()
+	//1、存在关键字或短语
+	if q.Keyword != nil || q.Phrase != nil {
+		var key string
+		if q.Keyword != nil {
+			key = q.Keyword.ToString()
+		} else if keys := q.Phrase.Keys(); len(keys) > 0 {
+			// 文档会完整地添加到每个关键词所在的Colony上，短语到第一个词所在的Colony上查找
+			key = keys[0]
+		}
 		//得到关键字的跳表
 		colony, err := h.GetColony(h.HashStingTo32Bit(key))
 		if err != nil {
 			return nil
 		}
-		search := colony.Sentinel.Search(q, onFlag, offFlag, orFlags)
-		for _, document := range search {
-			if _, ok := h.DelId[document.Id]; ok {
-				// 懒删除
-				colony.DeleteDoc(strconv.FormatUint(document.IntId, 10))
-				continue
-			}
-			res.Set(document.Id, document)
-		}
-		return res
+		return h.searchColony(colony, q, request)
+	}
+
+	//1.1、前缀、通配符、模糊查询展开的关键词分布在不同的Colony上，数值字段不参与哈希，都需要查找所有Colony
+	if q.MultiTerm != nil || q.Range != nil {
+		res := make([]*skiplist.SkipList, 0)
+		h.Nodes.Range(func(key, value any) bool {
+			res = append(res, h.searchColony(key.(*Colony), q, request))
+			return true
+		})
+		return util.UnionsetOfSkipList(res...)
 	}
 
 	//2、must关系
 	if len(q.Must) > 0 {
 		res := make([]*skiplist.SkipList, 0, len(q.Must))
 		for _, val := range q.Must {
-			resSkl := h.search(val, onFlag, offFlag, orFlags)
+			resSkl := h.search(val, request)
 			res = append(res, resSkl)
 		}
 		//must求交集
@@ -130,7 +187,7 @@ func (h *HashService) search(q *term_query.TermQuery, onFlag *util.Bitmap, offFl
 	if len(q.Should) > 0 {
 		res := make([]*skiplist.SkipList, 0, len(q.Should))
 		for _, val := range q.Should {
-			resSkl := h.search(val, onFlag, offFlag, orFlags)
+			resSkl := h.search(val, request)
 			res = append(res, resSkl)
 		}
 		//should求并集
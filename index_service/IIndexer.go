@@ -2,14 +2,20 @@ package index_service
 
 import (
 	"Research/types/doc"
-	"Research/types/term_query"
-	"Research/util"
+	"Research/types/index"
 )
 
 type IIndexer interface {
 	AddDoc(*doc.Document) (int, error)
+	AddDocIf(doc *doc.Document, cond *index.Condition) (int, error) //不满足条件时返回*ConflictError
+	AddDocs(docs []*doc.Document) *index.BulkAddResult              //结果与docs一一对应
+	UpdateDoc(request *index.UpdateDocRequest) (int, error)
 	DeleteDoc(docId string) int
-	Search(query *term_query.TermQuery, onFlag *util.Bitmap, offFlag *util.Bitmap, orFlags []*util.Bitmap) []*doc.Document
+	DeleteDocIf(docId string, cond *index.Condition) (int, error)     //不满足条件时返回*ConflictError
+	Search(request *index.SearchRequest) (*index.SearchResult, error) //参数错误或有节点失败时返回错误
+	GetDoc(docId string) *doc.Document                                //不存在时返回nil
+	MultiGetDoc(docIds []string) []*doc.Document                      //按docIds的顺序返回，不存在的文档不返回
 	Count() int
+	Suggest(request *index.SuggestRequest) *index.SuggestResult
 	Close() error
 }
@@ -2,6 +2,7 @@ package index_service
 
 import (
 	"Research/ServiceHub"
+	reverseindex "Research/internal/reverse_index"
 	"Research/types/doc"
 	"Research/types/index"
 	"Research/types/raft"
@@ -15,6 +16,9 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials/insecure"
+	"hash/fnv"
+	"io"
+	"sort"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -22,15 +26,25 @@ import (
 )
 
 type Sentinel struct {
-	ServiceHub.IServiceHub          // 从Hub上获取IndexServiceWorker集合。可能是直接访问ServiceHub，也可能是走代理
-	connPool               sync.Map // 与各个IndexServiceWorker建立的连接。把连接缓存起来，避免每次都重建连接
+	ServiceHub.IServiceHub           // 从Hub上获取IndexServiceWorker集合。可能是直接访问ServiceHub，也可能是走代理
+	connPool               *sync.Map // 与各个IndexServiceWorker建立的连接。把连接缓存起来，避免每次都重建连接
+	index                  string    // 操作的索引名称，为空表示默认索引
 }
 
 // 创建哨兵
 func NewSentinel(hub ServiceHub.IServiceHub) *Sentinel {
 	return &Sentinel{
 		IServiceHub: hub,
-		connPool:    sync.Map{},
+		connPool:    &sync.Map{},
+	}
+}
+
+// 返回操作指定索引的哨兵，与原哨兵共用注册中心和连接
+func (s *Sentinel) Index(name string) *Sentinel {
+	return &Sentinel{
+		IServiceHub: s.IServiceHub,
+		connPool:    s.connPool,
+		index:       name,
 	}
 }
 
@@ -41,7 +55,7 @@ func (s *Sentinel) GetGrpcConn(endpoint *ServiceHub.EndPoint) *grpc.ClientConn {
 		//1.1、判断连接是否可用
 		if conn.GetState() == connectivity.TransientFailure || conn.GetState() == connectivity.Shutdown {
 			// 连接不可用，关闭和删除连接
-			util.Log.Printf("connection status to endpoint %s is %s", endpoint, conn.GetState())
+			util.Log.Printf("connection status to endpoint %s is %s", endpoint.SelfAddr, conn.GetState())
 			conn.Close()
 			s.connPool.Delete(endpoint)
 		} else {
@@ -58,7 +72,7 @@ func (s *Sentinel) GetGrpcConn(endpoint *ServiceHub.EndPoint) *grpc.ClientConn {
 		grpc.WithTransportCredentials(insecure.NewCredentials()), // tls安全链接，传递了一个空证书
 		grpc.WithBlock()) // Dial是异步链接，设置Block会变为同步，（异步状态下ctx超时不会生效）
 	if err != nil {
-		util.Log.Printf("dial %s failed: %s", endpoint, err)
+		util.Log.Printf("dial %s failed: %s", endpoint.SelfAddr, err)
 		return nil
 	}
 
@@ -69,44 +83,206 @@ func (s *Sentinel) GetGrpcConn(endpoint *ServiceHub.EndPoint) *grpc.ClientConn {
 
 // 将文档添加到一个节点上
 func (s *Sentinel) AddDoc(document *doc.Document) (int, error) {
+	return s.AddDocIf(document, nil)
+}
+
+// 满足条件时向集群添加文档，不满足时返回*ConflictError，成功后document.Version是新的版本
+// 按业务id哈希选择节点，同一文档总是写到同一节点上，写入条件也在该节点上检查
+// 有条件时先查找持有该文档的节点，节点增减后文档可能还在原来的节点上
+func (s *Sentinel) AddDocIf(document *doc.Document, cond *index.Condition) (int, error) {
 	//1、获取服务器
-	endpoint := s.GetServiceEndpoint(INDEX_SERVICE)
+	var endpoint *ServiceHub.EndPoint
+	if cond != nil {
+		_, endpoint = s.locateDoc(document.Id)
+	}
+	if endpoint == nil {
+		endpoint = docEndpoint(s.GetServiceEndpoints(INDEX_SERVICE), document.Id)
+	}
 	if endpoint == nil {
 		return 0, fmt.Errorf("there is no alive index worker")
 	}
 	//2、获取grpc连接
 	conn := s.GetGrpcConn(endpoint)
 	if conn == nil {
-		return 0, fmt.Errorf("connect to worker %s failed", endpoint)
+		return 0, fmt.Errorf("connect to worker %s failed", endpoint.SelfAddr)
 	}
 	////3、创建客户端
 	//client := index.NewIndexServiceClient(conn)
 	////4、发送grpc请求
 	//affected, err := client.AddDoc(context.Background(), document)
-	resp, err := RaftClintRequest(conn, 1, document, nil, nil, nil, nil, nil)
-	affected := resp.(*index.AffectedCount)
+	resp, err := RaftClintRequest(conn, 1, &index.AddDocRequest{Index: s.index, Doc: document, Condition: cond}, nil, nil, nil, nil, nil, nil)
 	if err != nil {
 		return 0, err
 	}
-	util.Log.Printf("add %d doc to worker %s", affected.Count, endpoint)
+	affected := resp.(*index.AffectedCount)
+	if err = conflictOf(document.Id, affected); err != nil {
+		return 0, err
+	}
+	document.Version = affected.Version
+	util.Log.Printf("add %d doc to worker %s", affected.Count, endpoint.SelfAddr)
 	return int(affected.Count), nil
 
 }
 
+// 批量添加文档，每个文档按业务id哈希选择节点，发往同一节点的文档用一个流发送，结果与docs一一对应
+func (s *Sentinel) AddDocs(docs []*doc.Document) *index.BulkAddResult {
+	res := &index.BulkAddResult{Items: make([]*index.BulkItem, len(docs))}
+	alive := s.GetServiceEndpoints(INDEX_SERVICE)
+	endpoints := make(map[string]*ServiceHub.EndPoint)
+	batches := make(map[string][]int) // 节点地址 -> 发往该节点的文档下标
+	for i, document := range docs {
+		res.Items[i] = &index.BulkItem{Id: document.Id}
+		endpoint := docEndpoint(alive, document.Id)
+		if endpoint == nil {
+			res.Items[i].Error = "there is no alive index worker"
+			continue
+		}
+		endpoints[endpoint.SelfAddr] = endpoint
+		batches[endpoint.SelfAddr] = append(batches[endpoint.SelfAddr], i)
+	}
+
+	var count int32
+	wg := sync.WaitGroup{}
+	for addr, batch := range batches {
+		wg.Add(1)
+		go func(endpoint *ServiceHub.EndPoint, batch []int) {
+			defer wg.Done()
+			result, err := s.bulkAdd(endpoint, docs, batch)
+			if err != nil {
+				util.Log.Printf("bulk add %d docs to worker %s failed: %s", len(batch), endpoint.SelfAddr, err)
+				for _, i := range batch {
+					res.Items[i].Error = err.Error()
+				}
+				return
+			}
+			// 节点按发送顺序返回结果
+			for j, i := range batch {
+				if j < len(result.Items) {
+					res.Items[i] = result.Items[j]
+				}
+			}
+			atomic.AddInt32(&count, result.Count)
+			util.Log.Printf("bulk add %d doc to worker %s", result.Count, endpoint.SelfAddr)
+		}(endpoints[addr], batch)
+	}
+	wg.Wait()
+	res.Count = count
+	return res
+}
+
+// 按业务id选择写入的节点，每个节点与id一起哈希，取哈希值最大的节点(rendezvous hash)
+// 与节点顺序无关，增减节点时只有落在该节点上的文档会换节点
+func docEndpoint(endpoints []*ServiceHub.EndPoint, docId string) *ServiceHub.EndPoint {
+	id := fnv64(normalizeDocId(docId))
+	var owner *ServiceHub.EndPoint
+	var best uint64
+	for _, endpoint := range endpoints {
+		if sum := mix64(fnv64(endpoint.SelfAddr) ^ id); owner == nil || sum > best {
+			owner, best = endpoint, sum
+		}
+	}
+	return owner
+}
+
+func fnv64(s string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s))
+	return h.Sum64()
+}
+
+// 打散哈希值的各位，fnv对只差几个字符的地址和id区分度不够
+func mix64(x uint64) uint64 {
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
+
+// 用一个流把docs中下标为batch的文档发给节点
+func (s *Sentinel) bulkAdd(endpoint *ServiceHub.EndPoint, docs []*doc.Document, batch []int) (*index.BulkAddResult, error) {
+	conn := s.GetGrpcConn(endpoint)
+	if conn == nil {
+		return nil, fmt.Errorf("connect to worker %s failed", endpoint.SelfAddr)
+	}
+	stream, err := index.NewIndexServiceClient(conn).BulkAdd(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range batch {
+		if err = stream.Send(&index.AddDocRequest{Index: s.index, Doc: docs[i]}); err != nil {
+			if err == io.EOF {
+				// 流被服务端关闭，真正的错误需要从CloseAndRecv获取
+				_, err = stream.CloseAndRecv()
+			}
+			return nil, err
+		}
+	}
+	return stream.CloseAndRecv()
+}
+
+// 部分更新文档，发给持有该文档的节点，和添加、删除一样通过raft请求写入
+func (s *Sentinel) UpdateDoc(request *index.UpdateDocRequest) (int, error) {
+	endpoints := s.GetServiceEndpoints(INDEX_SERVICE)
+	if len(endpoints) == 0 {
+		return 0, fmt.Errorf("there is no alive index worker")
+	}
+	nodeRequest := &index.UpdateDocRequest{
+		Index:          request.Index,
+		DocId:          request.DocId,
+		Keywords:       request.Keywords,
+		Numerics:       request.Numerics,
+		Texts:          request.Texts,
+		RemoveFields:   request.RemoveFields,
+		SetBits:        request.SetBits,
+		ClearBits:      request.ClearBits,
+		Bytes:          request.Bytes,
+		UpdateBytes:    request.UpdateBytes,
+		Condition:      request.Condition,
+		ExpireAt:       request.ExpireAt,
+		UpdateExpireAt: request.UpdateExpireAt,
+	}
+	if len(nodeRequest.Index) == 0 {
+		nodeRequest.Index = s.index
+	}
+	//文档只在一个节点上，只发给持有它的节点，其他节点收到更新会创建出只有部分字段的文档
+	_, endpoint := s.locateDoc(request.DocId)
+	if endpoint == nil {
+		//文档不存在，发给哈希选出的节点，由它返回未更新或冲突
+		endpoint = docEndpoint(endpoints, request.DocId)
+	}
+	conn := s.GetGrpcConn(endpoint)
+	if conn == nil {
+		return 0, fmt.Errorf("connect to worker %s failed", endpoint.SelfAddr)
+	}
+	resp, err := RaftClintRequest(conn, 4, nil, nodeRequest, nil, nil, nil, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	return mergeAffected(request.DocId, []*index.AffectedCount{resp.(*index.AffectedCount)})
+}
+
 // 从集群中删除一个文档，需要遍历集群所有节点，异步完成，
 func (s *Sentinel) DeleteDoc(docId string) int {
+	n, _ := s.DeleteDocIf(docId, nil)
+	return n
+}
+
+// 满足条件时从集群中删除一个文档，不满足时返回*ConflictError
+func (s *Sentinel) DeleteDocIf(docId string, cond *index.Condition) (int, error) {
 	//1、获取服务器
 	endpoints := s.GetServiceEndpoints(INDEX_SERVICE)
 	if len(endpoints) == 0 {
-		return 0
+		return 0, nil
 	}
 
-	var res int32 //统计结果
+	results := make([]*index.AffectedCount, len(endpoints)) //各节点的结果
 	wg := sync.WaitGroup{}
 
-	for _, endpoint := range endpoints {
+	for i, endpoint := range endpoints {
 		wg.Add(1)
-		go func(endpoint *ServiceHub.EndPoint) {
+		go func(i int, endpoint *ServiceHub.EndPoint) {
 			defer wg.Done()
 			//2、获取grpc连接,并发发送删除请求
 			conn := s.GetGrpcConn(endpoint)
@@ -117,57 +293,126 @@ func (s *Sentinel) DeleteDoc(docId string) int {
 			//client := index.NewIndexServiceClient(conn)
 			////4、发送grpc请求
 			//affected, err := client.DeleteDoc(context.Background(), &index.DocId{DocId: docId})
-			resp, err := RaftClintRequest(conn, 2, nil, &index.DocId{DocId: docId}, nil, nil, nil, nil)
-			affected := resp.(*index.AffectedCount)
+			resp, err := RaftClintRequest(conn, 2, nil, nil, &index.DocId{DocId: docId, Index: s.index, Condition: cond}, nil, nil, nil, nil)
 			if err != nil {
-				util.Log.Printf("delete doc %s from worker %s failed: %s", docId, endpoint, err)
-			} else if affected.Count > 0 {
-				atomic.StoreInt32(&res, 1)
-				util.Log.Printf("delete %d doc from worker %s", affected.Count, endpoint)
+				util.Log.Printf("delete doc %s from worker %s failed: %s", docId, endpoint.SelfAddr, err)
+				return
 			}
-		}(endpoint)
+			affected := resp.(*index.AffectedCount)
+			results[i] = affected
+			if affected.Count > 0 {
+				util.Log.Printf("delete %d doc from worker %s", affected.Count, endpoint.SelfAddr)
+			}
+		}(i, endpoint)
 	}
 	wg.Wait()
-	return int(res)
+	n, err := mergeAffected(docId, results)
+	return min(n, 1), err
+}
+
+// 合并各节点条件写入的结果，文档只在一个节点上，其他节点会因为文档不存在而冲突
+// 有节点写入成功时返回成功，否则返回版本最大的冲突，nil表示该节点请求失败
+func mergeAffected(docId string, results []*index.AffectedCount) (int, error) {
+	var count int32
+	var conflict *index.AffectedCount
+	for _, affected := range results {
+		if affected == nil {
+			continue
+		}
+		count += affected.Count
+		if affected.Conflict && (conflict == nil || affected.Version > conflict.Version) {
+			conflict = affected
+		}
+	}
+	if count > 0 {
+		return int(count), nil
+	}
+	return 0, conflictOf(docId, conflict)
 }
 
-// 从集群上查找，合并查询的结果
-func (s *Sentinel) Search(query *term_query.TermQuery, onFlag *util.Bitmap, offFlag *util.Bitmap, orFlags []*util.Bitmap) []*doc.Document {
+// 从集群上查找，合并查询的结果，按相关性得分降序，只保留TopK个，再取出一页
+// 有节点失败时返回其余节点合并的结果和节点的错误，调用方可以区分部分结果和完整结果
+func (s *Sentinel) Search(request *index.SearchRequest) (*index.SearchResult, error) {
+	if err := checkPage(request); err != nil {
+		return &index.SearchResult{}, err
+	}
 	//1、获取服务器
 	endpoints := s.GetServiceEndpoints(INDEX_SERVICE)
 	if len(endpoints) == 0 {
-		return nil
+		return &index.SearchResult{}, nil
+	}
+
+	// 每个节点都可能贡献一页里的任意位置，所以每个节点都从游标处返回前offset+limit个，合并后再分页
+	// 游标基于得分和业务id，各节点可以直接使用
+	nodeRequest := &index.SearchRequest{
+		Query:     request.Query,
+		OnFlag:    request.OnFlag,
+		OffFlag:   request.OffFlag,
+		OrFlags:   request.OrFlags,
+		TopK:      request.TopK,
+		Limit:     int32(pageWindow(request)),
+		Cursor:    request.Cursor,
+		Index:     request.Index,
+		Highlight: request.Highlight,
+	}
+	if len(nodeRequest.Index) == 0 {
+		nodeRequest.Index = s.index
+	}
+	// 节点返回terms聚合的全部词，合并后再截断，避免各节点截断后漏掉总数靠前的词
+	for _, agg := range request.Aggregations {
+		nodeAgg := &index.Aggregation{Type: agg.Type, Name: agg.Name, Field: agg.Field, Interval: agg.Interval}
+		nodeRequest.Aggregations = append(nodeRequest.Aggregations, nodeAgg)
 	}
 
-	res := make([]*doc.Document, 100)         //统计结果
+	res := make([]*doc.Document, 0, 100)      //统计结果
 	docsChan := make(chan *doc.Document, 100) // 存储并发结果
+	var more int32                            // 是否有节点还有下一页
+	aggregations := make([][]*index.AggregationResult, 0, len(endpoints))
+	highlights := make(map[string]*index.DocHighlight) // 业务id -> 高亮片段
+	var errs []error                                   // 失败节点的错误
+	aggLock := sync.Mutex{}
 	wg := sync.WaitGroup{}
 
 	for _, endpoint := range endpoints {
 		wg.Add(1)
 		go func(endpoint *ServiceHub.EndPoint) {
 			defer wg.Done()
-			//2、获取grpc连接,并发发送删除请求
+			//2、获取grpc连接,并发发送查询请求
 			conn := s.GetGrpcConn(endpoint)
 			if conn == nil {
+				aggLock.Lock()
+				errs = append(errs, fmt.Errorf("connect to %s failed", endpoint.SelfAddr))
+				aggLock.Unlock()
 				return
 			}
-			////3、创建客户端
-			//client := index.NewIndexServiceClient(conn)
-			////4、发送grpc请求
-			//affected, err := client.Search(context.Background(), &index.SearchRequest{
-			//	OnFlag:  onFlag,
-			//	OffFlag: offFlag,
-			//	OrFlags: orFlags,
-			//})
-			resp, err := RaftClintRequest(conn, 3, nil, nil, query, onFlag, offFlag, orFlags)
-			affected := resp.(*index.SearchResult)
+			//3、创建客户端，查询是只读请求，直接发给节点
+			client := index.NewIndexServiceClient(conn)
+			//4、发送grpc请求，每个节点都返回自己的TopK，合并后再取TopK
+			affected, err := client.Search(context.Background(), nodeRequest)
 			if err != nil {
 				util.Log.Printf("search from cluster failed: %s", err)
+				aggLock.Lock()
+				errs = append(errs, fmt.Errorf("search from %s failed: %w", endpoint.SelfAddr, err))
+				aggLock.Unlock()
 			} else {
+				if len(affected.NextCursor) > 0 {
+					atomic.StoreInt32(&more, 1)
+				}
+				if len(affected.Aggregations) > 0 {
+					aggLock.Lock()
+					aggregations = append(aggregations, affected.Aggregations)
+					aggLock.Unlock()
+				}
+				if len(affected.Highlights) > 0 {
+					aggLock.Lock()
+					for _, highlight := range affected.Highlights {
+						highlights[highlight.Id] = highlight
+					}
+					aggLock.Unlock()
+				}
 				if len(affected.Results) > 0 {
 					//5、合并结果
-					util.Log.Printf("search %d doc from worker %s", len(affected.Results), endpoint)
+					util.Log.Printf("search %d doc from worker %s", len(affected.Results), endpoint.SelfAddr)
 					for _, document := range affected.Results {
 						docsChan <- document
 					}
@@ -192,9 +437,174 @@ func (s *Sentinel) Search(query *term_query.TermQuery, onFlag *util.Bitmap, offF
 	wg.Wait()
 	close(docsChan)      // 写入完毕，关闭管道，可以继续读
 	signal <- struct{}{} // 发送信号，退出协程（不接受信号就阻塞）
+
+	// 各节点的得分都基于本节点的统计信息，近似可比
+	sort.Slice(res, func(i, j int) bool {
+		return docBefore(res[i], res[j])
+	})
+	hasMore := more == 1
+	if request.TopK > 0 && len(request.Cursor) == 0 && len(res) >= int(request.TopK) {
+		// 没有游标时结果从头开始，超过TopK的部分不会再返回
+		res = res[:request.TopK]
+		hasMore = false
+	}
+	start, end, hasMore := pageRange(len(res), request, hasMore)
+	result := &index.SearchResult{
+		Results:      res[start:end],
+		Aggregations: reverseindex.MergeAggregations(request.Aggregations, aggregations...),
+	}
+	// 只返回这一页文档的高亮片段
+	for _, document := range result.Results {
+		if highlight, exist := highlights[document.Id]; exist {
+			result.Highlights = append(result.Highlights, highlight)
+		}
+	}
+	if hasMore && end > start {
+		last := res[end-1]
+		result.NextCursor = encodeCursor(last.Score, last.Id)
+	}
+	return result, errors.Join(errs...)
+}
+
+// 从集群上读取文档，文档按业务id哈希写到一个节点上，先读该节点，没有时再询问所有节点
+func (s *Sentinel) GetDoc(docId string) *doc.Document {
+	res, _ := s.locateDoc(docId)
+	return res
+}
+
+// 查找持有文档的节点，先询问按业务id哈希选出的节点，没有时再询问所有节点
+// 节点增减后或哈希路由之前写入的文档可能在其他节点上，不存在时都为nil
+func (s *Sentinel) locateDoc(docId string) (*doc.Document, *ServiceHub.EndPoint) {
+	endpoint := docEndpoint(s.GetServiceEndpoints(INDEX_SERVICE), docId)
+	if endpoint == nil {
+		return nil, nil
+	}
+	if document := s.getDoc(endpoint, docId); document != nil {
+		return document, endpoint
+	}
+	return s.findDoc(docId)
+}
+
+// 询问所有节点，返回文档和持有该文档的节点，不存在时都为nil
+func (s *Sentinel) findDoc(docId string) (*doc.Document, *ServiceHub.EndPoint) {
+	endpoints := s.GetServiceEndpoints(INDEX_SERVICE)
+	if len(endpoints) == 0 {
+		return nil, nil
+	}
+	var res atomic.Pointer[doc.Document]
+	var owner atomic.Pointer[ServiceHub.EndPoint]
+	wg := sync.WaitGroup{}
+	for _, endpoint := range endpoints {
+		wg.Add(1)
+		go func(endpoint *ServiceHub.EndPoint) {
+			defer wg.Done()
+			if document := s.getDoc(endpoint, docId); document != nil {
+				res.Store(document)
+				owner.Store(endpoint)
+			}
+		}(endpoint)
+	}
+	wg.Wait()
+	return res.Load(), owner.Load()
+}
+
+// 从一个节点上读取文档，不存在或请求失败时返回nil
+func (s *Sentinel) getDoc(endpoint *ServiceHub.EndPoint, docId string) *doc.Document {
+	conn := s.GetGrpcConn(endpoint)
+	if conn == nil {
+		return nil
+	}
+	result, err := index.NewIndexServiceClient(conn).GetDoc(context.Background(), &index.DocId{DocId: docId, Index: s.index})
+	if err != nil {
+		util.Log.Printf("get doc %s from worker %s failed: %s", docId, endpoint.SelfAddr, err)
+		return nil
+	}
+	return result.Doc
+}
+
+// 从集群上批量读取文档，每个节点返回自己持有的文档，再按docIds的顺序合并
+func (s *Sentinel) MultiGetDoc(docIds []string) []*doc.Document {
+	endpoints := s.GetServiceEndpoints(INDEX_SERVICE)
+	if len(endpoints) == 0 || len(docIds) == 0 {
+		return nil
+	}
+	docs := make(map[string]*doc.Document, len(docIds))
+	lock := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	request := &index.MultiGetRequest{Index: s.index, DocIds: docIds}
+	for _, endpoint := range endpoints {
+		wg.Add(1)
+		go func(endpoint *ServiceHub.EndPoint) {
+			defer wg.Done()
+			conn := s.GetGrpcConn(endpoint)
+			if conn == nil {
+				return
+			}
+			result, err := index.NewIndexServiceClient(conn).MultiGetDoc(context.Background(), request)
+			if err != nil {
+				util.Log.Printf("multi get doc from worker %s failed: %s", endpoint.SelfAddr, err)
+				return
+			}
+			lock.Lock()
+			defer lock.Unlock()
+			for _, document := range result.Docs {
+				docs[document.Id] = document
+			}
+		}(endpoint)
+	}
+	wg.Wait()
+	res := make([]*doc.Document, 0, len(docs))
+	for _, docId := range docIds {
+		if document, exist := docs[docId]; exist {
+			res = append(res, document)
+		}
+	}
 	return res
 }
 
+// 从集群上获取输入提示，各节点返回自己的前Size个，合并后再取前Size个
+func (s *Sentinel) Suggest(request *index.SuggestRequest) *index.SuggestResult {
+	endpoints := s.GetServiceEndpoints(INDEX_SERVICE)
+	if len(endpoints) == 0 {
+		return &index.SuggestResult{}
+	}
+	nodeRequest := &index.SuggestRequest{
+		Index:       request.Index,
+		Prefix:      request.Prefix,
+		Fields:      request.Fields,
+		Size:        request.Size,
+		Fuzzy:       request.Fuzzy,
+		Fuzziness:   request.Fuzziness,
+		WeightField: request.WeightField,
+	}
+	if len(nodeRequest.Index) == 0 {
+		nodeRequest.Index = s.index
+	}
+	results := make([][]*index.Suggestion, 0, len(endpoints))
+	lock := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	for _, endpoint := range endpoints {
+		wg.Add(1)
+		go func(endpoint *ServiceHub.EndPoint) {
+			defer wg.Done()
+			conn := s.GetGrpcConn(endpoint)
+			if conn == nil {
+				return
+			}
+			result, err := index.NewIndexServiceClient(conn).Suggest(context.Background(), nodeRequest)
+			if err != nil {
+				util.Log.Printf("suggest from worker %s failed: %s", endpoint.SelfAddr, err)
+				return
+			}
+			lock.Lock()
+			results = append(results, result.Suggestions)
+			lock.Unlock()
+		}(endpoint)
+	}
+	wg.Wait()
+	return &index.SuggestResult{Suggestions: reverseindex.MergeSuggestions(request, results...)}
+}
+
 func (s *Sentinel) Count() int {
 	//1、获取服务器
 	endpoints := s.GetServiceEndpoints(INDEX_SERVICE)
@@ -217,12 +627,12 @@ func (s *Sentinel) Count() int {
 			//3、创建客户端
 			client := index.NewIndexServiceClient(conn)
 			//4、发送grpc请求
-			affected, err := client.Count(context.Background(), &index.CountRequest{})
+			affected, err := client.Count(context.Background(), &index.CountRequest{Index: s.index})
 			if err != nil {
-				util.Log.Printf("get doc count from worker %s failed: %s", endpoint, err)
+				util.Log.Printf("get doc count from worker %s failed: %s", endpoint.SelfAddr, err)
 			} else {
 				atomic.StoreInt32(&res, affected.Count)
-				util.Log.Printf("worker %s have %d documents", endpoint, affected.Count)
+				util.Log.Printf("worker %s have %d documents", endpoint.SelfAddr, affected.Count)
 			}
 		}(endpoint)
 	}
@@ -230,6 +640,82 @@ func (s *Sentinel) Count() int {
 	return int(res)
 }
 
+// 在集群所有节点上创建命名索引，返回创建成功的节点数
+func (s *Sentinel) CreateIndex(request *index.CreateIndexRequest) int {
+	return s.broadcast("create index "+request.Name, func(client index.IndexServiceClient) (*index.AffectedCount, error) {
+		return client.CreateIndex(context.Background(), request)
+	})
+}
+
+// 在集群所有节点上删除命名索引，返回删除成功的节点数
+func (s *Sentinel) DeleteIndex(name string) int {
+	return s.broadcast("delete index "+name, func(client index.IndexServiceClient) (*index.AffectedCount, error) {
+		return client.DeleteIndex(context.Background(), &index.IndexName{Name: name})
+	})
+}
+
+// 并发向所有节点发送请求，返回成功的节点数
+func (s *Sentinel) broadcast(action string, fn func(client index.IndexServiceClient) (*index.AffectedCount, error)) int {
+	endpoints := s.GetServiceEndpoints(INDEX_SERVICE)
+	var res int32
+	wg := sync.WaitGroup{}
+	for _, endpoint := range endpoints {
+		wg.Add(1)
+		go func(endpoint *ServiceHub.EndPoint) {
+			defer wg.Done()
+			conn := s.GetGrpcConn(endpoint)
+			if conn == nil {
+				return
+			}
+			affected, err := fn(index.NewIndexServiceClient(conn))
+			if err != nil {
+				util.Log.Printf("%s on worker %s failed: %s", action, endpoint.SelfAddr, err)
+			} else if affected.Count > 0 {
+				atomic.AddInt32(&res, 1)
+			}
+		}(endpoint)
+	}
+	wg.Wait()
+	return int(res)
+}
+
+// 列出集群上的所有索引，文档数量是各节点之和
+func (s *Sentinel) ListIndex() []*index.IndexInfo {
+	endpoints := s.GetServiceEndpoints(INDEX_SERVICE)
+	counts := make(map[string]int32)
+	lock := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	for _, endpoint := range endpoints {
+		wg.Add(1)
+		go func(endpoint *ServiceHub.EndPoint) {
+			defer wg.Done()
+			conn := s.GetGrpcConn(endpoint)
+			if conn == nil {
+				return
+			}
+			list, err := index.NewIndexServiceClient(conn).ListIndex(context.Background(), &index.ListIndexRequest{})
+			if err != nil {
+				util.Log.Printf("list index from worker %s failed: %s", endpoint.SelfAddr, err)
+				return
+			}
+			lock.Lock()
+			defer lock.Unlock()
+			for _, info := range list.Indexes {
+				counts[info.Name] += info.Count
+			}
+		}(endpoint)
+	}
+	wg.Wait()
+	res := make([]*index.IndexInfo, 0, len(counts))
+	for name, count := range counts {
+		res = append(res, &index.IndexInfo{Name: name, Count: count})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
+
 func (s *Sentinel) Close() error {
 	s.connPool.Range(func(key, value any) bool {
 		conn := value.(*grpc.ClientConn)
@@ -240,27 +726,23 @@ func (s *Sentinel) Close() error {
 	return nil
 }
 
-func RaftClintRequest(conn *grpc.ClientConn, reqType int64, document *doc.Document, docId *index.DocId, query *term_query.TermQuery, onFlag *util.Bitmap, offFlag *util.Bitmap, orFlags []*util.Bitmap) (any, error) {
+func RaftClintRequest(conn *grpc.ClientConn, reqType int64, request *index.AddDocRequest, update *index.UpdateDocRequest, docId *index.DocId, query *term_query.TermQuery, onFlag *util.Bitmap, offFlag *util.Bitmap, orFlags []*util.Bitmap) (any, error) {
 	client := raft.NewResearchClientServiceClient(conn)
 
 	var args []string
 	switch reqType {
 	case 1:
 		args = make([]string, 1)
-		str, err := Serialize[*doc.Document](document)
+		str, err := Serialize[*index.AddDocRequest](request)
 		if err != nil {
 			return nil, err
 		}
 		args[0] = str
-		research, err := client.Research(context.Background(), &raft.ResearchRequest{
-			ReqType: &reqType,
-			Args:    args,
-		})
-		if err != nil || len(research.Values) == 0 {
+		values, err := research(client, reqType, args)
+		if err != nil {
 			return nil, err
 		}
-		target, err := DeserializeAffectedCount(research.Values[0])
-		return target, err
+		return DeserializeAffectedCount(values)
 	case 2:
 		args = make([]string, 1)
 		str, err := Serialize[*index.DocId](docId)
@@ -268,12 +750,11 @@ func RaftClintRequest(conn *grpc.ClientConn, reqType int64, document *doc.Docume
 			return nil, err
 		}
 		args[0] = str
-		research, err := client.Research(context.Background(), &raft.ResearchRequest{
-			ReqType: &reqType,
-			Args:    args,
-		})
-		target, err := DeserializeAffectedCount(research.Values[0])
-		return target, err
+		values, err := research(client, reqType, args)
+		if err != nil {
+			return nil, err
+		}
+		return DeserializeAffectedCount(values)
 	case 3:
 		args = make([]string, 4)
 		str1, err := Serialize[*term_query.TermQuery](query)
@@ -296,45 +777,90 @@ func RaftClintRequest(conn *grpc.ClientConn, reqType int64, document *doc.Docume
 			return nil, err
 		}
 		args[3] = str4
-		research, err := client.Research(context.Background(), &raft.ResearchRequest{
-			ReqType: &reqType,
-			Args:    args,
-		})
-		target, err := DeserializeSearchResult(research.Values)
+		values, err := research(client, reqType, args)
+		if err != nil {
+			return nil, err
+		}
+		return DeserializeSearchResult(values)
+	case 4:
+		args = make([]string, 1)
+		str, err := Serialize[*index.UpdateDocRequest](update)
+		if err != nil {
+			return nil, err
+		}
+		args[0] = str
+		values, err := research(client, reqType, args)
 		if err != nil {
 			return nil, err
 		}
-		return target, err
+		return DeserializeAffectedCount(values)
 	}
 	return nil, errors.New("unknown request type")
 }
 
-func DeserializeAffectedCount(s string) (target *index.AffectedCount, err error) {
-	atoi, err := strconv.Atoi(s)
+// 发送raft请求，服务端执行失败时返回服务端的错误信息。写入条件不满足不算失败，由Values中的Conflict标记
+func research(client raft.ResearchClientServiceClient, reqType int64, args []string) ([]string, error) {
+	resp, err := client.Research(context.Background(), &raft.ResearchRequest{
+		ReqType: &reqType,
+		Args:    args,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.GetSuccess() {
+		return nil, fmt.Errorf("raft request %d failed: %s", reqType, resp.GetErrorMsg())
+	}
+	return resp.Values, nil
+}
+
+// AffectedCount序列化为[Count, Version, Conflict]三个字符串
+func SerializeAffectedCount(affected *index.AffectedCount) []string {
+	return []string{
+		strconv.Itoa(int(affected.Count)),
+		strconv.FormatUint(affected.Version, 10),
+		strconv.FormatBool(affected.Conflict),
+	}
+}
+
+func DeserializeAffectedCount(s []string) (target *index.AffectedCount, err error) {
+	if len(s) == 0 {
+		return nil, errors.New("empty affected count")
+	}
+	atoi, err := strconv.Atoi(s[0])
 	if err != nil {
 		return
 	}
 	target = &index.AffectedCount{
 		Count: int32(atoi),
 	}
+	// 只有Count时其余字段为零值
+	if len(s) >= 3 {
+		if target.Version, err = strconv.ParseUint(s[1], 10, 64); err != nil {
+			return nil, err
+		}
+		if target.Conflict, err = strconv.ParseBool(s[2]); err != nil {
+			return nil, err
+		}
+	}
 	return
 }
 
 func DeserializeSearchResult(s []string) (target *index.SearchResult, err error) {
 	reader := bytes.NewReader([]byte{})
-	target.Results = make([]*doc.Document, len(s))
+	target = &index.SearchResult{Results: make([]*doc.Document, len(s))}
 	for i := 0; i < len(s); i++ {
 		reader.Reset([]byte(s[i]))
 		decoder := gob.NewDecoder(reader)
+		target.Results[i] = new(doc.Document)
 		err = decoder.Decode(target.Results[i])
 		if err != nil {
-			return
+			return nil, err
 		}
 	}
 	return
 }
 
-func Serialize[T *doc.Document | *index.DocId | *term_query.TermQuery | *util.Bitmap | []*util.Bitmap](input T) (str string, err error) {
+func Serialize[T *doc.Document | *index.AddDocRequest | *index.UpdateDocRequest | *index.DocId | *term_query.TermQuery | *util.Bitmap | []*util.Bitmap](input T) (str string, err error) {
 	var buffer bytes.Buffer
 	encoder := gob.NewEncoder(&buffer)
 	err = encoder.Encode(input)
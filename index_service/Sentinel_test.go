@@ -0,0 +1,120 @@
+package index_service
+
+import (
+	"Research/ServiceHub"
+	"Research/types/doc"
+	"Research/types/index"
+	"Research/types/raft"
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+// 按请求返回固定响应的raft服务端
+type fakeRaftServer struct {
+	raft.UnimplementedResearchClientServiceServer
+	resp *raft.ResearchResponse
+	reqs []*raft.ResearchRequest
+}
+
+func (s *fakeRaftServer) Research(ctx context.Context, req *raft.ResearchRequest) (*raft.ResearchResponse, error) {
+	s.reqs = append(s.reqs, req)
+	return s.resp, nil
+}
+
+// 启动内存中的raft服务端，返回连接它的客户端连接
+func newFakeRaftConn(t *testing.T, server *fakeRaftServer) *grpc.ClientConn {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	raft.RegisterResearchClientServiceServer(s, server)
+	go s.Serve(listener)
+	t.Cleanup(s.Stop)
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestRaftClientRequestReturnsServerError(t *testing.T) {
+	server := &fakeRaftServer{resp: &raft.ResearchResponse{Success: proto.Bool(false), ErrorMsg: proto.String("index not exist: books")}}
+	conn := newFakeRaftConn(t, server)
+
+	_, err := RaftClintRequest(conn, 1, &index.AddDocRequest{Index: "books", Doc: &doc.Document{Id: "a"}}, nil, nil, nil, nil, nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "index not exist: books") {
+		t.Errorf("add: got %v", err)
+	}
+	_, err = RaftClintRequest(conn, 2, nil, nil, &index.DocId{DocId: "a"}, nil, nil, nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "index not exist: books") {
+		t.Errorf("delete: got %v", err)
+	}
+}
+
+func TestRaftClientRequestReturnsConflict(t *testing.T) {
+	affected := &index.AffectedCount{Version: 3, Conflict: true}
+	server := &fakeRaftServer{resp: &raft.ResearchResponse{Success: proto.Bool(true), Values: SerializeAffectedCount(affected)}}
+	conn := newFakeRaftConn(t, server)
+
+	resp, err := RaftClintRequest(conn, 1, &index.AddDocRequest{Doc: &doc.Document{Id: "a"}, Condition: &index.Condition{IfVersion: 2}}, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := resp.(*index.AffectedCount)
+	if !got.Conflict || got.Version != 3 || got.Count != 0 {
+		t.Errorf("got count %d version %d conflict %v", got.Count, got.Version, got.Conflict)
+	}
+	if err = conflictOf("a", got); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("got %v, want version conflict", err)
+	}
+}
+
+func TestDocEndpoint(t *testing.T) {
+	if docEndpoint(nil, "a") != nil {
+		t.Fatal("no endpoint: want nil")
+	}
+	endpoints := make([]*ServiceHub.EndPoint, 0, 4)
+	for i := 0; i < 4; i++ {
+		endpoints = append(endpoints, ServiceHub.NewEndPoint("127.0.0.1", 5000+i, 1))
+	}
+	reversed := slices.Clone(endpoints)
+	slices.Reverse(reversed)
+
+	counts := make(map[string]int)
+	owners := make(map[string]string)
+	for i := 0; i < 4000; i++ {
+		docId := strconv.Itoa(i)
+		owner := docEndpoint(endpoints, docId)
+		// 与节点顺序无关，id前后的空白不影响
+		if docEndpoint(reversed, docId) != owner || docEndpoint(endpoints, " "+docId+" ") != owner {
+			t.Fatalf("doc %s is routed to different endpoints", docId)
+		}
+		counts[owner.SelfAddr]++
+		owners[docId] = owner.SelfAddr
+	}
+	for addr, n := range counts {
+		if n < 700 || n > 1300 {
+			t.Errorf("endpoint %s got %d of 4000 docs", addr, n)
+		}
+	}
+
+	// 增加一个节点，只有落到新节点上的文档换节点
+	added := append(endpoints, ServiceHub.NewEndPoint("127.0.0.1", 5004, 1))
+	for docId, addr := range owners {
+		if owner := docEndpoint(added, docId); owner.SelfAddr != addr && owner != added[4] {
+			t.Fatalf("doc %s moved from %s to %s", docId, addr, owner.SelfAddr)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package index_service
+
+import (
+	"Research/etc"
+	"Research/types/doc"
+	"Research/types/term_query"
+	"Research/util"
+	"slices"
+	"time"
+)
+
+const (
+	expireField          = "_expire_at" // 过期时间在数值索引上使用的字段
+	defaultSweepInterval = time.Minute  // 默认清理间隔
+	defaultSweepBatch    = 1000         // 默认每批删除的文档数
+)
+
+// 文档的过期时间对应的数值字段，不过期时返回nil
+func expireNumeric(d *doc.Document) *doc.NumericField {
+	if d.ExpireAt <= 0 {
+		return nil
+	}
+	return &doc.NumericField{Field: expireField, Value: float64(d.ExpireAt)}
+}
+
+// 已过期文档的范围查询，过期时间不晚于now
+func expiredQuery(now int64) *term_query.TermQuery {
+	to := float64(now)
+	return &term_query.TermQuery{Range: &term_query.Range{Field: expireField, To: &to}}
+}
+
+// 在查询上排除已过期的文档，清理之前的过期文档也检索不到
+func notExpired(q *term_query.TermQuery, now int64) *term_query.TermQuery {
+	if q == nil {
+		return nil
+	}
+	return &term_query.TermQuery{
+		Must:    []*term_query.TermQuery{q},
+		MustNot: []*term_query.TermQuery{expiredQuery(now)},
+	}
+}
+
+// 后台定期清理过期文档，索引关闭时退出
+func (indexer *Indexer) startSweeper(conf *etc.Expire) {
+	if conf.SweepInterval < 0 {
+		return
+	}
+	interval := time.Duration(conf.SweepInterval) * time.Second
+	if interval == 0 {
+		interval = defaultSweepInterval
+	}
+	batch := conf.BatchSize
+	if batch <= 0 {
+		batch = defaultSweepBatch
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-indexer.stop:
+				return
+			case <-ticker.C:
+				if n := indexer.sweep(batch); n > 0 {
+					util.Log.Printf("sweep %d expired docs from index %s", n, indexer.Name())
+				}
+			}
+		}
+	}()
+}
+
+// 删除所有已过期的文档，返回删除的文档数
+func (indexer *Indexer) SweepExpired() int {
+	return indexer.sweep(defaultSweepBatch)
+}
+
+// 按批删除过期文档，直到没有过期文档，或者一批中没有可以删除的文档
+func (indexer *Indexer) sweep(batch int) int {
+	now := time.Now().UnixMilli()
+	total := 0
+	for {
+		found, removed := indexer.sweepBatch(now, batch)
+		total += removed
+		if found < batch || removed == 0 {
+			return total
+		}
+	}
+}
+
+// 删除一批过期文档，正排索引一次删除，返回找到和删除的文档数
+func (indexer *Indexer) sweepBatch(now int64, batch int) (int, int) {
+	hits, _ := indexer.reverseIndex.Search(expiredQuery(now), nil, nil, nil, batch, nil)
+	if len(hits) == 0 {
+		return 0, 0
+	}
+	docIds := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		docIds = append(docIds, hit.Id)
+	}
+	indexer.walLock.RLock()
+	defer indexer.walLock.RUnlock()
+	unlock := indexer.docLocks.lock(docIds...)
+	defer unlock()
+
+	expired := make([]*doc.Document, 0, len(hits))
+	keys := make([][]byte, 0, 2*len(hits))
+	for _, docId := range docIds {
+		// 检索之后文档可能被更新或删除，在锁内重新检查
+		d, err := indexer.getDoc(docId)
+		if err != nil || d.ExpireAt <= 0 || d.ExpireAt > now {
+			continue
+		}
+		if err = indexer.appendWal(walDelete, d.Id, d.IntId, nil); err != nil {
+			util.Log.Printf("write wal failed: %s", err)
+			continue
+		}
+		expired = append(expired, d)
+		keys = append(keys, []byte(d.Id), intIdKey(d.IntId))
+	}
+	if len(expired) == 0 {
+		return len(hits), 0
+	}
+	if err := indexer.forwardIndex.BatchDelete(keys); err != nil {
+		util.Log.Printf("delete expired docs failed: %s", err)
+		return len(hits), 0
+	}
+	for _, d := range expired {
+		indexer.unindex(d)
+	}
+	return len(hits), len(expired)
+}
+
+// 在需要写入倒排索引的文档上加上过期时间
+func withExpire(d *doc.Document, expire *doc.NumericField) *doc.Document {
+	return &doc.Document{
+		Id:          d.Id,
+		IntId:       d.IntId,
+		BitsFeature: d.BitsFeature,
+		Keywords:    d.Keywords,
+		Numerics:    append(slices.Clip(d.Numerics), expire),
+		ExpireAt:    d.ExpireAt,
+	}
+}
@@ -0,0 +1,107 @@
+package index_service
+
+import (
+	"Research/etc"
+	"Research/internal/kvdb"
+	"Research/types/doc"
+	"Research/types/index"
+	"slices"
+	"testing"
+	"time"
+)
+
+// 和redis一样没有存储路径的数据库
+type noPathDB struct {
+	kvdb.IKeyValueDB
+}
+
+func (db *noPathDB) GetDbPath() string {
+	panic("implement me")
+}
+
+func expiringDoc(id string, expireAt int64) *doc.Document {
+	return &doc.Document{Id: id, Keywords: []*doc.KeyWord{{Field: "f", Word: "w"}}, ExpireAt: expireAt}
+}
+
+func TestSearchSkipsExpiredDocs(t *testing.T) {
+	eachIndexType(t, func(t *testing.T, indexer *Indexer) {
+		now := time.Now().UnixMilli()
+		indexer.AddDoc(expiringDoc("expired", now-1000))
+		indexer.AddDoc(expiringDoc("later", now+time.Hour.Milliseconds()))
+		indexer.AddDoc(expiringDoc("never", 0))
+
+		res, err := indexer.Search(&index.SearchRequest{
+			Query:        keywordQuery("f", "w"),
+			Aggregations: []*index.Aggregation{{Type: index.Aggregation_TERMS, Field: "f"}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids := resultIds(res.Results)
+		slices.Sort(ids)
+		if !slices.Equal(ids, []string{"later", "never"}) {
+			t.Errorf("got %v", ids)
+		}
+		if buckets := res.Aggregations[0].Buckets; len(buckets) != 1 || buckets[0].Count != 2 {
+			t.Errorf("expired doc is aggregated: %v", buckets)
+		}
+		// 清理之前文档还在正排索引中
+		if indexer.Count() != 3 {
+			t.Errorf("count: got %d, want 3", indexer.Count())
+		}
+	})
+}
+
+func TestSweepExpired(t *testing.T) {
+	eachIndexType(t, func(t *testing.T, indexer *Indexer) {
+		now := time.Now().UnixMilli()
+		for _, id := range []string{"a", "b", "c"} {
+			indexer.AddDoc(expiringDoc(id, now-1000))
+		}
+		indexer.AddDoc(expiringDoc("d", now+time.Hour.Milliseconds()))
+		// 延长过期时间的文档不被清理
+		indexer.UpdateDoc(&index.UpdateDocRequest{DocId: "c", UpdateExpireAt: true, ExpireAt: now + time.Hour.Milliseconds()})
+
+		// 每批2个，需要多批
+		if n := indexer.sweep(2); n != 2 {
+			t.Errorf("swept %d, want 2", n)
+		}
+		if n := indexer.Count(); n != 2 {
+			t.Errorf("count: got %d, want 2", n)
+		}
+		for _, id := range []string{"a", "b"} {
+			if indexer.GetDoc(id) != nil {
+				t.Errorf("expired doc %s is not deleted", id)
+			}
+		}
+		hits, _ := indexer.reverseIndex.Search(expiredQuery(time.Now().UnixMilli()), nil, nil, nil, 0, nil)
+		if len(hits) != 0 {
+			t.Errorf("expired postings are left: %d", len(hits))
+		}
+		if n := indexer.SweepExpired(); n != 0 {
+			t.Errorf("second sweep deleted %d", n)
+		}
+	})
+}
+
+func TestBackgroundSweeperWithoutDbPath(t *testing.T) {
+	c := &etc.Config{}
+	c.ForwardIndex.Dbtype = "memory"
+	c.Expire.SweepInterval = -1
+	indexer := new(Indexer)
+	if err := indexer.Init(c); err != nil {
+		t.Fatal(err)
+	}
+	defer indexer.Close()
+	indexer.forwardIndex = &noPathDB{IKeyValueDB: indexer.forwardIndex}
+	indexer.AddDoc(expiringDoc("a", time.Now().UnixMilli()-1000))
+
+	indexer.startSweeper(&etc.Expire{SweepInterval: 1})
+	deadline := time.Now().Add(5 * time.Second)
+	for indexer.Count() > 0 && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+	}
+	if n := indexer.Count(); n != 0 {
+		t.Errorf("count: got %d, want 0", n)
+	}
+}
@@ -0,0 +1,275 @@
+package index_service
+
+import (
+	"Research/analysis"
+	"Research/types/doc"
+	"Research/types/index"
+	"Research/types/term_query"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// 高亮的默认配置
+const (
+	defaultPreTag       = "<em>"
+	defaultPostTag      = "</em>"
+	defaultFragmentSize = 100
+	defaultNumFragments = 3
+)
+
+// 没有配置分析器的字段按标准分析器切分，和查询词比较时忽略大小写
+var fallbackAnalyzer = analysis.NewBuiltinAnalyzer(analysis.STANDARD)
+
+// 高亮器，根据查询中的关键词、短语和前缀在文档的原始文本中标记匹配词，截取包含匹配词的片段
+// 通配符和模糊查询不高亮
+type highlighter struct {
+	fields       map[string]struct{} // 需要高亮的字段，为空表示所有字段
+	preTag       string
+	postTag      string
+	fragmentSize int
+	numFragments int
+	analyzer     *analysis.FieldAnalyzer        // 与建索引时相同的分析器，可以为nil
+	words        map[string]map[string]struct{} // field -> 查询词
+	prefixes     map[string][]string            // field -> 前缀
+}
+
+// q是经过分析之后的查询
+func newHighlighter(conf *index.Highlight, q *term_query.TermQuery, analyzer *analysis.FieldAnalyzer) *highlighter {
+	h := &highlighter{
+		fields:       make(map[string]struct{}, len(conf.Fields)),
+		preTag:       conf.PreTag,
+		postTag:      conf.PostTag,
+		fragmentSize: int(conf.FragmentSize),
+		numFragments: int(conf.NumFragments),
+		analyzer:     analyzer,
+		words:        make(map[string]map[string]struct{}),
+		prefixes:     make(map[string][]string),
+	}
+	for _, field := range conf.Fields {
+		h.fields[field] = struct{}{}
+	}
+	if len(h.preTag) == 0 && len(h.postTag) == 0 {
+		h.preTag, h.postTag = defaultPreTag, defaultPostTag
+	}
+	if h.fragmentSize == 0 {
+		h.fragmentSize = defaultFragmentSize
+	}
+	if h.numFragments <= 0 {
+		h.numFragments = defaultNumFragments
+	}
+	h.collect(q)
+	return h
+}
+
+// 收集查询中需要高亮的词，排除的条件不高亮
+func (h *highlighter) collect(q *term_query.TermQuery) {
+	if q == nil {
+		return
+	}
+	if q.Keyword != nil {
+		h.addWord(q.Keyword.Field, q.Keyword.Word)
+	}
+	if q.Phrase != nil {
+		for _, word := range q.Phrase.Words {
+			h.addWord(q.Phrase.Field, word)
+		}
+	}
+	if q.MultiTerm != nil && q.MultiTerm.Type == term_query.MultiTerm_PREFIX {
+		h.prefixes[q.MultiTerm.Field] = append(h.prefixes[q.MultiTerm.Field], q.MultiTerm.Pattern)
+	}
+	for _, val := range q.Must {
+		h.collect(val)
+	}
+	for _, val := range q.Should {
+		h.collect(val)
+	}
+}
+
+func (h *highlighter) addWord(field, word string) {
+	words, exist := h.words[field]
+	if !exist {
+		words = make(map[string]struct{})
+		h.words[field] = words
+	}
+	words[word] = struct{}{}
+}
+
+// 高亮一个文档，没有匹配词时返回nil
+func (h *highlighter) highlight(d *doc.Document) *index.DocHighlight {
+	var res *index.DocHighlight
+	var current *index.HighlightField
+	candidates := make([]*fragment, 0)
+	flush := func() {
+		if current != nil && len(candidates) > 0 {
+			current.Fragments = h.best(candidates)
+			if res == nil {
+				res = &index.DocHighlight{Id: d.Id}
+			}
+			res.Fields = append(res.Fields, current)
+		}
+		current, candidates = nil, candidates[:0]
+	}
+	// 同一字段的多段文本合并选出最好的片段，字段按第一次出现的顺序返回
+	order := make([]string, 0, len(d.Texts))
+	texts := make(map[string][]string, len(d.Texts))
+	for _, text := range d.Texts {
+		if _, exist := texts[text.Field]; !exist {
+			order = append(order, text.Field)
+		}
+		texts[text.Field] = append(texts[text.Field], text.Text)
+	}
+	for _, field := range order {
+		if _, exist := h.fields[field]; len(h.fields) > 0 && !exist {
+			continue
+		}
+		if len(h.words[field]) == 0 && len(h.prefixes[field]) == 0 {
+			continue
+		}
+		current = &index.HighlightField{Field: field}
+		for _, text := range texts[field] {
+			candidates = append(candidates, h.fragments(field, text)...)
+		}
+		flush()
+	}
+	return res
+}
+
+// 一个候选片段
+type fragment struct {
+	text  string // 已插入标签的片段
+	score int    // 匹配词的个数
+	order int    // 在字段中的顺序
+}
+
+// 选出匹配词最多的numFragments个片段，按原文顺序返回
+func (h *highlighter) best(candidates []*fragment) []string {
+	for i, candidate := range candidates {
+		candidate.order = i
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+	if len(candidates) > h.numFragments {
+		candidates = candidates[:h.numFragments]
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].order < candidates[j].order
+	})
+	res := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		res = append(res, candidate.text)
+	}
+	return res
+}
+
+// 在一段文本中找出匹配词，切成包含匹配词的片段
+func (h *highlighter) fragments(field, text string) []*fragment {
+	analyzer, fallback := h.analyzerOf(field)
+	// 偏移量基于字符过滤之后的文本
+	text = analyzer.Filter(text)
+	spans := make([][2]int, 0)
+	for _, token := range analyzer.Tokenize(text) {
+		if !h.match(field, token.Term, fallback) {
+			continue
+		}
+		// 同义词和原词的偏移量相同，只标记一次；紧挨着的匹配词合并成一段，如中文单字
+		if n := len(spans); n > 0 && spans[n-1][1] >= token.Start {
+			if token.End > spans[n-1][1] {
+				spans[n-1][1] = token.End
+			}
+			continue
+		}
+		spans = append(spans, [2]int{token.Start, token.End})
+	}
+	if len(spans) == 0 {
+		return nil
+	}
+	if h.fragmentSize < 0 || utf8.RuneCountInString(text) <= h.fragmentSize {
+		return []*fragment{{text: h.mark(text, 0, len(text), spans), score: len(spans)}}
+	}
+
+	// 每个字符的字节偏移，用于按字符数截取片段
+	offsets := make([]int, 0, len(text)+1)
+	for i := range text {
+		offsets = append(offsets, i)
+	}
+	offsets = append(offsets, len(text))
+	runeIndex := func(offset int) int {
+		return sort.SearchInts(offsets, offset)
+	}
+
+	res := make([]*fragment, 0)
+	for i := 0; i < len(spans); {
+		// 以第一个未覆盖的匹配词为中心截取片段
+		start, end := runeIndex(spans[i][0]), runeIndex(spans[i][1])
+		from := start - (h.fragmentSize-(end-start))/2
+		if from > start {
+			// 匹配词比片段长
+			from = start
+		}
+		if from < 0 {
+			from = 0
+		}
+		to := from + h.fragmentSize
+		if to < end {
+			to = end
+		}
+		if last := len(offsets) - 1; to > last {
+			to = last
+			if from = to - h.fragmentSize; from < 0 {
+				from = 0
+			}
+		}
+		startByte, endByte := offsets[from], offsets[to]
+		j := i
+		for j < len(spans) && spans[j][1] <= endByte {
+			j++
+		}
+		res = append(res, &fragment{text: h.mark(text, startByte, endByte, spans[i:j]), score: j - i})
+		i = j
+	}
+	return res
+}
+
+// 截取text[start:end]并在匹配词前后插入标签
+func (h *highlighter) mark(text string, start, end int, spans [][2]int) string {
+	var builder strings.Builder
+	for _, span := range spans {
+		if span[0] < start || span[1] > end {
+			continue
+		}
+		builder.WriteString(text[start:span[0]])
+		builder.WriteString(h.preTag)
+		builder.WriteString(text[span[0]:span[1]])
+		builder.WriteString(h.postTag)
+		start = span[1]
+	}
+	builder.WriteString(text[start:end])
+	return builder.String()
+}
+
+// 字段使用的分析器，没有配置时使用标准分析器，fallback为true
+func (h *highlighter) analyzerOf(field string) (*analysis.Analyzer, bool) {
+	if h.analyzer != nil {
+		if analyzer := h.analyzer.Get(field); analyzer != nil {
+			return analyzer, false
+		}
+	}
+	return fallbackAnalyzer, true
+}
+
+// 分析后的词是否匹配查询
+func (h *highlighter) match(field, term string, fallback bool) bool {
+	for word := range h.words[field] {
+		if term == word || fallback && strings.EqualFold(term, word) {
+			return true
+		}
+	}
+	for _, prefix := range h.prefixes[field] {
+		if strings.HasPrefix(term, prefix) || fallback && strings.HasPrefix(term, strings.ToLower(prefix)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package index_service
+
+import (
+	"Research/types/doc"
+	"Research/types/index"
+	"Research/types/term_query"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func textDoc(id string, fieldTexts ...string) *doc.Document {
+	d := &doc.Document{Id: id}
+	for i := 0; i+1 < len(fieldTexts); i += 2 {
+		d.Texts = append(d.Texts, &doc.TextField{Field: fieldTexts[i], Text: fieldTexts[i+1]})
+	}
+	return d
+}
+
+// 返回字段的片段，字段没有高亮时返回nil
+func fragmentsOf(highlight *index.DocHighlight, field string) []string {
+	if highlight == nil {
+		return nil
+	}
+	for _, f := range highlight.Fields {
+		if f.Field == field {
+			return f.Fragments
+		}
+	}
+	return nil
+}
+
+func TestHighlight(t *testing.T) {
+	const long = "go is fun. rust is fast. python is slow. go wins in the end"
+	goOrFast := keywordQuery("body", "go").Or(keywordQuery("body", "fast"))
+	cases := []struct {
+		name  string
+		conf  *index.Highlight
+		query *term_query.TermQuery
+		text  string
+		want  []string
+	}{
+		{"default tags", &index.Highlight{}, keywordQuery("body", "go"), "Go is fast", []string{"<em>Go</em> is fast"}},
+		{"custom tags", &index.Highlight{PreTag: "[", PostTag: "]"}, goOrFast, "Go is fast", []string{"[Go] is [fast]"}},
+		{"fragments", &index.Highlight{FragmentSize: 10}, goOrFast, long,
+			[]string{"<em>go</em> is fun.", "is <em>fast</em>. p", "ow. <em>go</em> win"}},
+		{"num fragments", &index.Highlight{FragmentSize: 10, NumFragments: 2}, goOrFast, long,
+			[]string{"<em>go</em> is fun.", "is <em>fast</em>. p"}},
+		// 匹配词多的片段优先，按原文顺序返回
+		{"best fragment", &index.Highlight{FragmentSize: 12, NumFragments: 1}, goOrFast, "fast and quick, but go go go wins",
+			[]string{" but <em>go</em> <em>go</em> g"}},
+		{"whole text", &index.Highlight{FragmentSize: -1}, goOrFast, long,
+			[]string{"<em>go</em> is fun. rust is <em>fast</em>. python is slow. <em>go</em> wins in the end"}},
+		{"phrase", &index.Highlight{}, term_query.NewPhraseQuery("body", 0, "rust", "is"), "rust is fast",
+			[]string{"<em>rust</em> <em>is</em> fast"}},
+		{"prefix", &index.Highlight{}, term_query.NewPrefixQuery("body", "Py"), "python and pypy", []string{"<em>python</em> and <em>pypy</em>"}},
+		// 排除条件中的词不高亮
+		{"must not", &index.Highlight{}, keywordQuery("body", "go").Not(keywordQuery("body", "fast")), "go fast", []string{"<em>go</em> fast"}},
+		{"no match", &index.Highlight{}, keywordQuery("body", "java"), "go is fast", nil},
+		{"other field", &index.Highlight{}, keywordQuery("title", "go"), "go is fast", nil},
+		{"field filter", &index.Highlight{Fields: []string{"title"}}, keywordQuery("body", "go"), "go is fast", nil},
+	}
+	for _, c := range cases {
+		h := newHighlighter(c.conf, c.query, nil)
+		got := fragmentsOf(h.highlight(textDoc("a", "body", c.text)), "body")
+		if !slices.Equal(got, c.want) {
+			t.Errorf("%s: got %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+// 片段去掉标签之后不超过片段长度
+func TestHighlightFragmentSize(t *testing.T) {
+	text := strings.Repeat("搜索引擎 go 检索 ", 20)
+	h := newHighlighter(&index.Highlight{FragmentSize: 8, NumFragments: 100}, keywordQuery("body", "go"), nil)
+	fragments := fragmentsOf(h.highlight(textDoc("a", "body", text)), "body")
+	if len(fragments) != 20 {
+		t.Fatalf("got %d fragments, want 20", len(fragments))
+	}
+	for _, fragment := range fragments {
+		if !strings.Contains(fragment, "<em>go</em>") {
+			t.Errorf("fragment %q has no match", fragment)
+		}
+		plain := strings.NewReplacer("<em>", "", "</em>", "").Replace(fragment)
+		if n := utf8.RuneCountInString(plain); n > 8 {
+			t.Errorf("fragment %q has %d runes", plain, n)
+		}
+	}
+}
+
+// 同一字段的多段文本一起选片段，字段按文档中的顺序返回
+func TestHighlightFields(t *testing.T) {
+	d := textDoc("a", "title", "Go search", "body", "nothing here", "body", "written in go", "tag", "go")
+	q := keywordQuery("title", "go").Or(keywordQuery("body", "go"), keywordQuery("tag", "go"))
+	highlight := newHighlighter(&index.Highlight{Fields: []string{"body", "title"}}, q, nil).highlight(d)
+	fields := make([]string, 0)
+	for _, f := range highlight.Fields {
+		fields = append(fields, f.Field)
+	}
+	if !slices.Equal(fields, []string{"title", "body"}) {
+		t.Errorf("fields %v", fields)
+	}
+	if got := fragmentsOf(highlight, "body"); !slices.Equal(got, []string{"written in <em>go</em>"}) {
+		t.Errorf("body %q", got)
+	}
+}
+
+func TestSearchHighlight(t *testing.T) {
+	eachIndexType(t, func(t *testing.T, indexer *Indexer) {
+		for _, d := range []*doc.Document{
+			{Id: "a", Keywords: []*doc.KeyWord{{Field: "title", Word: "go"}}, Texts: []*doc.TextField{{Field: "title", Text: "Go in action"}}},
+			{Id: "b", Keywords: []*doc.KeyWord{{Field: "title", Word: "go"}}},
+		} {
+			if _, err := indexer.AddDoc(d); err != nil {
+				t.Fatal(err)
+			}
+		}
+		res, err := indexer.Search(&index.SearchRequest{Query: keywordQuery("title", "go"), Highlight: &index.Highlight{PreTag: "<b>", PostTag: "</b>"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 没有文本字段的文档不返回高亮
+		if len(res.Results) != 2 || len(res.Highlights) != 1 || res.Highlights[0].Id != "a" {
+			t.Fatalf("results %v, highlights %v", resultIds(res.Results), res.Highlights)
+		}
+		if got := fragmentsOf(res.Highlights[0], "title"); !slices.Equal(got, []string{"<b>Go</b> in action"}) {
+			t.Errorf("got %q", got)
+		}
+	})
+}
@@ -1,40 +1,193 @@
 package index_service
 
 import (
+	"Research/analysis"
+	"Research/etc"
 	"Research/internal/kvdb"
+	"Research/schema"
 	"Research/types/doc"
-	"Research/types/term_query"
+	"Research/types/index"
 	"Research/util"
 	"bytes"
 	"encoding/gob"
-	"strings"
-	"sync/atomic"
+	"errors"
+	"os"
+	"sync"
+	"time"
 )
 import reverseindex "Research/internal/reverse_index"
 
+const (
+	defaultCheckpointInterval = time.Minute // 默认检查点间隔
+	defaultBatchSize          = 100         // 流式检索默认每批返回的文档数
+	maxStreamHits             = 10000       // 流式检索没有指定TopK时，节点上最多返回的文档数
+)
+
 // 外观Facade模式。把正排和倒排2个子系统封装到了一起
 type Indexer struct {
-	forwardIndex kvdb.IKeyValueDB
-	reverseIndex reverseindex.IReverseIndex
-	maxIntId     uint64
+	name          string // 索引名称，用于日志，为空表示默认索引
+	forwardIndex  kvdb.IKeyValueDB
+	reverseIndex  reverseindex.IReverseIndex
+	analyzer      *analysis.FieldAnalyzer // 文本分析器，未配置时为nil
+	schema        *schema.Schema          // 索引schema，未配置时为nil
+	maxIntId      uint64                  // 已分配的最大IntId
+	reservedIntId uint64                  // 已持久化到正排索引的预留IntId，重启后从这里继续分配
+	intIdLock     sync.Mutex              // 分配IntId时加锁
+	wal           *wal                    // 预写日志，未配置时为nil
+	walLock       sync.RWMutex            // 写操作持有读锁，检查点持有写锁，保证截断日志时没有执行到一半的写操作
+	docLocks      docLocks                // 按业务id加锁，写入条件的检查和写入在锁内完成
+	stop          chan struct{}           // 通知检查点和过期清理协程退出
 }
 
 // 初始化索引
-func (indexer *Indexer) Init(DocNumEstimate int, dbtype string, DataDir string) error {
-	db, err := kvdb.GetKvdb(dbtype, DataDir) //调用工厂方法，打开本地的KV数据库
+func (indexer *Indexer) Init(c *etc.Config) error {
+	var s *schema.Schema
+	if len(c.Schema) > 0 {
+		var err error
+		if s, err = schema.Load(c.Schema); err != nil {
+			return err
+		}
+	}
+	return indexer.init(c, s)
+}
+
+// 使用已加载的schema初始化索引，s为nil时不校验文档
+func (indexer *Indexer) init(c *etc.Config, s *schema.Schema) error {
+	db, err := kvdb.GetKvdb(&c.ForwardIndex) //调用工厂方法，打开本地的KV数据库
 	if err != nil {
 		return err
 	}
 	indexer.forwardIndex = db
-	// todo: 这里指定使用跳表，可以采用策略模式更换不同数据结构
-	indexer.reverseIndex = reverseindex.NewSkipListReverseIndex(DocNumEstimate)
+	// 恢复IntId分配器，避免重启后分配的IntId与已有文档冲突
+	if err = indexer.loadMaxIntId(); err != nil {
+		return err
+	}
+	// 根据配置选择倒排索引的数据结构
+	indexer.reverseIndex = reverseindex.NewReverseIndex(c.ReverseIndex.IndexType, c.ReverseIndex.DocNumEstimate)
+	analysisConf := c.Analysis
+	if s != nil {
+		indexer.schema = s
+		// schema中text字段指定的分析器优先于analysis中的配置
+		if analyzers := s.Analyzers(); len(analyzers) > 0 {
+			fields := make(map[string]string, len(analysisConf.Fields)+len(analyzers))
+			for field, name := range analysisConf.Fields {
+				fields[field] = name
+			}
+			for field, name := range analyzers {
+				fields[field] = name
+			}
+			analysisConf.Fields = fields
+		}
+	}
+	// 配置了文本分析时，从原始文本生成关键词，查询也用同样的分析器处理
+	if len(analysisConf.Default) > 0 || len(analysisConf.Fields) > 0 {
+		if indexer.analyzer, err = analysis.NewFieldAnalyzer(&analysisConf); err != nil {
+			return err
+		}
+	}
+	indexer.stop = make(chan struct{})
+	// 开启预写日志
+	if len(c.Wal.Path) > 0 {
+		if err = indexer.initWal(&c.Wal); err != nil {
+			return err
+		}
+	}
+	indexer.startSweeper(&c.Expire)
+	return nil
+}
+
+// 打开预写日志，把上次未做检查点的操作回放到正排索引，之后定期做检查点
+func (indexer *Indexer) initWal(conf *etc.Wal) error {
+	w, err := openWal(conf.Path)
+	if err != nil {
+		return err
+	}
+	indexer.wal = w
+	n, err := w.Replay(func(record *walRecord) error {
+		switch record.Op {
+		case walAdd:
+			if err := indexer.ensureIntId(record.IntId); err != nil {
+				return err
+			}
+			old, err := indexer.getDoc(record.DocId)
+			if err != nil && !errors.Is(err, kvdb.ErrNoData) {
+				return err
+			}
+			if err = indexer.forwardIndex.BatchSet(
+				[][]byte{[]byte(record.DocId), intIdKey(record.IntId)},
+				[][]byte{record.Doc, []byte(record.DocId)}); err != nil {
+				return err
+			}
+			// 与AddDocIf一致，覆盖了旧文档时删除旧IntId的映射；同一条记录已经写入过时IntId相同，不需要删除
+			if old != nil && old.IntId != record.IntId {
+				indexer.dropReplaced(old)
+			}
+			return nil
+		case walDelete:
+			return indexer.forwardIndex.BatchDelete([][]byte{[]byte(record.DocId), intIdKey(record.IntId)})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	util.Log.Printf("replay %d records from wal %s", n, conf.Path)
+	if err = indexer.Checkpoint(); err != nil {
+		return err
+	}
+
+	interval := time.Duration(conf.CheckpointInterval) * time.Second
+	if interval <= 0 {
+		interval = defaultCheckpointInterval
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-indexer.stop:
+				return
+			case <-ticker.C:
+				if err := indexer.Checkpoint(); err != nil {
+					util.Log.Printf("wal checkpoint failed: %s", err)
+				}
+			}
+		}
+	}()
 	return nil
 }
 
+// 检查点：把正排索引刷到磁盘后清空预写日志
+func (indexer *Indexer) Checkpoint() error {
+	if indexer.wal == nil {
+		return nil
+	}
+	indexer.walLock.Lock()
+	defer indexer.walLock.Unlock()
+	if syncer, ok := indexer.forwardIndex.(kvdb.ISyncer); ok {
+		if err := syncer.Sync(); err != nil {
+			return err
+		}
+	}
+	return indexer.wal.Truncate()
+}
+
+// 写入预写日志，未开启时直接返回
+func (indexer *Indexer) appendWal(op byte, docId string, intId uint64, docByte []byte) error {
+	if indexer.wal == nil {
+		return nil
+	}
+	return indexer.wal.Append(&walRecord{Op: op, DocId: docId, IntId: intId, Doc: docByte})
+}
+
 // 从正排索引加载文件到倒排索引
 func (indexer *Indexer) LoadFromIndexFile(rate float64) int {
 	reader := bytes.NewReader([]byte{})
-	n := indexer.forwardIndex.IterKeyByWeight(rate, func(k, v []byte) error {
+	loaded := 0
+	indexer.forwardIndex.IterKeyByWeight(rate, func(k, v []byte) error {
+		if isMetaKey(k) {
+			return nil
+		}
 		reader.Reset(v)
 		gobDecode := gob.NewDecoder(reader) // 构造gob反序列化器
 		// 反序列化
@@ -44,51 +197,451 @@ func (indexer *Indexer) LoadFromIndexFile(rate float64) int {
 			util.Log.Printf("gob decode document failed：%s", err)
 			return nil
 		}
-		indexer.reverseIndex.Add(doc)
+		// 校验文档的IntId没有超过分配器的最大值
+		if err = indexer.ensureIntId(doc.IntId); err != nil {
+			util.Log.Printf("save max IntId failed: %s", err)
+		}
+		indexer.reverseIndex.Add(*indexer.indexed(&doc))
+		loaded++
 		return err
 	})
-	util.Log.Printf("load %d data from forward index %s", n, indexer.forwardIndex.GetDbPath())
-	indexer.maxIntId = uint64(n)
-	return int(n)
+	util.Log.Printf("load %d data from forward index of %s", loaded, indexer.Name())
+	return loaded
 }
 
 // 关闭索引
 func (indexer *Indexer) Close() error {
+	close(indexer.stop)
+	if indexer.wal != nil {
+		// 正常关闭时做一次检查点，下次启动不需要回放
+		if err := indexer.Checkpoint(); err != nil {
+			util.Log.Printf("wal checkpoint failed: %s", err)
+		}
+		_ = indexer.wal.Close()
+	}
 	return indexer.forwardIndex.Close()
 }
 
+// 删除索引的全部数据并关闭索引
+func (indexer *Indexer) Drop() error {
+	keys := make([][]byte, 0)
+	indexer.forwardIndex.IterKey(func(k []byte) error {
+		keys = append(keys, append([]byte{}, k...))
+		return nil
+	})
+	if len(keys) > 0 {
+		if err := indexer.forwardIndex.BatchDelete(keys); err != nil {
+			return err
+		}
+	}
+	if err := indexer.Close(); err != nil {
+		return err
+	}
+	if indexer.wal != nil {
+		return os.Remove(indexer.wal.path)
+	}
+	return nil
+}
+
 // 向索引中添加(亦是更新)文档(如果已存在，会先删除)
 func (indexer *Indexer) AddDoc(doc *doc.Document) (int, error) {
-	docId := strings.TrimSpace(doc.Id)
-	if len(docId) == 0 {
-		return 0, nil
+	return indexer.AddDocIf(doc, nil)
+}
+
+// 满足条件时添加文档，不满足时返回*ConflictError，成功后doc.Version是新的版本
+func (indexer *Indexer) AddDocIf(doc *doc.Document, cond *index.Condition) (int, error) {
+	indexer.walLock.RLock()
+	defer indexer.walLock.RUnlock()
+	unlock := indexer.docLocks.lock(normalizeDocId(doc.Id))
+	defer unlock()
+	value, old, err := indexer.prepareDoc(doc, cond)
+	if err != nil {
+		if errors.Is(err, errEmptyDocId) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	// 先写日志，再写索引
+	if err := indexer.appendWal(walAdd, doc.Id, doc.IntId, value); err != nil {
+		return 0, err
 	}
-	//先从正排和倒排索引上将docId删除
-	indexer.DeleteDoc(docId)
+	// 写入正排索引，同时记录IntId到业务id的映射，旧文档被覆盖
+	err = indexer.forwardIndex.BatchSet(
+		[][]byte{[]byte(doc.Id), intIdKey(doc.IntId)},
+		[][]byte{value, []byte(doc.Id)})
+	if err != nil {
+		return 0, err
+	}
+
+	//新文档写入成功后才删除旧文档的倒排，再写入倒排索引
+	if old != nil {
+		indexer.dropReplaced(old)
+	}
+	indexer.reverseIndex.Add(*indexer.indexed(doc))
+	return 1, nil
+}
+
+// 批量添加文档，正排索引一次写入，倒排索引每个key只加一次锁，返回每个文档是否成功
+// 同一批中业务id重复时只添加最后一个，前面的返回失败
+func (indexer *Indexer) AddDocs(docs []*doc.Document) *index.BulkAddResult {
+	return indexer.addDocs(docs, nil)
+}
+
+// 批量添加文档，conds为nil或与docs一一对应，不满足条件的文档标记为冲突
+func (indexer *Indexer) addDocs(docs []*doc.Document, conds []*index.Condition) *index.BulkAddResult {
+	res := &index.BulkAddResult{Items: make([]*index.BulkItem, len(docs))}
+	last := make(map[string]int, len(docs)) // 业务id -> 这一批中最后一个该id的文档下标
+	for i, d := range docs {
+		last[normalizeDocId(d.Id)] = i
+	}
+	indexer.walLock.RLock()
+	defer indexer.walLock.RUnlock()
+	docIds := make([]string, 0, len(last))
+	for docId := range last {
+		docIds = append(docIds, docId)
+	}
+	unlock := indexer.docLocks.lock(docIds...)
+	defer unlock()
+
+	values := make([][]byte, len(docs))
+	added := make([]*doc.Document, 0, len(docs))
+	replaced := make([]*doc.Document, 0) // 被覆盖的旧文档
+	for i, d := range docs {
+		item := &index.BulkItem{Id: d.Id}
+		res.Items[i] = item
+		if docId := normalizeDocId(d.Id); len(docId) > 0 && last[docId] != i {
+			item.Error = errDuplicateDocId.Error()
+			continue
+		}
+		var cond *index.Condition
+		if conds != nil {
+			cond = conds[i]
+		}
+		value, old, err := indexer.prepareDoc(d, cond)
+		if err == nil {
+			err = indexer.appendWal(walAdd, d.Id, d.IntId, value)
+		}
+		if err != nil {
+			var conflict *ConflictError
+			if errors.As(err, &conflict) {
+				item.Conflict, item.Version = true, conflict.Version
+			}
+			item.Error = err.Error()
+			continue
+		}
+		values[i] = value
+		added = append(added, d)
+		if old != nil {
+			replaced = append(replaced, old)
+		}
+		item.Ok, item.Version = true, d.Version
+	}
+	if len(added) == 0 {
+		return res
+	}
+
+	// 写入正排索引，同时记录IntId到业务id的映射
+	keys := make([][]byte, 0, 2*len(added))
+	vals := make([][]byte, 0, 2*len(added))
+	for i, d := range docs {
+		if res.Items[i].Ok {
+			keys = append(keys, []byte(d.Id), intIdKey(d.IntId))
+			vals = append(vals, values[i], []byte(d.Id))
+		}
+	}
+	if err := indexer.forwardIndex.BatchSet(keys, vals); err != nil {
+		util.Log.Printf("batch set docs failed: %s", err)
+		for _, item := range res.Items {
+			if item.Ok {
+				item.Ok, item.Version, item.Error = false, 0, err.Error()
+			}
+		}
+		return res
+	}
+
+	//新文档写入成功后才删除旧文档的倒排，再写入倒排索引
+	if len(replaced) > 0 {
+		indexer.dropReplaced(replaced...)
+	}
+	for i, d := range added {
+		added[i] = indexer.indexed(d)
+	}
+	indexer.reverseIndex.BatchAdd(added)
+	res.Count = int32(len(added))
+	return res
+}
+
+// 部分更新文档，只修改请求中给出的部分，IntId不变，倒排索引只修改有变化的倒排链
+// 文档不存在时返回0，不满足request.Condition时返回*ConflictError
+func (indexer *Indexer) UpdateDoc(request *index.UpdateDocRequest) (int, error) {
+	updated, err := indexer.updateDoc(request)
+	if updated == nil {
+		return 0, err
+	}
+	return 1, err
+}
 
-	doc.IntId = atomic.AddUint64(&indexer.maxIntId, 1) //写入索引时自动为文档生成IntId
-	//写入正排索引
+// 部分更新文档，返回更新后的文档，文档不存在或更新失败时返回nil
+func (indexer *Indexer) updateDoc(request *index.UpdateDocRequest) (*doc.Document, error) {
+	docId := normalizeDocId(request.DocId)
+	if len(docId) == 0 || isMetaKey([]byte(docId)) {
+		return nil, nil
+	}
+	indexer.walLock.RLock()
+	defer indexer.walLock.RUnlock()
+	unlock := indexer.docLocks.lock(docId)
+	defer unlock()
+	old, err := indexer.getDoc(docId)
+	if err != nil {
+		if errors.Is(err, kvdb.ErrNoData) {
+			return nil, checkCondition(docId, 0, request.Condition)
+		}
+		return nil, err
+	}
+	if err = checkCondition(docId, old.Version, request.Condition); err != nil {
+		return nil, err
+	}
+	updated := indexer.patchDoc(old, request)
+	if indexer.schema != nil {
+		if err = indexer.schema.Validate(updated); err != nil {
+			return nil, err
+		}
+		updated.BitsFeature, _ = indexer.schema.NormalizeFeatures(updated.BitsFeature)
+		indexer.schema.DropUnstored(updated)
+	}
+	var value bytes.Buffer
+	if err = gob.NewEncoder(&value).Encode(updated); err != nil {
+		return nil, err
+	}
+	// 先写日志，再写索引，IntId不变，映射不需要修改
+	if err = indexer.appendWal(walAdd, docId, updated.IntId, value.Bytes()); err != nil {
+		return nil, err
+	}
+	if err = indexer.forwardIndex.Set([]byte(docId), value.Bytes()); err != nil {
+		return nil, err
+	}
+	indexer.reverseIndex.Update(indexer.indexed(old), indexer.indexed(updated))
+	return updated, nil
+}
+
+// 在旧文档上应用更新，返回新文档，不修改旧文档
+func (indexer *Indexer) patchDoc(old *doc.Document, request *index.UpdateDocRequest) *doc.Document {
+	fieldSet := func(fields ...string) map[string]struct{} {
+		set := make(map[string]struct{}, len(fields))
+		for _, field := range fields {
+			set[field] = struct{}{}
+		}
+		return set
+	}
+	removed := fieldSet(request.RemoveFields...)
+	keywordFields, numericFields, textFields := fieldSet(), fieldSet(), fieldSet()
+	for _, keyword := range request.Keywords {
+		keywordFields[keyword.Field] = struct{}{}
+	}
+	for _, numeric := range request.Numerics {
+		numericFields[numeric.Field] = struct{}{}
+	}
+	for _, text := range request.Texts {
+		// 文本的关键词由分析生成，替换文本时一起替换
+		textFields[text.Field] = struct{}{}
+		keywordFields[text.Field] = struct{}{}
+	}
+	keep := func(field string, replaced map[string]struct{}) bool {
+		_, isReplaced := replaced[field]
+		_, isRemoved := removed[field]
+		return !isReplaced && !isRemoved
+	}
+
+	updated := &doc.Document{Id: old.Id, IntId: old.IntId, BitsFeature: old.BitsFeature, Bytes: old.Bytes, Version: old.Version + 1}
+	for _, keyword := range old.Keywords {
+		if keep(keyword.Field, keywordFields) {
+			updated.Keywords = append(updated.Keywords, keyword)
+		}
+	}
+	updated.Keywords = append(updated.Keywords, request.Keywords...)
+	if indexer.analyzer != nil && len(request.Texts) > 0 {
+		updated.Keywords = append(updated.Keywords, indexer.analyzer.Keywords(request.Texts)...)
+	}
+	for _, numeric := range old.Numerics {
+		if keep(numeric.Field, numericFields) {
+			updated.Numerics = append(updated.Numerics, numeric)
+		}
+	}
+	updated.Numerics = append(updated.Numerics, request.Numerics...)
+	for _, text := range old.Texts {
+		if keep(text.Field, textFields) {
+			updated.Texts = append(updated.Texts, text)
+		}
+	}
+	updated.Texts = append(updated.Texts, request.Texts...)
+	if request.UpdateBytes {
+		updated.Bytes = request.Bytes
+	}
+	updated.ExpireAt = old.ExpireAt
+	if request.UpdateExpireAt {
+		updated.ExpireAt = request.ExpireAt
+	}
+	if len(request.SetBits) > 0 || len(request.ClearBits) > 0 {
+		updated.BitsFeature = patchFeatures(old.BitsFeature, request.SetBits, request.ClearBits)
+	}
+	return updated
+}
+
+// 在旧特征的副本上置位和清除，容量不够时扩大
+func patchFeatures(old *util.Bitmap, set, clear []int32) *util.Bitmap {
+	capacity := 0
+	if old != nil {
+		capacity = old.Cap()
+	}
+	for _, bit := range set {
+		if int(bit) >= capacity {
+			capacity = int(bit) + 1
+		}
+	}
+	res := util.NewBitmap(capacity)
+	if old != nil {
+		for i := 1; i < old.Cap(); i++ {
+			if value, _ := old.GetBit(i); value == 1 {
+				res.SetBit(i)
+			}
+		}
+	}
+	for _, bit := range set {
+		res.SetBit(int(bit))
+	}
+	for _, bit := range clear {
+		res.ClearBit(int(bit))
+	}
+	return res
+}
+
+// 写入文档之前的准备：校验文档、检查写入条件、分配IntId和版本、分析原始文本，返回序列化后的文档和被覆盖的旧文档
+// 旧文档不在这里删除，调用方写入新文档之后调用dropReplaced，写入失败时旧文档保持不变
+// 调用方需持有walLock的读锁和文档锁
+func (indexer *Indexer) prepareDoc(doc *doc.Document, cond *index.Condition) ([]byte, *doc.Document, error) {
+	doc.Id = normalizeDocId(doc.Id)
+	if len(doc.Id) == 0 {
+		return nil, nil, errEmptyDocId
+	}
+	if isMetaKey([]byte(doc.Id)) {
+		return nil, nil, errMetaDocId
+	}
+	if indexer.schema != nil {
+		if err := indexer.schema.Validate(doc); err != nil {
+			return nil, nil, err
+		}
+		// 统一BitsFeature的容量，和按特征名称编译的检索条件一致
+		doc.BitsFeature, _ = indexer.schema.NormalizeFeatures(doc.BitsFeature)
+	}
+	old, err := indexer.getDoc(doc.Id)
+	if err != nil && !errors.Is(err, kvdb.ErrNoData) {
+		return nil, nil, err
+	}
+	var version uint64
+	if old != nil {
+		version = old.Version
+	}
+	if err = checkCondition(doc.Id, version, cond); err != nil {
+		return nil, nil, err
+	}
+	doc.Version = version + 1
+
+	intId, err := indexer.allocIntId() //写入索引时自动为文档生成IntId
+	if err != nil {
+		return nil, nil, err
+	}
+	doc.IntId = intId
+	// 分析原始文本生成关键词，和文档一起存入正排索引，删除时可以找到对应的倒排
+	if indexer.analyzer != nil && len(doc.Texts) > 0 {
+		doc.Keywords = append(doc.Keywords, indexer.analyzer.Keywords(doc.Texts)...)
+	}
+	if indexer.schema != nil {
+		indexer.schema.DropUnstored(doc)
+	}
 	var value bytes.Buffer
 	encoder := gob.NewEncoder(&value) // 构造编码器，传输到缓冲区
+	if err := encoder.Encode(doc); err != nil {
+		return nil, nil, err
+	}
+	return value.Bytes(), old, nil
+}
 
-	if err := encoder.Encode(doc); err == nil {
-		_ = indexer.forwardIndex.Set([]byte(docId), value.Bytes())
-		// 写入正排索引
-	} else {
-		return 0, err
+// 新文档已覆盖正排索引中的旧文档，只需从倒排索引删除旧文档，再删除旧IntId的映射
+// 日志中的添加操作会覆盖旧文档，删除不需要单独记录
+func (indexer *Indexer) dropReplaced(olds ...*doc.Document) {
+	keys := make([][]byte, 0, len(olds))
+	for _, old := range olds {
+		indexer.unindex(old)
+		keys = append(keys, intIdKey(old.IntId))
 	}
+	if err := indexer.forwardIndex.BatchDelete(keys); err != nil {
+		util.Log.Printf("delete replaced IntId failed: %s", err)
+	}
+}
 
-	//写入倒排索引
-	indexer.reverseIndex.Add(*doc)
-	return 1, nil
+// 返回需要写入倒排索引的文档，去掉schema中不需要索引的字段，过期时间作为数值字段写入
+func (indexer *Indexer) indexed(doc *doc.Document) *doc.Document {
+	expire := expireNumeric(doc)
+	if indexer.schema != nil {
+		doc = indexer.schema.Indexed(doc)
+	}
+	if expire != nil {
+		doc = withExpire(doc, expire)
+	}
+	return doc
+}
+
+// 索引名称，用于日志，不依赖正排索引的存储路径(redis没有路径)
+func (indexer *Indexer) Name() string {
+	if len(indexer.name) == 0 {
+		return DEFAULT_INDEX
+	}
+	return indexer.name
+}
+
+// 索引的schema，未配置时返回nil，可以用来把特征名称编译成检索条件
+func (indexer *Indexer) Schema() *schema.Schema {
+	return indexer.schema
 }
 
 // 删除文档
 func (indexer *Indexer) DeleteDoc(docId string) int {
-	// 读取正排索引
+	n, _ := indexer.DeleteDocIf(docId, nil)
+	return n
+}
+
+// 满足条件时删除文档，不满足时返回*ConflictError
+func (indexer *Indexer) DeleteDocIf(docId string, cond *index.Condition) (int, error) {
+	docId = normalizeDocId(docId)
+	if len(docId) == 0 || isMetaKey([]byte(docId)) {
+		return 0, nil
+	}
+	indexer.walLock.RLock()
+	defer indexer.walLock.RUnlock()
+	unlock := indexer.docLocks.lock(docId)
+	defer unlock()
+	doc, err := indexer.getDoc(docId)
+	if err != nil {
+		if errors.Is(err, kvdb.ErrNoData) {
+			return 0, checkCondition(docId, 0, cond)
+		}
+		return 0, err
+	}
+	if err = checkCondition(docId, doc.Version, cond); err != nil {
+		return 0, err
+	}
+	if err = indexer.appendWal(walDelete, docId, doc.IntId, nil); err != nil {
+		util.Log.Printf("write wal failed: %s", err)
+		return 0, err
+	}
+	return indexer.removeDoc(doc), nil
+}
+
+// 从正排索引读取文档
+func (indexer *Indexer) getDoc(docId string) (*doc.Document, error) {
 	docByte, err := indexer.forwardIndex.Get([]byte(docId))
 	if err != nil {
-		return 0
+		return nil, err
 	}
 	//反序列化为文档
 	reader := bytes.NewReader([]byte{})
@@ -97,59 +650,264 @@ func (indexer *Indexer) DeleteDoc(docId string) int {
 	var doc doc.Document
 	err = decoder.Decode(&doc)
 	if err != nil {
-		return 0
+		return nil, err
 	}
+	return &doc, nil
+}
+
+// 从正排和倒排索引上删除文档，不写日志
+func (indexer *Indexer) removeDoc(doc *doc.Document) int {
+	indexer.unindex(doc)
+	// 删除正排索引和IntId映射
+	_ = indexer.forwardIndex.BatchDelete([][]byte{[]byte(doc.Id), intIdKey(doc.IntId)})
+	return 1
+}
+
+// 从倒排索引上删除文档
+func (indexer *Indexer) unindex(doc *doc.Document) {
 	//读取文档关键字，删除倒排索引
 	for _, keyWord := range doc.Keywords {
 		indexer.reverseIndex.Delete(doc.IntId, keyWord)
 	}
-	// 删除正排索引
-	_ = indexer.forwardIndex.Delete([]byte(docId))
-	return 1
+	for _, numeric := range doc.Numerics {
+		indexer.reverseIndex.DeleteNumeric(doc.IntId, numeric)
+	}
+	if expire := expireNumeric(doc); expire != nil {
+		indexer.reverseIndex.DeleteNumeric(doc.IntId, expire)
+	}
 }
 
 // 检索文档
-// 从倒排索引中查询文档业务id，再从正排索引查询完整文档
-func (indexer *Indexer) Search(query *term_query.TermQuery, onFlag *util.Bitmap, offFlag *util.Bitmap, orFlags []*util.Bitmap) []*doc.Document {
+// 从倒排索引中查询文档业务id，再从正排索引查询一页的完整文档，结果按相关性得分降序
+func (indexer *Indexer) Search(request *index.SearchRequest) (*index.SearchResult, error) {
+	if err := checkPage(request); err != nil {
+		return &index.SearchResult{}, err
+	}
+	cursor, err := decodeCursor(request.Cursor)
+	if err != nil {
+		return &index.SearchResult{}, err
+	}
 	// 1、从倒排索引中查询文档业务id
-	searchResult := indexer.reverseIndex.Search(query, onFlag, offFlag, orFlags)
-	if len(searchResult) == 0 {
-		return nil
+	// 没有游标时只需要排在前面的offset+limit个文档，多取一个用来判断是否还有下一页
+	topK := int(request.TopK)
+	if window := pageWindow(request); window > 0 && cursor == nil && (topK <= 0 || window < topK) {
+		topK = window + 1
+	}
+	query := request.Query
+	if indexer.analyzer != nil && query != nil {
+		query = indexer.analyzer.AnalyzeQuery(query)
+	}
+	// 过期的文档在清理之前也不返回，也不参与聚合
+	now := time.Now().UnixMilli()
+	hits, aggregations := indexer.reverseIndex.Search(notExpired(query, now), request.OnFlag, request.OffFlag, request.OrFlags, topK, request.Aggregations)
+	hits = afterCursor(hits, cursor)
+	start, end, more := pageRange(len(hits), request, false)
+	hits = hits[start:end]
+	if len(hits) == 0 {
+		return &index.SearchResult{Aggregations: aggregations}, nil
 	}
 	// 2、从正排索引查询完整文档
-	keys := make([][]byte, 0, len(searchResult))
-	for _, id := range searchResult {
-		keys = append(keys, []byte(id))
+	result, err := indexer.loadHits(hits)
+	if err != nil {
+		util.Log.Printf("get docs from forward index failed: %s", err)
+		return &index.SearchResult{}, err
+	}
+	res := &index.SearchResult{Results: result, Aggregations: aggregations}
+	// 3、在存储的文本字段中高亮查询词
+	if request.Highlight != nil {
+		h := newHighlighter(request.Highlight, query, indexer.analyzer)
+		for _, d := range result {
+			if highlight := h.highlight(d); highlight != nil {
+				res.Highlights = append(res.Highlights, highlight)
+			}
+		}
+	}
+	if more {
+		last := hits[len(hits)-1]
+		res.NextCursor = encodeCursor(last.Score, last.Id)
+	}
+	return res, nil
+}
+
+// 流式检索，从倒排索引查询所有匹配的文档后，每次从正排索引读取一批完整文档交给fn，不计算聚合
+// 结果按相关性得分降序，fn返回错误时停止
+// 倒排索引需要给所有匹配的文档打分排序后才能返回，命中列表(业务id和得分)占用的内存与命中数成正比，
+// 所以只保留前TopK个，没有指定TopK和Limit时最多返回maxStreamHits个，被截断时最后一批带上最后一个文档的游标，用它继续检索
+func (indexer *Indexer) SearchStream(request *index.SearchRequest, fn func(batch *index.SearchBatch) error) error {
+	if err := checkPage(request); err != nil {
+		return err
+	}
+	cursor, err := decodeCursor(request.Cursor)
+	if err != nil {
+		return err
+	}
+	query := request.Query
+	if indexer.analyzer != nil && query != nil {
+		query = indexer.analyzer.AnalyzeQuery(query)
+	}
+	topK := int(request.TopK)
+	if window := pageWindow(request); window > 0 && cursor == nil && (topK <= 0 || window < topK) {
+		topK = window
+	}
+	capped := topK <= 0 && pageWindow(request) == 0 // 没有指定数量，最多返回maxStreamHits个
+	if capped && cursor == nil {
+		// 多取一个，用来判断是否被截断
+		topK = maxStreamHits + 1
+	}
+	hits, _ := indexer.reverseIndex.Search(notExpired(query, time.Now().UnixMilli()), request.OnFlag, request.OffFlag, request.OrFlags, topK, nil)
+	hits = afterCursor(hits, cursor)
+	var nextCursor string
+	if capped && len(hits) > maxStreamHits {
+		// 有游标时游标之前的文档不能提前去掉，在过滤之后再截断
+		hits = hits[:maxStreamHits]
+		last := hits[len(hits)-1]
+		nextCursor = encodeCursor(last.Score, last.Id)
+	}
+	start, end, _ := pageRange(len(hits), request, false)
+	hits = hits[start:end]
+	if len(hits) == 0 && len(nextCursor) > 0 {
+		// 跳过了截断后的全部文档，仍然要返回游标
+		return fn(&index.SearchBatch{NextCursor: nextCursor})
+	}
+
+	batchSize := int(request.BatchSize)
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	var h *highlighter
+	if request.Highlight != nil {
+		h = newHighlighter(request.Highlight, query, indexer.analyzer)
+	}
+	for start := 0; start < len(hits); start += batchSize {
+		docs, err := indexer.loadHits(hits[start:min(start+batchSize, len(hits))])
+		if err != nil {
+			return err
+		}
+		batch := &index.SearchBatch{Docs: docs}
+		if start+batchSize >= len(hits) {
+			batch.NextCursor = nextCursor
+		}
+		if h != nil {
+			for _, d := range docs {
+				if highlight := h.highlight(d); highlight != nil {
+					batch.Highlights = append(batch.Highlights, highlight)
+				}
+			}
+		}
+		if err = fn(batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 去掉游标之前的文档
+func afterCursor(hits []*reverseindex.SearchHit, cursor *searchCursor) []*reverseindex.SearchHit {
+	if cursor == nil {
+		return hits
+	}
+	n := 0
+	for _, hit := range hits {
+		if cursor.after(hit.Score, hit.Id) {
+			hits[n] = hit
+			n++
+		}
+	}
+	return hits[:n]
+}
+
+// 从正排索引读取命中的完整文档，按hits的顺序返回并带上得分，已删除的文档不返回
+func (indexer *Indexer) loadHits(hits []*reverseindex.SearchHit) ([]*doc.Document, error) {
+	keys := make([][]byte, 0, len(hits))
+	for _, hit := range hits {
+		keys = append(keys, []byte(hit.Id))
 	}
 	docs, err := indexer.forwardIndex.BatchGet(keys)
 	if err != nil {
-		return nil
+		return nil, err
 	}
-	//3、序列化文档
+	//反序列化文档
 	reader := bytes.NewReader([]byte{})
-	result := make([]*doc.Document, 0, len(searchResult))
-	for _, docByte := range docs {
+	result := make([]*doc.Document, 0, len(hits))
+	for i, docByte := range docs {
 		if len(docByte) == 0 {
 			continue
 		}
-		//反序列化
 		reader.Reset(docByte)
 		decoder := gob.NewDecoder(reader)
 		var doc doc.Document
-		err = decoder.Decode(&doc)
-		if err != nil {
-			return nil
+		if err = decoder.Decode(&doc); err != nil {
+			return nil, err
 		}
-		// 添加反序列化结果
+		// BatchGet按keys的顺序返回，与hits一一对应
+		doc.Score = hits[i].Score
 		result = append(result, &doc)
 	}
-	return result
+	return result, nil
+}
+
+// 根据业务id从正排索引读取文档，不存在时返回nil
+func (indexer *Indexer) GetDoc(docId string) *doc.Document {
+	docId = normalizeDocId(docId)
+	if len(docId) == 0 || isMetaKey([]byte(docId)) {
+		return nil
+	}
+	document, err := indexer.getDoc(docId)
+	if err != nil {
+		if !errors.Is(err, kvdb.ErrNoData) {
+			util.Log.Printf("get doc %s failed: %s", docId, err)
+		}
+		return nil
+	}
+	return document
+}
+
+// 批量读取文档，按docIds的顺序返回，不存在的文档不返回
+func (indexer *Indexer) MultiGetDoc(docIds []string) []*doc.Document {
+	keys := make([][]byte, 0, len(docIds))
+	for _, docId := range docIds {
+		docId = normalizeDocId(docId)
+		if len(docId) == 0 || isMetaKey([]byte(docId)) {
+			continue
+		}
+		keys = append(keys, []byte(docId))
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	values, err := indexer.forwardIndex.BatchGet(keys)
+	if err != nil {
+		util.Log.Printf("get docs from forward index failed: %s", err)
+		return nil
+	}
+	reader := bytes.NewReader([]byte{})
+	res := make([]*doc.Document, 0, len(values))
+	for i, docByte := range values {
+		if len(docByte) == 0 {
+			continue
+		}
+		reader.Reset(docByte)
+		var document doc.Document
+		if err = gob.NewDecoder(reader).Decode(&document); err != nil {
+			util.Log.Printf("gob decode doc %s failed: %s", keys[i], err)
+			continue
+		}
+		res = append(res, &document)
+	}
+	return res
+}
+
+// 输入提示，用倒排索引词典中以前缀开头的词补全
+func (indexer *Indexer) Suggest(request *index.SuggestRequest) *index.SuggestResult {
+	return &index.SuggestResult{Suggestions: indexer.reverseIndex.Suggest(request)}
 }
 
 func (indexer *Indexer) Count() int {
 	res := 0
 	indexer.forwardIndex.IterKey(func(k []byte) error {
-		res++
+		if !isMetaKey(k) {
+			res++
+		}
 		return nil
 	})
 	return res
@@ -2,41 +2,51 @@ package index_service
 
 import (
 	ServiceHub2 "Research/ServiceHub"
+	"Research/etc"
 	"Research/types/doc"
 	"Research/types/index"
 	"context"
 	clientv3 "go.etcd.io/etcd/client/v3"
+	"io"
 	"time"
 )
 
 const (
 	INDEX_SERVICE = "index_service"
+	bulkBatchSize = 1000 // 批量添加时每批写入的文档数
 )
 
 type IndexServiceWorker struct {
-	Indexer  *Indexer
+	Indexes  *IndexRegistry // 节点上的所有索引
 	hub      ServiceHub2.IServiceHub
 	Endpoint *ServiceHub2.EndPoint
 }
 
 // 创建IndexServiceWorker，
-// DocNumEstimate 对文档数量的预估
-// dbtype 正排索引类型
-// DataDir 正排索引地址
+// c 索引配置，包括正排索引、倒排索引和预写日志，命名索引在此基础上使用各自的命名空间
 // endpoint 节点信息
-func NewIndexServiceWorker(DocNumEstimate int, dbtype string, DataDir string, endpoint *ServiceHub2.EndPoint) (*IndexServiceWorker, error) {
+func NewIndexServiceWorker(c *etc.Config, endpoint *ServiceHub2.EndPoint) (*IndexServiceWorker, error) {
 	service := &IndexServiceWorker{}
-	// 1、初始化索引
-	service.Indexer = new(Indexer)
-	err := service.Indexer.Init(DocNumEstimate, dbtype, DataDir)
+	// 1、打开默认索引和已创建的命名索引
+	indexes, err := NewIndexRegistry(c)
 	if err != nil {
 		return nil, err
 	}
+	service.Indexes = indexes
 	// 2、设置本机信息
 	service.Endpoint = endpoint
 	return service, nil
 }
 
+// 按比例从正排索引加载所有索引的文档到倒排索引
+func (service *IndexServiceWorker) LoadFromIndexFile(rate float64) int {
+	loaded := 0
+	service.Indexes.Range(func(name string, indexer *Indexer) {
+		loaded += indexer.LoadFromIndexFile(rate)
+	})
+	return loaded
+}
+
 // 进行服务注册
 func (service *IndexServiceWorker) Regist(hub ServiceHub2.IServiceHub, heartBeat int64) error {
 	//1、设置注册中心
@@ -64,27 +74,174 @@ func (service *IndexServiceWorker) Close() error {
 	if service.hub != nil {
 		service.hub.UnRegist(INDEX_SERVICE, service.Endpoint)
 	}
-	return service.Indexer.Close()
+	return service.Indexes.Close()
 }
 
 // 从索引上删除文档
 func (service *IndexServiceWorker) DeleteDoc(ctx context.Context, docId *index.DocId) (*index.AffectedCount, error) {
-	return &index.AffectedCount{Count: int32(service.Indexer.DeleteDoc(docId.DocId))}, nil
+	indexer, err := service.Indexes.Get(docId.Index)
+	if err != nil {
+		return &index.AffectedCount{}, err
+	}
+	n, err := indexer.DeleteDocIf(docId.DocId, docId.Condition)
+	return affectedCount(n, 0, err)
 }
 
-// 向索引中添加文档(如果已存在，会先删除)
-func (service *IndexServiceWorker) AddDoc(ctx context.Context, doc *doc.Document) (*index.AffectedCount, error) {
-	n, err := service.Indexer.AddDoc(doc)
-	return &index.AffectedCount{Count: int32(n)}, err
+// 向默认索引中添加文档(如果已存在，会先删除)
+func (service *IndexServiceWorker) AddDoc(ctx context.Context, document *doc.Document) (*index.AffectedCount, error) {
+	return service.AddDocTo(ctx, &index.AddDocRequest{Doc: document})
+}
+
+// 向指定索引中添加文档(如果已存在，会先删除)，不满足写入条件时返回Conflict
+func (service *IndexServiceWorker) AddDocTo(ctx context.Context, request *index.AddDocRequest) (*index.AffectedCount, error) {
+	indexer, err := service.Indexes.Get(request.Index)
+	if err != nil {
+		return &index.AffectedCount{}, err
+	}
+	if request.Doc == nil {
+		request.Doc = &doc.Document{}
+	}
+	n, err := indexer.AddDocIf(request.Doc, request.Condition)
+	return affectedCount(n, request.Doc.Version, err)
+}
+
+// 批量添加文档，客户端流式发送，攒够一批或索引变化时写入一次，结果与发送的文档一一对应
+func (service *IndexServiceWorker) BulkAdd(stream index.IndexService_BulkAddServer) error {
+	res := &index.BulkAddResult{}
+	batch := make([]*doc.Document, 0, bulkBatchSize)
+	conds := make([]*index.Condition, 0, bulkBatchSize)
+	name := "" // 当前这批文档的索引名称
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if indexer, err := service.Indexes.Get(name); err != nil {
+			for _, document := range batch {
+				res.Items = append(res.Items, &index.BulkItem{Id: document.Id, Error: err.Error()})
+			}
+		} else {
+			result := indexer.addDocs(batch, conds)
+			res.Count += result.Count
+			res.Items = append(res.Items, result.Items...)
+		}
+		batch = make([]*doc.Document, 0, bulkBatchSize)
+		conds = make([]*index.Condition, 0, bulkBatchSize)
+	}
+	for {
+		request, err := stream.Recv()
+		if err == io.EOF {
+			flush()
+			return stream.SendAndClose(res)
+		}
+		if err != nil {
+			return err
+		}
+		if request.Index != name || len(batch) >= bulkBatchSize {
+			flush()
+			name = request.Index
+		}
+		if request.Doc == nil {
+			request.Doc = &doc.Document{}
+		}
+		batch = append(batch, request.Doc)
+		conds = append(conds, request.Condition)
+	}
+}
+
+// 部分更新文档
+func (service *IndexServiceWorker) UpdateDoc(ctx context.Context, request *index.UpdateDocRequest) (*index.AffectedCount, error) {
+	indexer, err := service.Indexes.Get(request.Index)
+	if err != nil {
+		return &index.AffectedCount{}, err
+	}
+	updated, err := indexer.updateDoc(request)
+	if updated == nil {
+		return affectedCount(0, 0, err)
+	}
+	return affectedCount(1, updated.Version, err)
 }
 
 // 检索，返回文档列表
 func (service *IndexServiceWorker) Search(ctx context.Context, request *index.SearchRequest) (*index.SearchResult, error) {
-	result := service.Indexer.Search(request.Query, request.OnFlag, request.OffFlag, request.OrFlags)
-	return &index.SearchResult{Results: result}, nil
+	indexer, err := service.Indexes.Get(request.Index)
+	if err != nil {
+		return &index.SearchResult{}, err
+	}
+	return indexer.Search(request)
+}
+
+// 流式检索，每从正排索引读出一批文档就发送给客户端
+func (service *IndexServiceWorker) SearchStream(request *index.SearchRequest, stream index.IndexService_SearchStreamServer) error {
+	indexer, err := service.Indexes.Get(request.Index)
+	if err != nil {
+		return err
+	}
+	return indexer.SearchStream(request, stream.Send)
 }
 
 // 索引里有几个文档
 func (service *IndexServiceWorker) Count(ctx context.Context, request *index.CountRequest) (*index.AffectedCount, error) {
-	return &index.AffectedCount{Count: int32(service.Indexer.Count())}, nil
+	indexer, err := service.Indexes.Get(request.Index)
+	if err != nil {
+		return &index.AffectedCount{}, err
+	}
+	return &index.AffectedCount{Count: int32(indexer.Count())}, nil
+}
+
+// 根据业务id读取文档
+func (service *IndexServiceWorker) GetDoc(ctx context.Context, docId *index.DocId) (*index.GetDocResult, error) {
+	indexer, err := service.Indexes.Get(docId.Index)
+	if err != nil {
+		return &index.GetDocResult{}, err
+	}
+	return &index.GetDocResult{Doc: indexer.GetDoc(docId.DocId)}, nil
+}
+
+// 批量读取文档
+func (service *IndexServiceWorker) MultiGetDoc(ctx context.Context, request *index.MultiGetRequest) (*index.MultiGetResult, error) {
+	indexer, err := service.Indexes.Get(request.Index)
+	if err != nil {
+		return &index.MultiGetResult{}, err
+	}
+	return &index.MultiGetResult{Docs: indexer.MultiGetDoc(request.DocIds)}, nil
+}
+
+// 输入提示
+func (service *IndexServiceWorker) Suggest(ctx context.Context, request *index.SuggestRequest) (*index.SuggestResult, error) {
+	indexer, err := service.Indexes.Get(request.Index)
+	if err != nil {
+		return &index.SuggestResult{}, err
+	}
+	return indexer.Suggest(request), nil
+}
+
+// 创建命名索引
+func (service *IndexServiceWorker) CreateIndex(ctx context.Context, request *index.CreateIndexRequest) (*index.AffectedCount, error) {
+	err := service.Indexes.Create(&IndexSpec{
+		Name:           request.Name,
+		IndexType:      int(request.IndexType),
+		DocNumEstimate: int(request.DocNumEstimate),
+		Schema:         request.Schema,
+	})
+	if err != nil {
+		return &index.AffectedCount{}, err
+	}
+	return &index.AffectedCount{Count: 1}, nil
+}
+
+// 删除命名索引及其全部数据
+func (service *IndexServiceWorker) DeleteIndex(ctx context.Context, request *index.IndexName) (*index.AffectedCount, error) {
+	if err := service.Indexes.Delete(request.Name); err != nil {
+		return &index.AffectedCount{}, err
+	}
+	return &index.AffectedCount{Count: 1}, nil
+}
+
+// 列出节点上的所有索引及文档数量
+func (service *IndexServiceWorker) ListIndex(ctx context.Context, request *index.ListIndexRequest) (*index.IndexList, error) {
+	res := &index.IndexList{}
+	service.Indexes.Range(func(name string, indexer *Indexer) {
+		res.Indexes = append(res.Indexes, &index.IndexInfo{Name: name, Count: int32(indexer.Count())})
+	})
+	return res, nil
 }
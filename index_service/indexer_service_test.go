@@ -0,0 +1,119 @@
+package index_service
+
+import (
+	"Research/etc"
+	"Research/types/doc"
+	"Research/types/index"
+	"google.golang.org/grpc"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWorkerAddDocKeepsDocumentRequest(t *testing.T) {
+	c := &etc.Config{}
+	c.ForwardIndex.Dbtype = "memory"
+	c.Expire.SweepInterval = -1
+	worker, err := NewIndexServiceWorker(c, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer worker.Close()
+	if err = worker.Indexes.Create(&IndexSpec{Name: "books"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 原有的AddDoc直接接收文档，写入默认索引
+	if res, err := worker.AddDoc(nil, &doc.Document{Id: "a"}); err != nil || res.Count != 1 {
+		t.Fatalf("add doc: got %v %v", res.GetCount(), err)
+	}
+	if res, err := worker.AddDocTo(nil, &index.AddDocRequest{Index: "books", Doc: &doc.Document{Id: "b"}}); err != nil || res.Count != 1 {
+		t.Fatalf("add doc to index: got %v %v", res.GetCount(), err)
+	}
+	res, _ := worker.AddDocTo(nil, &index.AddDocRequest{Index: "books", Doc: &doc.Document{Id: "b"}, Condition: &index.Condition{IfAbsent: true}})
+	if !res.Conflict {
+		t.Errorf("add existing doc with IfAbsent: want conflict")
+	}
+
+	books, _ := worker.Indexes.Get("books")
+	if worker.Indexes.Default.GetDoc("a") == nil || books.GetDoc("a") != nil {
+		t.Errorf("doc a should only be in the default index")
+	}
+	if books.GetDoc("b") == nil || worker.Indexes.Default.GetDoc("b") != nil {
+		t.Errorf("doc b should only be in index books")
+	}
+}
+
+// 模拟客户端流，依次返回请求，记录最后的结果
+type bulkAddStream struct {
+	grpc.ServerStream
+	requests []*index.AddDocRequest
+	res      *index.BulkAddResult
+}
+
+func (s *bulkAddStream) Recv() (*index.AddDocRequest, error) {
+	if len(s.requests) == 0 {
+		return nil, io.EOF
+	}
+	request := s.requests[0]
+	s.requests = s.requests[1:]
+	return request, nil
+}
+
+func (s *bulkAddStream) SendAndClose(res *index.BulkAddResult) error {
+	s.res = res
+	return nil
+}
+
+// 流中的文档可以写入不同的索引，结果按发送顺序返回
+func TestWorkerBulkAdd(t *testing.T) {
+	c := &etc.Config{}
+	c.ForwardIndex.Dbtype = "memory"
+	c.Expire.SweepInterval = -1
+	worker, err := NewIndexServiceWorker(c, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer worker.Close()
+	if err = worker.Indexes.Create(&IndexSpec{Name: "books"}); err != nil {
+		t.Fatal(err)
+	}
+	worker.Indexes.Default.AddDoc(&doc.Document{Id: "a"})
+
+	stream := &bulkAddStream{requests: []*index.AddDocRequest{
+		{Doc: &doc.Document{Id: "a"}, Condition: &index.Condition{IfAbsent: true}},
+		{Doc: &doc.Document{Id: "b"}},
+		{Index: "books", Doc: &doc.Document{Id: "a"}},
+		{Index: "none", Doc: &doc.Document{Id: "c"}},
+		{Index: "books"},
+		{Doc: &doc.Document{Id: "d"}},
+	}}
+	if err = worker.BulkAdd(stream); err != nil {
+		t.Fatal(err)
+	}
+	res := stream.res
+	if res.Count != 3 || len(res.Items) != 6 {
+		t.Fatalf("count %d items %d, want 3 and 6", res.Count, len(res.Items))
+	}
+	oks := make([]bool, 0, len(res.Items))
+	for _, item := range res.Items {
+		oks = append(oks, item.Ok)
+	}
+	if !slices.Equal(oks, []bool{false, true, true, false, false, true}) {
+		t.Errorf("ok: got %v", oks)
+	}
+	if !res.Items[0].Conflict || res.Items[0].Version != 1 {
+		t.Errorf("item 0: conflict %v version %d", res.Items[0].Conflict, res.Items[0].Version)
+	}
+	if !strings.Contains(res.Items[3].Error, "none") || res.Items[3].Id != "c" {
+		t.Errorf("item 3: id %q error %q", res.Items[3].Id, res.Items[3].Error)
+	}
+	if res.Items[4].Error != errEmptyDocId.Error() {
+		t.Errorf("item 4: error %q", res.Items[4].Error)
+	}
+	books, _ := worker.Indexes.Get("books")
+	if books.GetDoc("a") == nil || books.GetDoc("b") != nil || worker.Indexes.Default.GetDoc("d") == nil {
+		t.Errorf("docs are added to the wrong index")
+	}
+}
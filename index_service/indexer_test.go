@@ -0,0 +1,170 @@
+package index_service
+
+import (
+	"Research/internal/kvdb"
+	"Research/types/doc"
+	"Research/types/index"
+	"errors"
+	"slices"
+	"testing"
+)
+
+var errInjected = errors.New("injected failure")
+
+// 写入正排索引时返回错误的数据库
+type failingDB struct {
+	kvdb.IKeyValueDB
+	fail bool
+}
+
+func (db *failingDB) Set(k, v []byte) error {
+	if db.fail {
+		return errInjected
+	}
+	return db.IKeyValueDB.Set(k, v)
+}
+
+func (db *failingDB) BatchSet(keys, values [][]byte) error {
+	if db.fail {
+		return errInjected
+	}
+	return db.IKeyValueDB.BatchSet(keys, values)
+}
+
+func searchIds(t *testing.T, indexer *Indexer, field, word string) []string {
+	t.Helper()
+	res, err := indexer.Search(&index.SearchRequest{Query: keywordQuery(field, word)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := resultIds(res.Results)
+	slices.Sort(ids)
+	return ids
+}
+
+func TestAddDocKeepsOldDocWhenWriteFails(t *testing.T) {
+	eachIndexType(t, func(t *testing.T, indexer *Indexer) {
+		if _, err := indexer.AddDoc(&doc.Document{Id: "a", Keywords: []*doc.KeyWord{{Field: "f", Word: "old"}}}); err != nil {
+			t.Fatal(err)
+		}
+		db := &failingDB{IKeyValueDB: indexer.forwardIndex, fail: true}
+		indexer.forwardIndex = db
+
+		if _, err := indexer.AddDoc(&doc.Document{Id: "a", Keywords: []*doc.KeyWord{{Field: "f", Word: "new"}}}); !errors.Is(err, errInjected) {
+			t.Fatalf("got %v, want %v", err, errInjected)
+		}
+		res := indexer.AddDocs([]*doc.Document{{Id: "a", Keywords: []*doc.KeyWord{{Field: "f", Word: "new"}}}})
+		if res.Count != 0 || res.Items[0].Ok {
+			t.Fatalf("bulk add should fail, got count %d", res.Count)
+		}
+
+		db.fail = false
+		if d := indexer.GetDoc("a"); d == nil || d.Version != 1 {
+			t.Fatalf("old doc is lost: %v", d)
+		}
+		if ids := searchIds(t, indexer, "f", "old"); !slices.Equal(ids, []string{"a"}) {
+			t.Errorf("old keyword: got %v", ids)
+		}
+		if ids := searchIds(t, indexer, "f", "new"); len(ids) != 0 {
+			t.Errorf("new keyword: got %v", ids)
+		}
+	})
+}
+
+func TestAddDocReplacesOldDoc(t *testing.T) {
+	eachIndexType(t, func(t *testing.T, indexer *Indexer) {
+		indexer.AddDoc(&doc.Document{Id: "a", Keywords: []*doc.KeyWord{{Field: "f", Word: "old"}}})
+		oldIntId := indexer.GetDoc("a").IntId
+		indexer.AddDocs([]*doc.Document{{Id: "a", Keywords: []*doc.KeyWord{{Field: "f", Word: "new"}}}})
+
+		d := indexer.GetDoc("a")
+		if d == nil || d.Version != 2 || d.IntId == oldIntId {
+			t.Fatalf("got %v", d)
+		}
+		if _, exist := indexer.GetDocId(oldIntId); exist {
+			t.Errorf("IntId %d of the replaced doc is still mapped", oldIntId)
+		}
+		if ids := searchIds(t, indexer, "f", "old"); len(ids) != 0 {
+			t.Errorf("old keyword: got %v", ids)
+		}
+		if ids := searchIds(t, indexer, "f", "new"); !slices.Equal(ids, []string{"a"}) {
+			t.Errorf("new keyword: got %v", ids)
+		}
+		if n := indexer.Count(); n != 1 {
+			t.Errorf("count: got %d, want 1", n)
+		}
+	})
+}
+
+func TestUpdateDocKeepsIntId(t *testing.T) {
+	eachIndexType(t, func(t *testing.T, indexer *Indexer) {
+		indexer.AddDoc(&doc.Document{Id: "a", Keywords: []*doc.KeyWord{{Field: "f", Word: "old"}, {Field: "g", Word: "x"}}})
+		oldIntId := indexer.GetDoc("a").IntId
+		n, err := indexer.UpdateDoc(&index.UpdateDocRequest{DocId: "a", Keywords: []*doc.KeyWord{{Field: "f", Word: "new"}}})
+		if n != 1 || err != nil {
+			t.Fatalf("got %d %v", n, err)
+		}
+
+		d := indexer.GetDoc("a")
+		if d == nil || d.Version != 2 || d.IntId != oldIntId {
+			t.Fatalf("IntId changed from %d", oldIntId)
+		}
+		if docId, exist := indexer.GetDocId(oldIntId); !exist || docId != "a" {
+			t.Errorf("IntId %d maps to %q", oldIntId, docId)
+		}
+		if ids := searchIds(t, indexer, "f", "old"); len(ids) != 0 {
+			t.Errorf("old keyword: got %v", ids)
+		}
+		if ids := searchIds(t, indexer, "f", "new"); !slices.Equal(ids, []string{"a"}) {
+			t.Errorf("new keyword: got %v", ids)
+		}
+		if ids := searchIds(t, indexer, "g", "x"); !slices.Equal(ids, []string{"a"}) {
+			t.Errorf("untouched field: got %v", ids)
+		}
+		// 文档不存在时不创建
+		if n, _ = indexer.UpdateDoc(&index.UpdateDocRequest{DocId: "b", Keywords: []*doc.KeyWord{{Field: "f", Word: "new"}}}); n != 0 || indexer.GetDoc("b") != nil {
+			t.Errorf("update created missing doc")
+		}
+	})
+}
+
+// 批量添加的结果与文档一一对应，失败的文档不影响同一批中的其它文档
+func TestAddDocsItems(t *testing.T) {
+	eachIndexType(t, func(t *testing.T, indexer *Indexer) {
+		if _, err := indexer.AddDoc(&doc.Document{Id: "c", Keywords: []*doc.KeyWord{{Field: "f", Word: "old"}}}); err != nil {
+			t.Fatal(err)
+		}
+		res := indexer.AddDocs([]*doc.Document{
+			{Id: "a", Keywords: []*doc.KeyWord{{Field: "f", Word: "go"}}},
+			{Id: "  "},
+			{Id: "b", Keywords: []*doc.KeyWord{{Field: "f", Word: "old"}}},
+			{Id: metaPrefix + "x"},
+			{Id: " b ", Keywords: []*doc.KeyWord{{Field: "f", Word: "go"}}},
+			{Id: "c", Keywords: []*doc.KeyWord{{Field: "f", Word: "go"}}},
+		})
+		if res.Count != 3 || len(res.Items) != 6 {
+			t.Fatalf("count %d items %d, want 3 and 6", res.Count, len(res.Items))
+		}
+		wants := []struct {
+			ok      bool
+			version uint64
+			err     error
+		}{{true, 1, nil}, {false, 0, errEmptyDocId}, {false, 0, errDuplicateDocId}, {false, 0, errMetaDocId}, {true, 1, nil}, {true, 2, nil}}
+		for i, want := range wants {
+			item := res.Items[i]
+			if item.Ok != want.ok || item.Version != want.version || item.Conflict {
+				t.Errorf("item %d: ok %v version %d conflict %v", i, item.Ok, item.Version, item.Conflict)
+			}
+			if want.err != nil && item.Error != want.err.Error() || want.err == nil && len(item.Error) > 0 {
+				t.Errorf("item %d: error %q, want %v", i, item.Error, want.err)
+			}
+		}
+		if ids := searchIds(t, indexer, "f", "go"); !slices.Equal(ids, []string{"a", "b", "c"}) {
+			t.Errorf("go: got %v", ids)
+		}
+		// 被覆盖的c和重复的b都不在倒排索引中
+		if ids := searchIds(t, indexer, "f", "old"); len(ids) != 0 {
+			t.Errorf("old: got %v", ids)
+		}
+	})
+}
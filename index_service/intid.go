@@ -0,0 +1,104 @@
+package index_service
+
+import (
+	"Research/util"
+	"encoding/binary"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// 正排索引中除了文档，还保存IntId分配器的元数据，元数据的key以metaPrefix开头，业务id不能使用该前缀
+const (
+	metaPrefix  = "\x00research\x00"       // etc.RedisIndexPrefix也以此开头
+	maxIntIdKey = metaPrefix + "maxIntId"  // 已预留的最大IntId
+	intIdPrefix = metaPrefix + "intId\x00" // IntId到业务id的映射
+	intIdStep   = 1000                     // 每次预留的IntId数量，减少写正排索引的次数
+)
+
+var (
+	errMetaDocId      = errors.New("doc id can not start with reserved prefix")
+	errEmptyDocId     = errors.New("doc id is empty")
+	errDuplicateDocId = errors.New("doc id is duplicated in batch, only the last one is added")
+)
+
+// 判断是否是元数据的key
+func isMetaKey(k []byte) bool {
+	return strings.HasPrefix(string(k), metaPrefix)
+}
+
+// 业务id去掉首尾空白，按id读写、删除文档和选择节点前都先规范化，保证同一个id落到同一个文档上
+func normalizeDocId(docId string) string {
+	return strings.TrimSpace(docId)
+}
+
+// IntId映射的key，大端序保证按IntId有序
+func intIdKey(intId uint64) []byte {
+	key := make([]byte, len(intIdPrefix)+8)
+	copy(key, intIdPrefix)
+	binary.BigEndian.PutUint64(key[len(intIdPrefix):], intId)
+	return key
+}
+
+// 从正排索引加载已预留的最大IntId，之后分配的IntId都比它大
+func (indexer *Indexer) loadMaxIntId() error {
+	value, err := indexer.forwardIndex.Get([]byte(maxIntIdKey))
+	if err != nil {
+		// 新建的索引没有元数据
+		return nil
+	}
+	maxIntId, err := strconv.ParseUint(string(value), 10, 64)
+	if err != nil {
+		return err
+	}
+	indexer.maxIntId = maxIntId
+	indexer.reservedIntId = maxIntId
+	return nil
+}
+
+// 分配一个新的IntId，超过已预留的范围时先持久化新的预留值
+func (indexer *Indexer) allocIntId() (uint64, error) {
+	indexer.intIdLock.Lock()
+	defer indexer.intIdLock.Unlock()
+	if indexer.maxIntId >= indexer.reservedIntId {
+		if err := indexer.reserveIntId(indexer.maxIntId + intIdStep); err != nil {
+			return 0, err
+		}
+	}
+	indexer.maxIntId++
+	return indexer.maxIntId, nil
+}
+
+// 确保之后分配的IntId都比intId大，用于回放日志和加载文档时校验已有的IntId
+func (indexer *Indexer) ensureIntId(intId uint64) error {
+	indexer.intIdLock.Lock()
+	defer indexer.intIdLock.Unlock()
+	if intId <= indexer.maxIntId {
+		return nil
+	}
+	indexer.maxIntId = intId
+	if intId > indexer.reservedIntId {
+		// 分配前会先持久化预留值，正常情况下不会超过，说明元数据丢失，调大分配器避免冲突
+		util.Log.Printf("IntId %d exceeds reserved max IntId %d", intId, indexer.reservedIntId)
+		return indexer.reserveIntId(intId)
+	}
+	return nil
+}
+
+// 持久化预留的最大IntId，调用方需持有intIdLock
+func (indexer *Indexer) reserveIntId(reserved uint64) error {
+	if err := indexer.forwardIndex.Set([]byte(maxIntIdKey), []byte(strconv.FormatUint(reserved, 10))); err != nil {
+		return err
+	}
+	indexer.reservedIntId = reserved
+	return nil
+}
+
+// 根据IntId查询业务id
+func (indexer *Indexer) GetDocId(intId uint64) (string, bool) {
+	value, err := indexer.forwardIndex.Get(intIdKey(intId))
+	if err != nil {
+		return "", false
+	}
+	return string(value), true
+}
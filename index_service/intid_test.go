@@ -0,0 +1,97 @@
+package index_service
+
+import (
+	"Research/etc"
+	"Research/types/doc"
+	"errors"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestAllocIntIdReservesInSteps(t *testing.T) {
+	indexer := newTestIndexer(t, 0)
+	first, err := indexer.allocIntId()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != 1 || indexer.reservedIntId != intIdStep {
+		t.Fatalf("first IntId %d reserved %d", first, indexer.reservedIntId)
+	}
+	for i := 1; i < intIdStep; i++ {
+		_, _ = indexer.allocIntId()
+	}
+	// 用完预留的范围后再预留一段
+	next, _ := indexer.allocIntId()
+	if next != intIdStep+1 || indexer.reservedIntId != 2*intIdStep {
+		t.Errorf("IntId %d reserved %d", next, indexer.reservedIntId)
+	}
+	value, err := indexer.forwardIndex.Get([]byte(maxIntIdKey))
+	if err != nil || string(value) != strconv.Itoa(2*intIdStep) {
+		t.Errorf("persisted max IntId: got %s %v", value, err)
+	}
+}
+
+func TestIntIdSurvivesRestart(t *testing.T) {
+	c := &etc.Config{}
+	c.ForwardIndex.Dbtype = "bolt"
+	c.ForwardIndex.Addr = filepath.Join(t.TempDir(), "data", "db")
+	c.Expire.SweepInterval = -1
+	indexer := new(Indexer)
+	if err := indexer.Init(c); err != nil {
+		t.Fatal(err)
+	}
+	indexer.AddDoc(&doc.Document{Id: "a"})
+	indexer.AddDoc(&doc.Document{Id: "b"})
+	// 删除IntId最大的文档，重启后也不能复用它的IntId
+	maxIntId := indexer.GetDoc("b").IntId
+	indexer.DeleteDoc("b")
+	_ = indexer.Close()
+
+	indexer = new(Indexer)
+	if err := indexer.Init(c); err != nil {
+		t.Fatal(err)
+	}
+	defer indexer.Close()
+	indexer.LoadFromIndexFile(1)
+	indexer.AddDoc(&doc.Document{Id: "c"})
+	if intId := indexer.GetDoc("c").IntId; intId <= maxIntId {
+		t.Errorf("IntId %d is reused after restart, max was %d", intId, maxIntId)
+	}
+	if docId, exist := indexer.GetDocId(indexer.GetDoc("a").IntId); !exist || docId != "a" {
+		t.Errorf("IntId mapping of a: got %s %v", docId, exist)
+	}
+}
+
+func TestEnsureIntId(t *testing.T) {
+	indexer := newTestIndexer(t, 0)
+	if err := indexer.ensureIntId(5000); err != nil {
+		t.Fatal(err)
+	}
+	if indexer.reservedIntId != 5000 {
+		t.Errorf("reserved %d, want 5000", indexer.reservedIntId)
+	}
+	// 比已分配的小时不变
+	_ = indexer.ensureIntId(10)
+	if next, _ := indexer.allocIntId(); next != 5001 {
+		t.Errorf("next IntId %d, want 5001", next)
+	}
+}
+
+func TestMetaKeys(t *testing.T) {
+	indexer := newTestIndexer(t, 0)
+	if _, err := indexer.AddDoc(&doc.Document{Id: metaPrefix + "x"}); !errors.Is(err, errMetaDocId) {
+		t.Errorf("got %v, want %v", err, errMetaDocId)
+	}
+	indexer.AddDoc(&doc.Document{Id: "a"})
+	// 元数据不算作文档，也不能按业务id读取
+	if n := indexer.Count(); n != 1 {
+		t.Errorf("count: got %d, want 1", n)
+	}
+	if indexer.GetDoc(maxIntIdKey) != nil {
+		t.Error("meta key is readable as a doc")
+	}
+	if n, _ := indexer.DeleteDocIf(maxIntIdKey, nil); n != 0 || !indexer.forwardIndex.Has([]byte(maxIntIdKey)) {
+		t.Error("meta key is deleted as a doc")
+	}
+}
@@ -0,0 +1,95 @@
+package index_service
+
+import (
+	"Research/types/doc"
+	"Research/types/index"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// 检索结果按得分降序、得分相同按业务id升序排列，分页游标记录上一页最后一个文档在这个顺序中的位置
+
+var (
+	errCursor = errors.New("invalid search cursor")
+	errPage   = errors.New("offset and limit must not be negative")
+)
+
+type searchCursor struct {
+	Score float64
+	Id    string
+}
+
+// 游标对调用方是不透明的字符串：8字节得分 + 业务id
+func encodeCursor(score float64, id string) string {
+	buf := make([]byte, 8+len(id))
+	binary.BigEndian.PutUint64(buf, math.Float64bits(score))
+	copy(buf[8:], id)
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// 解析游标，cursor为空时返回nil
+func decodeCursor(cursor string) (*searchCursor, error) {
+	if len(cursor) == 0 {
+		return nil, nil
+	}
+	buf, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(buf) < 8 {
+		return nil, errCursor
+	}
+	return &searchCursor{
+		Score: math.Float64frombits(binary.BigEndian.Uint64(buf)),
+		Id:    string(buf[8:]),
+	}, nil
+}
+
+// 判断文档是否排在游标之后
+func (c *searchCursor) after(score float64, id string) bool {
+	if c == nil {
+		return true
+	}
+	if score != c.Score {
+		return score < c.Score
+	}
+	return id > c.Id
+}
+
+// 文档a是否排在b之前
+func docBefore(a, b *doc.Document) bool {
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	return a.Id < b.Id
+}
+
+// 校验分页参数，Offset和Limit不能是负数
+func checkPage(request *index.SearchRequest) error {
+	if request.Offset < 0 || request.Limit < 0 {
+		return errPage
+	}
+	return nil
+}
+
+// 计算一页在结果中的范围，n是游标之后的结果数量，more表示n之后还有结果
+// Offset和Limit已经由checkPage校验过，Limit为0时不分页
+func pageRange(n int, request *index.SearchRequest, more bool) (int, int, bool) {
+	start := max(int(request.Offset), 0)
+	if start > n {
+		start = n
+	}
+	end := n
+	if request.Limit > 0 && start+int(request.Limit) < n {
+		end = start + int(request.Limit)
+		more = true
+	}
+	return start, end, more
+}
+
+// 一页需要的结果数量，不分页时返回0
+func pageWindow(request *index.SearchRequest) int {
+	if request.Limit <= 0 {
+		return 0
+	}
+	return max(int(request.Offset), 0) + int(request.Limit)
+}
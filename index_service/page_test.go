@@ -0,0 +1,180 @@
+package index_service
+
+import (
+	"Research/etc"
+	reverseindex "Research/internal/reverse_index"
+	"Research/types/doc"
+	"Research/types/index"
+	"Research/types/term_query"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+// 创建使用内存正排索引的测试索引
+func newTestIndexer(t *testing.T, indexType int) *Indexer {
+	t.Helper()
+	c := &etc.Config{}
+	c.ForwardIndex.Dbtype = "memory"
+	c.ReverseIndex.IndexType = indexType
+	c.ReverseIndex.DocNumEstimate = 100
+	c.Expire.SweepInterval = -1
+	indexer := new(Indexer)
+	if err := indexer.Init(c); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = indexer.Close() })
+	return indexer
+}
+
+// 两种倒排索引各跑一遍
+func eachIndexType(t *testing.T, fn func(t *testing.T, indexer *Indexer)) {
+	for name, indexType := range map[string]int{"skiplist": reverseindex.SKIPLIST, "roaring": reverseindex.ROARING} {
+		t.Run(name, func(t *testing.T) {
+			fn(t, newTestIndexer(t, indexType))
+		})
+	}
+}
+
+func keywordQuery(field, word string) *term_query.TermQuery {
+	return &term_query.TermQuery{Keyword: &doc.KeyWord{Field: field, Word: word}}
+}
+
+func resultIds(docs []*doc.Document) []string {
+	ids := make([]string, 0, len(docs))
+	for _, d := range docs {
+		ids = append(ids, d.Id)
+	}
+	return ids
+}
+
+func TestCheckPage(t *testing.T) {
+	cases := []struct {
+		offset, limit int32
+		err           error
+	}{
+		{0, 0, nil},
+		{3, 10, nil},
+		{-1, 0, errPage},
+		{0, -1, errPage},
+		{-5, -5, errPage},
+	}
+	for _, c := range cases {
+		err := checkPage(&index.SearchRequest{Offset: c.offset, Limit: c.limit})
+		if !errors.Is(err, c.err) {
+			t.Errorf("offset %d limit %d: got %v, want %v", c.offset, c.limit, err, c.err)
+		}
+	}
+}
+
+func TestPageRange(t *testing.T) {
+	cases := []struct {
+		n             int
+		offset, limit int32
+		start, end    int
+		more          bool
+	}{
+		{10, 0, 0, 0, 10, false},
+		{10, 2, 3, 2, 5, true},
+		{10, 8, 5, 8, 10, false},
+		{10, 20, 5, 10, 10, false},
+		{10, -1, 0, 0, 10, false},
+		{10, -3, 4, 0, 4, true},
+		{10, 2, -1, 2, 10, false},
+		{0, -1, -1, 0, 0, false},
+	}
+	for _, c := range cases {
+		start, end, more := pageRange(c.n, &index.SearchRequest{Offset: c.offset, Limit: c.limit}, false)
+		if start != c.start || end != c.end || more != c.more {
+			t.Errorf("n %d offset %d limit %d: got [%d,%d) %v, want [%d,%d) %v",
+				c.n, c.offset, c.limit, start, end, more, c.start, c.end, c.more)
+		}
+	}
+	if window := pageWindow(&index.SearchRequest{Offset: -3, Limit: 4}); window != 4 {
+		t.Errorf("page window with negative offset: got %d, want 4", window)
+	}
+}
+
+func TestSearchNegativePage(t *testing.T) {
+	eachIndexType(t, func(t *testing.T, indexer *Indexer) {
+		for _, id := range []string{"a", "b", "c"} {
+			if _, err := indexer.AddDoc(&doc.Document{Id: id, Keywords: []*doc.KeyWord{{Field: "f", Word: "w"}}}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		q := keywordQuery("f", "w")
+		for _, request := range []*index.SearchRequest{
+			{Query: q, Offset: -1},
+			{Query: q, Limit: -1},
+			{Query: q, Offset: -1, Limit: 2},
+		} {
+			if res, err := indexer.Search(request); len(res.Results) != 0 || !errors.Is(err, errPage) {
+				t.Errorf("offset %d limit %d: got %v %v, want %v", request.Offset, request.Limit, resultIds(res.Results), err, errPage)
+			}
+			err := indexer.SearchStream(request, func(*index.SearchBatch) error { return nil })
+			if !errors.Is(err, errPage) {
+				t.Errorf("stream offset %d limit %d: got %v, want %v", request.Offset, request.Limit, err, errPage)
+			}
+		}
+		// 无法解析的游标返回错误，而不是空结果
+		if _, err := indexer.Search(&index.SearchRequest{Query: q, Cursor: "bad"}); !errors.Is(err, errCursor) {
+			t.Errorf("bad cursor: got %v, want %v", err, errCursor)
+		}
+		res, err := indexer.Search(&index.SearchRequest{Query: q, Offset: 1, Limit: 1})
+		if err != nil || len(res.Results) != 1 || len(res.NextCursor) == 0 {
+			t.Errorf("second page: got %v cursor %q", resultIds(res.Results), res.NextCursor)
+		}
+	})
+}
+
+func TestWorkerSearchRejectsNegativePage(t *testing.T) {
+	c := &etc.Config{}
+	c.ForwardIndex.Dbtype = "memory"
+	c.Expire.SweepInterval = -1
+	worker, err := NewIndexServiceWorker(c, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer worker.Close()
+	if _, err = worker.Search(nil, &index.SearchRequest{Query: keywordQuery("f", "w"), Offset: -1}); !errors.Is(err, errPage) {
+		t.Errorf("got %v, want %v", err, errPage)
+	}
+}
+
+func TestSearchStreamCapsHits(t *testing.T) {
+	if testing.Short() {
+		t.Skip("adds more than maxStreamHits docs")
+	}
+	indexer := newTestIndexer(t, reverseindex.ROARING)
+	docs := make([]*doc.Document, 0, maxStreamHits+10)
+	for i := 0; i < maxStreamHits+10; i++ {
+		docs = append(docs, &doc.Document{Id: strconv.Itoa(i), Keywords: []*doc.KeyWord{{Field: "f", Word: "w"}}})
+	}
+	if res := indexer.AddDocs(docs); int(res.Count) != len(docs) {
+		t.Fatalf("added %d docs", res.Count)
+	}
+	// 返回文档数和最后一批的游标
+	count := func(request *index.SearchRequest) (int, string) {
+		n, cursor := 0, ""
+		if err := indexer.SearchStream(request, func(batch *index.SearchBatch) error {
+			n += len(batch.Docs)
+			cursor = batch.NextCursor
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		return n, cursor
+	}
+	q := keywordQuery("f", "w")
+	n, cursor := count(&index.SearchRequest{Query: q, BatchSize: 1000})
+	if n != maxStreamHits || len(cursor) == 0 {
+		t.Fatalf("without TopK: got %d cursor %q, want %d with cursor", n, cursor, maxStreamHits)
+	}
+	// 用游标取到剩下的文档，结果完整时没有游标
+	if n, cursor = count(&index.SearchRequest{Query: q, Cursor: cursor}); n != 10 || len(cursor) > 0 {
+		t.Errorf("continue with cursor: got %d cursor %q, want 10", n, cursor)
+	}
+	if n, cursor = count(&index.SearchRequest{Query: q, TopK: 5}); n != 5 || len(cursor) > 0 {
+		t.Errorf("TopK 5: got %d cursor %q", n, cursor)
+	}
+}
@@ -0,0 +1,265 @@
+package index_service
+
+import (
+	"Research/etc"
+	"Research/schema"
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// 默认索引，请求中的索引名称为空时使用，打开节点配置中的正排索引，不能删除
+const DEFAULT_INDEX = "default"
+
+// 命名索引的定义保存在默认索引的正排索引中，节点重启后重新打开
+const indexSpecsKey = metaPrefix + "indexes"
+
+var (
+	errNoIndex        = errors.New("index not exist")
+	errIndexExist     = errors.New("index already exist")
+	errIndexName      = errors.New("index name can only contain letters, digits, '_' and '-'")
+	errDeleteDefault  = errors.New("default index can not be deleted")
+	errIndexDir       = errors.New("data directory of index already exists")
+	indexNamePattern  = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+	defaultIndexNames = map[string]struct{}{"": {}, DEFAULT_INDEX: {}}
+)
+
+// 命名索引的定义
+type IndexSpec struct {
+	Name           string
+	IndexType      int      // 倒排索引类型，0使用节点的配置
+	DocNumEstimate int      // 文档数量预估值，0使用节点的配置
+	Schema         string   // YAML或JSON格式的schema，为空时不校验文档
+	Dirs           []string // 创建索引时新建的本地数据目录，删除索引时一并删除
+}
+
+// 节点上的索引注册表，每个索引有独立的正排索引命名空间和倒排索引
+type IndexRegistry struct {
+	conf    *etc.Config
+	indexes map[string]*Indexer // 命名索引，不包括默认索引
+	specs   map[string]*IndexSpec
+	lock    sync.RWMutex
+	Default *Indexer // 默认索引
+}
+
+// 打开默认索引，再打开已创建的命名索引
+func NewIndexRegistry(c *etc.Config) (*IndexRegistry, error) {
+	r := &IndexRegistry{
+		conf:    c,
+		indexes: make(map[string]*Indexer),
+		specs:   make(map[string]*IndexSpec),
+		Default: new(Indexer),
+	}
+	// 默认索引使用节点配置的key前缀，遍历时跳过命名索引的数据
+	conf := *c
+	conf.ForwardIndex = c.ForwardIndex.DefaultNamespace()
+	if err := r.Default.Init(&conf); err != nil {
+		return nil, err
+	}
+	specs, err := r.loadSpecs()
+	if err != nil {
+		return nil, err
+	}
+	for _, spec := range specs {
+		indexer, err := r.open(spec)
+		if err != nil {
+			return nil, fmt.Errorf("open index %s failed: %w", spec.Name, err)
+		}
+		r.indexes[spec.Name] = indexer
+		r.specs[spec.Name] = spec
+	}
+	return r, nil
+}
+
+// 按名称获取索引，名称为空时返回默认索引
+func (r *IndexRegistry) Get(name string) (*Indexer, error) {
+	if _, exist := defaultIndexNames[name]; exist {
+		return r.Default, nil
+	}
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	indexer, exist := r.indexes[name]
+	if !exist {
+		return nil, fmt.Errorf("%w: %s", errNoIndex, name)
+	}
+	return indexer, nil
+}
+
+// 创建命名索引
+func (r *IndexRegistry) Create(spec *IndexSpec) error {
+	if _, exist := defaultIndexNames[spec.Name]; exist {
+		return errIndexExist
+	}
+	if !indexNamePattern.MatchString(spec.Name) {
+		return errIndexName
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, exist := r.indexes[spec.Name]; exist {
+		return fmt.Errorf("%w: %s", errIndexExist, spec.Name)
+	}
+	dirs, err := r.newDirs(spec.Name)
+	if err != nil {
+		return err
+	}
+	indexer, err := r.open(spec)
+	if err != nil {
+		return err
+	}
+	spec.Dirs = dirs
+	r.specs[spec.Name] = spec
+	if err = r.saveSpecs(); err != nil {
+		delete(r.specs, spec.Name)
+		_ = indexer.Drop()
+		return err
+	}
+	r.indexes[spec.Name] = indexer
+	return nil
+}
+
+// 删除命名索引及其全部数据
+func (r *IndexRegistry) Delete(name string) error {
+	if _, exist := defaultIndexNames[name]; exist {
+		return errDeleteDefault
+	}
+	if !indexNamePattern.MatchString(name) {
+		return errIndexName
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	indexer, exist := r.indexes[name]
+	if !exist {
+		return fmt.Errorf("%w: %s", errNoIndex, name)
+	}
+	spec := r.specs[name]
+	delete(r.specs, name)
+	if err := r.saveSpecs(); err != nil {
+		r.specs[name] = spec
+		return err
+	}
+	delete(r.indexes, name)
+	if err := indexer.Drop(); err != nil {
+		return err
+	}
+	// 只删除创建索引时新建的目录，不会删掉不属于该索引的目录
+	for _, dir := range spec.Dirs {
+		if err := os.RemoveAll(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 命名索引需要新建的本地数据目录，目录已存在时返回错误，避免和已有的目录共用，删除索引时误删
+func (r *IndexRegistry) newDirs(name string) ([]string, error) {
+	dirs := make([]string, 0, 2)
+	if conf := r.conf.ForwardIndex.Namespace(name); conf.Dbtype != "redis" && len(conf.Addr) > 0 {
+		dirs = append(dirs, filepath.Dir(conf.Addr))
+	}
+	if conf := r.conf.Wal.Namespace(name); len(conf.Path) > 0 {
+		if dir := filepath.Dir(conf.Path); len(dirs) == 0 || dirs[0] != dir {
+			dirs = append(dirs, dir)
+		}
+	}
+	for _, dir := range dirs {
+		if _, err := os.Stat(dir); err == nil {
+			return nil, fmt.Errorf("%w: %s", errIndexDir, dir)
+		}
+	}
+	return dirs, nil
+}
+
+// 所有索引的名称，默认索引排在第一个，命名索引按名称排序
+func (r *IndexRegistry) List() []string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	names := make([]string, 0, len(r.indexes))
+	for name := range r.indexes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return append([]string{DEFAULT_INDEX}, names...)
+}
+
+// 按List的顺序遍历所有索引
+func (r *IndexRegistry) Range(fn func(name string, indexer *Indexer)) {
+	for _, name := range r.List() {
+		if indexer, err := r.Get(name); err == nil {
+			fn(name, indexer)
+		}
+	}
+}
+
+// 关闭所有索引
+func (r *IndexRegistry) Close() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	var res error
+	for _, indexer := range r.indexes {
+		if err := indexer.Close(); err != nil {
+			res = err
+		}
+	}
+	if err := r.Default.Close(); err != nil {
+		res = err
+	}
+	return res
+}
+
+// 按索引定义打开命名索引，正排索引和预写日志使用以索引命名的命名空间
+func (r *IndexRegistry) open(spec *IndexSpec) (*Indexer, error) {
+	conf := *r.conf
+	conf.ForwardIndex = r.conf.ForwardIndex.Namespace(spec.Name)
+	conf.Wal = r.conf.Wal.Namespace(spec.Name)
+	if spec.IndexType > 0 {
+		conf.ReverseIndex.IndexType = spec.IndexType
+	}
+	if spec.DocNumEstimate > 0 {
+		conf.ReverseIndex.DocNumEstimate = spec.DocNumEstimate
+	}
+	var s *schema.Schema
+	if len(spec.Schema) > 0 {
+		var err error
+		if s, err = schema.Parse([]byte(spec.Schema)); err != nil {
+			return nil, fmt.Errorf("invalid schema: %w", err)
+		}
+	}
+	indexer := &Indexer{name: spec.Name}
+	if err := indexer.init(&conf, s); err != nil {
+		return nil, err
+	}
+	return indexer, nil
+}
+
+// 从默认索引读取命名索引的定义
+func (r *IndexRegistry) loadSpecs() ([]*IndexSpec, error) {
+	value, err := r.Default.forwardIndex.Get([]byte(indexSpecsKey))
+	if err != nil || len(value) == 0 {
+		// 没有创建过命名索引
+		return nil, nil
+	}
+	var specs []*IndexSpec
+	if err = gob.NewDecoder(bytes.NewReader(value)).Decode(&specs); err != nil {
+		return nil, err
+	}
+	return specs, nil
+}
+
+// 把命名索引的定义写入默认索引，调用方需持有写锁
+func (r *IndexRegistry) saveSpecs() error {
+	specs := make([]*IndexSpec, 0, len(r.specs))
+	for _, spec := range r.specs {
+		specs = append(specs, spec)
+	}
+	var value bytes.Buffer
+	if err := gob.NewEncoder(&value).Encode(specs); err != nil {
+		return err
+	}
+	return r.Default.forwardIndex.Set([]byte(indexSpecsKey), value.Bytes())
+}
@@ -0,0 +1,149 @@
+package index_service
+
+import (
+	"Research/etc"
+	"Research/internal/kvdb/redistest"
+	"Research/types/doc"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// 创建使用bolt正排索引的注册表，数据放在临时目录
+func newTestRegistry(t *testing.T) (*IndexRegistry, string) {
+	t.Helper()
+	root := t.TempDir()
+	c := &etc.Config{}
+	c.ForwardIndex.Dbtype = "bolt"
+	c.ForwardIndex.Addr = filepath.Join(root, "data", "db")
+	c.ForwardIndex.Bucket = "test"
+	c.Expire.SweepInterval = -1
+	r, err := NewIndexRegistry(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = r.Close() })
+	return r, root
+}
+
+func TestRegistryDeleteRemovesCreatedDir(t *testing.T) {
+	r, root := newTestRegistry(t)
+	if err := r.Create(&IndexSpec{Name: "books"}); err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(root, "data", "books")
+	if _, err := os.Stat(dir); err != nil {
+		t.Fatalf("index dir is not created: %s", err)
+	}
+	if err := r.Delete("books"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("index dir is left: %v", err)
+	}
+}
+
+func TestRegistryKeepsExistingDir(t *testing.T) {
+	r, root := newTestRegistry(t)
+	// 索引名称对应的目录已经存在，不属于注册表
+	dir := filepath.Join(root, "data", "products")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "keep")
+	if err := os.WriteFile(file, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Create(&IndexSpec{Name: "products"}); !errors.Is(err, errIndexDir) {
+		t.Fatalf("got %v, want %v", err, errIndexDir)
+	}
+	if err := r.Delete("products"); !errors.Is(err, errNoIndex) {
+		t.Errorf("got %v, want %v", err, errNoIndex)
+	}
+	if _, err := os.Stat(file); err != nil {
+		t.Errorf("existing dir is removed: %s", err)
+	}
+}
+
+func TestRegistryRejectsPathNames(t *testing.T) {
+	r, root := newTestRegistry(t)
+	for _, name := range []string{"..", "../data", "a/b", `a\b`, "a.b", ""} {
+		if err := r.Create(&IndexSpec{Name: name}); err == nil {
+			t.Errorf("create %q: want error", name)
+		}
+	}
+	for _, name := range []string{"..", "../data", "a/b"} {
+		if err := r.Delete(name); !errors.Is(err, errIndexName) {
+			t.Errorf("delete %q: got %v, want %v", name, err, errIndexName)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, "data")); err != nil {
+		t.Errorf("data dir is removed: %s", err)
+	}
+}
+
+// 升级前默认索引直接使用节点配置的redis前缀，注册表打开后仍然能读到，命名索引的数据不会混入默认索引
+func TestRegistryOpensExistingRedisKeyspace(t *testing.T) {
+	server := redistest.NewServer(t)
+	c := &etc.Config{}
+	c.ForwardIndex.Dbtype = "redis"
+	c.ForwardIndex.Addr = server.Addr()
+	c.ForwardIndex.Prefix = "app:"
+	c.Expire.SweepInterval = -1
+
+	// 没有注册表时写入的文档
+	old := new(Indexer)
+	if err := old.Init(c); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"a", "b"} {
+		if _, err := old.AddDoc(&doc.Document{Id: id, Keywords: []*doc.KeyWord{{Field: "f", Word: "w"}}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = old.Close()
+
+	r, err := NewIndexRegistry(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := r.Default.LoadFromIndexFile(1); n != 2 {
+		t.Fatalf("loaded %d docs, want 2", n)
+	}
+	if d := r.Default.GetDoc("a"); d == nil || d.Id != "a" {
+		t.Fatal("existing doc is not found")
+	}
+	if err = r.Create(&IndexSpec{Name: "products"}); err != nil {
+		t.Fatal(err)
+	}
+	products, _ := r.Get("products")
+	if _, err = products.AddDoc(&doc.Document{Id: "c", Keywords: []*doc.KeyWord{{Field: "f", Word: "w"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if n := r.Default.Count(); n != 2 {
+		t.Errorf("default index counts %d docs, want 2", n)
+	}
+	_ = r.Close()
+
+	// 重新打开，两个索引各自加载自己的文档
+	r, err = NewIndexRegistry(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if n := r.Default.LoadFromIndexFile(1); n != 2 {
+		t.Errorf("default index loaded %d docs, want 2", n)
+	}
+	products, err = r.Get("products")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := products.LoadFromIndexFile(1); n != 1 {
+		t.Errorf("named index loaded %d docs, want 1", n)
+	}
+	if !slices.Contains(server.Keys(), "app:"+etc.RedisIndexPrefix+"products:c") {
+		t.Errorf("named index key is not under the reserved prefix: %q", server.Keys())
+	}
+}
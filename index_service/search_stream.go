@@ -0,0 +1,240 @@
+package index_service
+
+import (
+	"Research/ServiceHub"
+	"Research/types/doc"
+	"Research/types/index"
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// 集群上流式检索的迭代器，归并各节点按得分降序的结果流
+// 只有某个节点已读取的文档被消费完时才读它的下一批，节点发送的速度受gRPC流控限制，不会把全部结果读进内存
+//
+// 迭代结束后检查Err，不为nil时有节点失败，结果不完整；NextCursor不为空时结果被截断，用它继续检索
+//
+//	it := sentinel.SearchStream(request)
+//	defer it.Close()
+//	for it.Next() {
+//		doc := it.Doc()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type SearchIterator struct {
+	cancel    context.CancelFunc
+	nodes     *nodeHeap
+	skip      int // 还需要跳过的文档数
+	remain    int // 还可以返回的文档数，负数表示不限
+	doc       *doc.Document
+	highlight *index.DocHighlight
+	bound     *searchCursor // 被截断的节点的最后一个文档，排在它之后的结果可能不完整，不再返回
+	cursor    string        // bound对应的游标
+	exhausted bool          // 所有节点的结果都已返回，或者到达了bound
+	errs      []error       // 失败节点的错误
+}
+
+// 一个节点的结果流
+type nodeStream struct {
+	addr       string
+	stream     index.IndexService_SearchStreamClient
+	docs       []*doc.Document // 已读取还未消费的文档
+	highlights map[string]*index.DocHighlight
+	cursor     string // 节点的结果被截断时，最后一批中继续检索的游标
+}
+
+// 读取下一批非空的结果，流结束时返回false
+func (n *nodeStream) fill() (bool, error) {
+	for len(n.docs) == 0 {
+		batch, err := n.stream.Recv()
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		n.docs = batch.Docs
+		n.cursor = batch.NextCursor
+		n.highlights = make(map[string]*index.DocHighlight, len(batch.Highlights))
+		for _, highlight := range batch.Highlights {
+			n.highlights[highlight.Id] = highlight
+		}
+	}
+	return true, nil
+}
+
+// 按各节点当前的第一个文档排序的堆
+type nodeHeap []*nodeStream
+
+func (h nodeHeap) Len() int           { return len(h) }
+func (h nodeHeap) Less(i, j int) bool { return docBefore(h[i].docs[0], h[j].docs[0]) }
+func (h nodeHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *nodeHeap) Push(x any)        { *h = append(*h, x.(*nodeStream)) }
+func (h *nodeHeap) Pop() any {
+	old := *h
+	n := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return n
+}
+
+// 在集群上流式检索，返回的迭代器用完后需要Close
+// 分页参数在归并后生效，每个节点从游标处返回前TopK个或前offset+limit个
+func (s *Sentinel) SearchStream(request *index.SearchRequest) *SearchIterator {
+	ctx, cancel := context.WithCancel(context.Background())
+	it := &SearchIterator{cancel: cancel, nodes: &nodeHeap{}, skip: max(int(request.Offset), 0), remain: -1}
+	if err := checkPage(request); err != nil {
+		// 参数错误时返回空的迭代器，Err返回错误
+		it.errs = append(it.errs, err)
+		it.Close()
+		return it
+	}
+	// TopK从第一个结果开始计数，Limit从跳过的文档之后计数
+	if request.TopK > 0 {
+		it.remain = max(int(request.TopK)-it.skip, 0)
+	}
+	if request.Limit > 0 && (it.remain < 0 || int(request.Limit) < it.remain) {
+		it.remain = int(request.Limit)
+	}
+	nodeRequest := &index.SearchRequest{
+		Query:     request.Query,
+		OnFlag:    request.OnFlag,
+		OffFlag:   request.OffFlag,
+		OrFlags:   request.OrFlags,
+		TopK:      request.TopK,
+		Limit:     int32(pageWindow(request)),
+		Cursor:    request.Cursor,
+		Index:     request.Index,
+		Highlight: request.Highlight,
+		BatchSize: request.BatchSize,
+	}
+	if len(nodeRequest.Index) == 0 {
+		nodeRequest.Index = s.index
+	}
+
+	lock := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	for _, endpoint := range s.GetServiceEndpoints(INDEX_SERVICE) {
+		wg.Add(1)
+		go func(endpoint *ServiceHub.EndPoint) {
+			defer wg.Done()
+			conn := s.GetGrpcConn(endpoint)
+			if conn == nil {
+				lock.Lock()
+				it.errs = append(it.errs, fmt.Errorf("connect to %s failed", endpoint.SelfAddr))
+				lock.Unlock()
+				return
+			}
+			node := &nodeStream{addr: endpoint.SelfAddr}
+			stream, err := index.NewIndexServiceClient(conn).SearchStream(ctx, nodeRequest)
+			ok := false
+			if err == nil {
+				node.stream = stream
+				ok, err = node.fill()
+			}
+			lock.Lock()
+			defer lock.Unlock()
+			if err != nil {
+				it.errs = append(it.errs, fmt.Errorf("search stream from worker %s failed: %w", endpoint.SelfAddr, err))
+			} else if ok {
+				*it.nodes = append(*it.nodes, node)
+			} else {
+				it.truncate(node)
+			}
+		}(endpoint)
+	}
+	wg.Wait()
+	heap.Init(it.nodes)
+	return it
+}
+
+// 移动到下一个文档，没有更多结果时返回false并关闭迭代器
+func (it *SearchIterator) Next() bool {
+	for ; it.skip > 0; it.skip-- {
+		if !it.advance() {
+			it.Close()
+			return false
+		}
+	}
+	if it.remain == 0 || !it.advance() {
+		it.Close()
+		return false
+	}
+	if it.remain > 0 {
+		it.remain--
+	}
+	return true
+}
+
+// 当前文档
+func (it *SearchIterator) Doc() *doc.Document {
+	return it.doc
+}
+
+// 当前文档的高亮片段，没有时返回nil
+func (it *SearchIterator) Highlight() *index.DocHighlight {
+	return it.highlight
+}
+
+// 迭代结束后，有节点的结果被截断时返回继续检索的游标，结果完整或者因为TopK、Limit结束时返回空
+func (it *SearchIterator) NextCursor() string {
+	if !it.exhausted {
+		return ""
+	}
+	return it.cursor
+}
+
+// 节点的错误，不为nil时结果不完整
+func (it *SearchIterator) Err() error {
+	return errors.Join(it.errs...)
+}
+
+// 关闭所有节点的结果流
+func (it *SearchIterator) Close() {
+	it.cancel()
+}
+
+// 取出所有节点中排在最前面的文档，节点的文档消费完时读下一批，出错的节点不再参与归并
+// 有节点被截断时，只能返回排在它最后一个文档之前的结果，其他节点之后的文档可能排在它未返回的文档之后
+func (it *SearchIterator) advance() bool {
+	if it.nodes.Len() == 0 {
+		it.doc, it.highlight, it.exhausted = nil, nil, true
+		return false
+	}
+	node := (*it.nodes)[0]
+	if first := node.docs[0]; it.bound != nil && it.bound.after(first.Score, first.Id) {
+		it.doc, it.highlight, it.exhausted = nil, nil, true
+		return false
+	}
+	it.doc = node.docs[0]
+	it.highlight = node.highlights[it.doc.Id]
+	node.docs = node.docs[1:]
+	if ok, err := node.fill(); err != nil || !ok {
+		if err != nil {
+			it.errs = append(it.errs, fmt.Errorf("search stream from worker %s failed: %w", node.addr, err))
+		} else {
+			it.truncate(node)
+		}
+		heap.Pop(it.nodes)
+	} else {
+		heap.Fix(it.nodes, 0)
+	}
+	return true
+}
+
+// 节点的结果流结束时，如果被截断，用它的游标限制之后返回的文档，多个节点被截断时取排在最前面的
+func (it *SearchIterator) truncate(node *nodeStream) {
+	if len(node.cursor) == 0 {
+		return
+	}
+	bound, err := decodeCursor(node.cursor)
+	if err != nil {
+		it.errs = append(it.errs, fmt.Errorf("search stream from worker %s failed: %w", node.addr, err))
+		return
+	}
+	if it.bound == nil || bound.after(it.bound.Score, it.bound.Id) {
+		it.bound, it.cursor = bound, node.cursor
+	}
+}
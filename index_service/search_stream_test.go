@@ -0,0 +1,136 @@
+package index_service
+
+import (
+	"Research/types/doc"
+	"Research/types/index"
+	"container/heap"
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+
+	"google.golang.org/grpc"
+)
+
+// 按顺序返回batches的结果流，之后返回err，err为nil时返回io.EOF
+type fakeSearchStream struct {
+	grpc.ClientStream
+	batches []*index.SearchBatch
+	err     error
+}
+
+func (s *fakeSearchStream) Recv() (*index.SearchBatch, error) {
+	if len(s.batches) == 0 {
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, io.EOF
+	}
+	batch := s.batches[0]
+	s.batches = s.batches[1:]
+	return batch, nil
+}
+
+func scoredDocs(scores ...float64) []*doc.Document {
+	docs := make([]*doc.Document, 0, len(scores))
+	for _, score := range scores {
+		docs = append(docs, &doc.Document{Id: string(rune('a' + int(score))), Score: score})
+	}
+	return docs
+}
+
+// 用各节点的结果流创建迭代器，和Sentinel.SearchStream一样先读取第一批
+func newTestIterator(t *testing.T, streams ...*fakeSearchStream) *SearchIterator {
+	_, cancel := context.WithCancel(context.Background())
+	it := &SearchIterator{cancel: cancel, nodes: &nodeHeap{}, remain: -1}
+	for i, stream := range streams {
+		node := &nodeStream{addr: string(rune('A' + i)), stream: stream}
+		ok, err := node.fill()
+		if err != nil {
+			it.errs = append(it.errs, err)
+		} else if ok {
+			*it.nodes = append(*it.nodes, node)
+		} else {
+			it.truncate(node)
+		}
+	}
+	heap.Init(it.nodes)
+	t.Cleanup(it.Close)
+	return it
+}
+
+func iterScores(it *SearchIterator) []float64 {
+	scores := make([]float64, 0)
+	for it.Next() {
+		scores = append(scores, it.Doc().Score)
+	}
+	return scores
+}
+
+func TestSearchIteratorClosesWhenSkipRunsOut(t *testing.T) {
+	closed := false
+	// 没有节点返回结果，跳过的文档数大于结果数
+	it := &SearchIterator{cancel: func() { closed = true }, nodes: &nodeHeap{}, skip: 3, remain: -1}
+	if it.Next() {
+		t.Fatal("want no more docs")
+	}
+	if !closed {
+		t.Error("iterator is not closed after skipping all docs")
+	}
+}
+
+func TestSearchIteratorStopsAtTruncatedNode(t *testing.T) {
+	// A的结果在6之后被截断，B中排在6之后的文档不能返回，否则会漏掉A中没有返回的文档
+	it := newTestIterator(t,
+		&fakeSearchStream{batches: []*index.SearchBatch{
+			{Docs: scoredDocs(10, 8)},
+			{Docs: scoredDocs(6), NextCursor: encodeCursor(6, "g")},
+		}},
+		&fakeSearchStream{batches: []*index.SearchBatch{{Docs: scoredDocs(9, 7, 5, 3)}}},
+	)
+	if got := iterScores(it); !slices.Equal(got, []float64{10, 9, 8, 7, 6}) {
+		t.Errorf("got %v", got)
+	}
+	if it.NextCursor() != encodeCursor(6, "g") {
+		t.Errorf("cursor: got %q", it.NextCursor())
+	}
+	if it.Err() != nil {
+		t.Error(it.Err())
+	}
+}
+
+func TestSearchIteratorCompleteWithoutCursor(t *testing.T) {
+	it := newTestIterator(t,
+		&fakeSearchStream{batches: []*index.SearchBatch{{Docs: scoredDocs(4, 2)}}},
+		&fakeSearchStream{batches: []*index.SearchBatch{{Docs: scoredDocs(3)}}},
+	)
+	if got := iterScores(it); !slices.Equal(got, []float64{4, 3, 2}) {
+		t.Errorf("got %v", got)
+	}
+	if len(it.NextCursor()) > 0 || it.Err() != nil {
+		t.Errorf("cursor %q err %v", it.NextCursor(), it.Err())
+	}
+}
+
+func TestSearchIteratorReturnsNodeErrors(t *testing.T) {
+	failed := errors.New("node down")
+	it := newTestIterator(t,
+		&fakeSearchStream{batches: []*index.SearchBatch{{Docs: scoredDocs(5, 1)}}, err: failed},
+		&fakeSearchStream{batches: []*index.SearchBatch{{Docs: scoredDocs(3)}}},
+	)
+	// 出错之前读到的文档和其他节点的结果照常返回
+	if got := iterScores(it); !slices.Equal(got, []float64{5, 3, 1}) {
+		t.Errorf("got %v", got)
+	}
+	if !errors.Is(it.Err(), failed) {
+		t.Errorf("got %v, want %v", it.Err(), failed)
+	}
+}
+
+func TestSearchStreamRejectsNegativePage(t *testing.T) {
+	it := (&Sentinel{}).SearchStream(&index.SearchRequest{Offset: -1})
+	if it.Next() || !errors.Is(it.Err(), errPage) {
+		t.Errorf("got %v, want %v", it.Err(), errPage)
+	}
+}
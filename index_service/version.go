@@ -0,0 +1,91 @@
+package index_service
+
+import (
+	"Research/types/index"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+)
+
+// 写入条件不满足，可以用errors.Is判断
+var ErrVersionConflict = errors.New("version conflict")
+
+// 版本冲突，带有文档当前的版本
+type ConflictError struct {
+	DocId   string
+	Version uint64 // 文档当前的版本，0表示不存在
+}
+
+func (e *ConflictError) Error() string {
+	if e.Version == 0 {
+		return fmt.Sprintf("version conflict: doc %s does not exist", e.DocId)
+	}
+	return fmt.Sprintf("version conflict: doc %s is at version %d", e.DocId, e.Version)
+}
+
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrVersionConflict
+}
+
+// 检查写入条件，version是文档当前的版本，不存在时为0
+func checkCondition(docId string, version uint64, cond *index.Condition) error {
+	if cond == nil {
+		return nil
+	}
+	if cond.IfAbsent && version > 0 || cond.IfVersion > 0 && cond.IfVersion != version {
+		return &ConflictError{DocId: docId, Version: version}
+	}
+	return nil
+}
+
+// 把写入结果转成AffectedCount，版本冲突不作为错误返回，由Conflict标记
+func affectedCount(n int, version uint64, err error) (*index.AffectedCount, error) {
+	var conflict *ConflictError
+	if errors.As(err, &conflict) {
+		return &index.AffectedCount{Conflict: true, Version: conflict.Version}, nil
+	}
+	return &index.AffectedCount{Count: int32(n), Version: version}, err
+}
+
+// 把AffectedCount中的冲突还原成ConflictError
+func conflictOf(docId string, affected *index.AffectedCount) error {
+	if affected != nil && affected.Conflict {
+		return &ConflictError{DocId: docId, Version: affected.Version}
+	}
+	return nil
+}
+
+const docLockShards = 64 // 文档锁的分片数
+
+// 按业务id分片的锁，同一文档的读-检查-写在锁内完成，不同文档大多可以并发写
+type docLocks [docLockShards]sync.Mutex
+
+func docShard(docId string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(docId))
+	return int(h.Sum32() % docLockShards)
+}
+
+// 锁住docIds所在的分片，按分片下标顺序加锁避免死锁，返回解锁函数
+func (l *docLocks) lock(docIds ...string) func() {
+	shards := make([]int, 0, len(docIds))
+	seen := make(map[int]struct{}, len(docIds))
+	for _, docId := range docIds {
+		shard := docShard(docId)
+		if _, exist := seen[shard]; !exist {
+			seen[shard] = struct{}{}
+			shards = append(shards, shard)
+		}
+	}
+	sort.Ints(shards)
+	for _, shard := range shards {
+		l[shard].Lock()
+	}
+	return func() {
+		for i := len(shards) - 1; i >= 0; i-- {
+			l[shards[i]].Unlock()
+		}
+	}
+}
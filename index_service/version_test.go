@@ -0,0 +1,176 @@
+package index_service
+
+import (
+	"Research/types/doc"
+	"Research/types/index"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestCheckCondition(t *testing.T) {
+	cases := []struct {
+		version  uint64
+		cond     *index.Condition
+		conflict bool
+	}{
+		{0, nil, false},
+		{3, nil, false},
+		{0, &index.Condition{IfAbsent: true}, false},
+		{1, &index.Condition{IfAbsent: true}, true},
+		{2, &index.Condition{IfVersion: 2}, false},
+		{3, &index.Condition{IfVersion: 2}, true},
+		{0, &index.Condition{IfVersion: 2}, true},
+	}
+	for _, c := range cases {
+		err := checkCondition("a", c.version, c.cond)
+		if conflict := errors.Is(err, ErrVersionConflict); conflict != c.conflict {
+			t.Errorf("version %d: got %v, want conflict %v", c.version, err, c.conflict)
+		}
+		var conflict *ConflictError
+		if c.conflict && (!errors.As(err, &conflict) || conflict.Version != c.version || conflict.DocId != "a") {
+			t.Errorf("version %d: got %v", c.version, err)
+		}
+	}
+}
+
+func TestAffectedCount(t *testing.T) {
+	affected, err := affectedCount(0, 0, &ConflictError{DocId: "a", Version: 4})
+	if err != nil || !affected.Conflict || affected.Version != 4 {
+		t.Errorf("conflict: got %v %v", affected.GetVersion(), err)
+	}
+	if err = conflictOf("a", affected); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("conflictOf: got %v", err)
+	}
+	affected, err = affectedCount(1, 2, nil)
+	if err != nil || affected.Conflict || affected.Count != 1 || affected.Version != 2 {
+		t.Errorf("ok: got count %d version %d", affected.GetCount(), affected.GetVersion())
+	}
+	if conflictOf("a", affected) != nil || conflictOf("a", nil) != nil {
+		t.Error("conflictOf without conflict should be nil")
+	}
+}
+
+func TestMergeAffected(t *testing.T) {
+	// 文档只在一个节点上，其他节点因为不存在而冲突
+	n, err := mergeAffected("a", []*index.AffectedCount{{Conflict: true}, {Count: 1, Version: 3}, nil})
+	if n != 1 || err != nil {
+		t.Errorf("got %d %v", n, err)
+	}
+	_, err = mergeAffected("a", []*index.AffectedCount{{Conflict: true}, {Conflict: true, Version: 5}, nil})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || conflict.Version != 5 {
+		t.Errorf("got %v, want conflict at version 5", err)
+	}
+}
+
+func TestDocVersions(t *testing.T) {
+	eachIndexType(t, func(t *testing.T, indexer *Indexer) {
+		d := &doc.Document{Id: "a", Keywords: []*doc.KeyWord{{Field: "f", Word: "w"}}}
+		if _, err := indexer.AddDocIf(d, &index.Condition{IfAbsent: true}); err != nil || d.Version != 1 {
+			t.Fatalf("create: version %d %v", d.Version, err)
+		}
+		if _, err := indexer.AddDocIf(&doc.Document{Id: "a"}, &index.Condition{IfAbsent: true}); !errors.Is(err, ErrVersionConflict) {
+			t.Errorf("create existing: got %v", err)
+		}
+		// 客户端传入的版本号被忽略，由索引维护
+		d = &doc.Document{Id: "a", Version: 100, Keywords: []*doc.KeyWord{{Field: "f", Word: "w"}}}
+		if _, err := indexer.AddDocIf(d, &index.Condition{IfVersion: 1}); err != nil || d.Version != 2 {
+			t.Fatalf("replace: version %d %v", d.Version, err)
+		}
+		if _, err := indexer.AddDocIf(&doc.Document{Id: "a"}, &index.Condition{IfVersion: 1}); !errors.Is(err, ErrVersionConflict) {
+			t.Errorf("stale replace: got %v", err)
+		}
+
+		updated, err := indexer.updateDoc(&index.UpdateDocRequest{DocId: "a", Condition: &index.Condition{IfVersion: 2}})
+		if err != nil || updated.Version != 3 {
+			t.Fatalf("update: %v", err)
+		}
+		if _, err = indexer.updateDoc(&index.UpdateDocRequest{DocId: "a", Condition: &index.Condition{IfVersion: 2}}); !errors.Is(err, ErrVersionConflict) {
+			t.Errorf("stale update: got %v", err)
+		}
+
+		if _, err = indexer.DeleteDocIf("a", &index.Condition{IfVersion: 2}); !errors.Is(err, ErrVersionConflict) {
+			t.Errorf("stale delete: got %v", err)
+		}
+		if n, err := indexer.DeleteDocIf("a", &index.Condition{IfVersion: 3}); n != 1 || err != nil {
+			t.Errorf("delete: got %d %v", n, err)
+		}
+		if _, err = indexer.DeleteDocIf("a", &index.Condition{IfVersion: 3}); !errors.Is(err, ErrVersionConflict) {
+			t.Errorf("delete missing doc with version: got %v", err)
+		}
+	})
+}
+
+// 各入口对业务id的规范化一致，带空白的id指向同一个文档
+func TestDocIdNormalized(t *testing.T) {
+	eachIndexType(t, func(t *testing.T, indexer *Indexer) {
+		if _, err := indexer.AddDoc(&doc.Document{Id: " a ", Keywords: []*doc.KeyWord{{Field: "f", Word: "w"}}}); err != nil {
+			t.Fatal(err)
+		}
+		if d := indexer.GetDoc("a\t"); d == nil || d.Id != "a" {
+			t.Fatalf("get: %v", d)
+		}
+		if _, err := indexer.DeleteDocIf(" a", &index.Condition{IfVersion: 2}); !errors.Is(err, ErrVersionConflict) {
+			t.Errorf("stale delete: got %v", err)
+		}
+		if n, err := indexer.DeleteDocIf(" a", &index.Condition{IfVersion: 1}); n != 1 || err != nil {
+			t.Errorf("delete: got %d %v", n, err)
+		}
+		if indexer.GetDoc("a") != nil {
+			t.Error("doc is not deleted")
+		}
+		if n, err := indexer.DeleteDocIf("  ", nil); n != 0 || err != nil {
+			t.Errorf("delete empty id: got %d %v", n, err)
+		}
+	})
+}
+
+func TestBulkAddConditions(t *testing.T) {
+	indexer := newTestIndexer(t, 0)
+	indexer.AddDoc(&doc.Document{Id: "a"})
+	res := indexer.addDocs(
+		[]*doc.Document{{Id: "a"}, {Id: "b"}, {Id: "c"}},
+		[]*index.Condition{{IfAbsent: true}, {IfAbsent: true}, nil})
+	if res.Count != 2 {
+		t.Errorf("count: got %d, want 2", res.Count)
+	}
+	if item := res.Items[0]; item.Ok || !item.Conflict || item.Version != 1 {
+		t.Errorf("item a: ok %v conflict %v version %d", item.Ok, item.Conflict, item.Version)
+	}
+	if !res.Items[1].Ok || !res.Items[2].Ok || res.Items[1].Version != 1 {
+		t.Error("items b and c should be added")
+	}
+}
+
+// 并发的条件写入中，同一版本只有一个能成功
+func TestConcurrentConditionalWrites(t *testing.T) {
+	indexer := newTestIndexer(t, 0)
+	indexer.AddDoc(&doc.Document{Id: "a"})
+	var ok, conflict int
+	var lock sync.Mutex
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			d := &doc.Document{Id: "a", Keywords: []*doc.KeyWord{{Field: "f", Word: strconv.Itoa(i)}}}
+			_, err := indexer.AddDocIf(d, &index.Condition{IfVersion: 1})
+			lock.Lock()
+			defer lock.Unlock()
+			if err == nil {
+				ok++
+			} else if errors.Is(err, ErrVersionConflict) {
+				conflict++
+			}
+		}(i)
+	}
+	wg.Wait()
+	if ok != 1 || conflict != 19 {
+		t.Errorf("ok %d conflict %d, want 1 and 19", ok, conflict)
+	}
+	if d := indexer.GetDoc("a"); d.Version != 2 {
+		t.Errorf("version: got %d, want 2", d.Version)
+	}
+}
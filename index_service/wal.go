@@ -0,0 +1,144 @@
+package index_service
+
+import (
+	"Research/util"
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// 日志操作类型
+const (
+	walAdd byte = iota + 1
+	walDelete
+)
+
+const walHeaderSize = 8 // 每条日志的头部：4字节长度 + 4字节crc32校验和
+
+var errWalCorrupted = errors.New("wal record corrupted")
+
+// 一条预写日志
+type walRecord struct {
+	Op    byte   // 操作类型
+	DocId string // 业务id
+	IntId uint64 // 倒排索引上的文档id
+	Doc   []byte // 序列化后的文档，删除操作为空
+}
+
+// 追加写的预写日志，每条日志格式为 | 长度 | crc32 | gob编码的walRecord |
+type wal struct {
+	file *os.File
+	path string
+	lock sync.Mutex
+}
+
+// 打开日志文件，不存在则创建
+func openWal(path string) (*wal, error) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &wal{file: file, path: path}, nil
+}
+
+// 追加一条日志，落盘后才返回
+func (w *wal) Append(record *walRecord) error {
+	var payload bytes.Buffer
+	if err := gob.NewEncoder(&payload).Encode(record); err != nil {
+		return err
+	}
+	buf := make([]byte, walHeaderSize, walHeaderSize+payload.Len())
+	binary.LittleEndian.PutUint32(buf[0:4], uint32(payload.Len()))
+	binary.LittleEndian.PutUint32(buf[4:8], crc32.ChecksumIEEE(payload.Bytes()))
+	buf = append(buf, payload.Bytes()...)
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if _, err := w.file.Write(buf); err != nil {
+		return err
+	}
+	return w.file.Sync()
+}
+
+// 从头回放日志，返回回放的条数
+// 遇到不完整或校验失败的日志(写到一半时崩溃)则停止，并截掉之后的内容
+func (w *wal) Replay(fn func(record *walRecord) error) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if _, err := w.file.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	reader := bufio.NewReader(w.file)
+	header := make([]byte, walHeaderSize)
+	var offset int64 // 最后一条完整日志的结束位置
+	n := 0
+	for {
+		record, size, err := readWalRecord(reader, header)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			// 截掉损坏的部分，之后的日志接着有效日志写入
+			util.Log.Printf("wal %s truncated at %d: %s", w.path, offset, err)
+			if err = w.file.Truncate(offset); err != nil {
+				return n, err
+			}
+			break
+		}
+		if err = fn(record); err != nil {
+			return n, err
+		}
+		offset += size
+		n++
+	}
+	return n, nil
+}
+
+// 读取一条日志，返回日志和占用的字节数
+func readWalRecord(reader io.Reader, header []byte) (*walRecord, int64, error) {
+	if _, err := io.ReadFull(reader, header); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, 0, errWalCorrupted
+		}
+		return nil, 0, err
+	}
+	length := binary.LittleEndian.Uint32(header[0:4])
+	checksum := binary.LittleEndian.Uint32(header[4:8])
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return nil, 0, errWalCorrupted
+	}
+	if crc32.ChecksumIEEE(payload) != checksum {
+		return nil, 0, errWalCorrupted
+	}
+	record := &walRecord{}
+	if err := gob.NewDecoder(bytes.NewReader(payload)).Decode(record); err != nil {
+		return nil, 0, errWalCorrupted
+	}
+	return record, int64(walHeaderSize + length), nil
+}
+
+// 清空日志，调用前需保证日志中的操作已经持久化到正排索引
+func (w *wal) Truncate() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if err := w.file.Truncate(0); err != nil {
+		return err
+	}
+	return w.file.Sync()
+}
+
+func (w *wal) Close() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.file.Close()
+}
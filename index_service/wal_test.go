@@ -0,0 +1,194 @@
+package index_service
+
+import (
+	"Research/etc"
+	"Research/types/doc"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func replayAll(t *testing.T, w *wal) []*walRecord {
+	t.Helper()
+	records := make([]*walRecord, 0)
+	if _, err := w.Replay(func(record *walRecord) error {
+		records = append(records, record)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return records
+}
+
+func TestWalReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wal", "log")
+	w, err := openWal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = w.Append(&walRecord{Op: walAdd, DocId: "a", IntId: 1, Doc: []byte("doc a")})
+	_ = w.Append(&walRecord{Op: walDelete, DocId: "a", IntId: 1})
+	_ = w.Close()
+
+	w, err = openWal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	records := replayAll(t, w)
+	if len(records) != 2 {
+		t.Fatalf("replayed %d records, want 2", len(records))
+	}
+	if r := records[0]; r.Op != walAdd || r.DocId != "a" || r.IntId != 1 || string(r.Doc) != "doc a" {
+		t.Errorf("first record: %+v", r)
+	}
+	if r := records[1]; r.Op != walDelete || len(r.Doc) != 0 {
+		t.Errorf("second record: %+v", r)
+	}
+
+	if err = w.Truncate(); err != nil {
+		t.Fatal(err)
+	}
+	if records = replayAll(t, w); len(records) != 0 {
+		t.Errorf("replayed %d records after truncate", len(records))
+	}
+}
+
+func TestWalTruncatesCorruptedTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log")
+	w, err := openWal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = w.Append(&walRecord{Op: walAdd, DocId: "a", IntId: 1})
+	info, _ := os.Stat(path)
+	valid := info.Size()
+	_ = w.Append(&walRecord{Op: walAdd, DocId: "b", IntId: 2})
+	_ = w.Close()
+	// 模拟写到一半时崩溃，最后一条日志不完整
+	if err = os.Truncate(path, info.Size()+5); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err = openWal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if records := replayAll(t, w); len(records) != 1 || records[0].DocId != "a" {
+		t.Fatalf("replayed %d records, want 1", len(records))
+	}
+	if info, _ = os.Stat(path); info.Size() != valid {
+		t.Errorf("size after replay: got %d, want %d", info.Size(), valid)
+	}
+	// 截断之后接着有效日志写入
+	_ = w.Append(&walRecord{Op: walAdd, DocId: "c", IntId: 3})
+	if records := replayAll(t, w); len(records) != 2 || records[1].DocId != "c" {
+		t.Errorf("replayed %d records after append, want 2", len(records))
+	}
+}
+
+func TestWalChecksumMismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log")
+	w, _ := openWal(path)
+	_ = w.Append(&walRecord{Op: walAdd, DocId: "a", IntId: 1, Doc: []byte("doc a")})
+	_ = w.Close()
+	data, _ := os.ReadFile(path)
+	data[len(data)-1] ^= 0xff
+	_ = os.WriteFile(path, data, 0o600)
+
+	w, _ = openWal(path)
+	defer w.Close()
+	if records := replayAll(t, w); len(records) != 0 {
+		t.Errorf("replayed %d corrupted records", len(records))
+	}
+}
+
+// 写入后没有做检查点就崩溃，重启时从日志恢复正排索引
+func TestIndexerRecoversFromWal(t *testing.T) {
+	dir := t.TempDir()
+	c := &etc.Config{}
+	c.ForwardIndex.Dbtype = "memory"
+	c.ForwardIndex.Addr = filepath.Join(dir, "data", "db")
+	c.Wal.Path = filepath.Join(dir, "wal", "log")
+	c.Expire.SweepInterval = -1
+
+	crashed := new(Indexer)
+	if err := crashed.Init(c); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"a", "b", "c"} {
+		if _, err := crashed.AddDoc(&doc.Document{Id: id, Keywords: []*doc.KeyWord{{Field: "f", Word: "w"}}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	crashed.DeleteDoc("b")
+	maxIntId := crashed.GetDoc("c").IntId
+	// 不调用Close，内存数据库的快照和日志都没有做检查点
+	close(crashed.stop)
+
+	indexer := new(Indexer)
+	if err := indexer.Init(c); err != nil {
+		t.Fatal(err)
+	}
+	defer indexer.Close()
+	if n := indexer.LoadFromIndexFile(1); n != 2 {
+		t.Errorf("loaded %d docs, want 2", n)
+	}
+	if indexer.GetDoc("a") == nil || indexer.GetDoc("c") == nil || indexer.GetDoc("b") != nil {
+		t.Error("forward index is not recovered from wal")
+	}
+	if ids := searchIds(t, indexer, "f", "w"); len(ids) != 2 {
+		t.Errorf("search after recovery: got %v", ids)
+	}
+	// 回放后做了检查点，日志被清空
+	if info, err := os.Stat(c.Wal.Path); err != nil || info.Size() != 0 {
+		t.Errorf("wal is not truncated after recovery")
+	}
+	// 重启后分配的IntId不与已有文档冲突
+	indexer.AddDoc(&doc.Document{Id: "d"})
+	if intId := indexer.GetDoc("d").IntId; intId <= maxIntId {
+		t.Errorf("IntId %d is not greater than %d", intId, maxIntId)
+	}
+}
+
+// 回放覆盖已有文档的记录时，删除旧IntId的映射
+func TestIndexerReplaysOverwrite(t *testing.T) {
+	dir := t.TempDir()
+	c := &etc.Config{}
+	c.ForwardIndex.Dbtype = "memory"
+	c.ForwardIndex.Addr = filepath.Join(dir, "data", "db")
+	c.Wal.Path = filepath.Join(dir, "wal", "log")
+	c.Expire.SweepInterval = -1
+
+	crashed := new(Indexer)
+	if err := crashed.Init(c); err != nil {
+		t.Fatal(err)
+	}
+	first := &doc.Document{Id: "a", Keywords: []*doc.KeyWord{{Field: "f", Word: "old"}}}
+	second := &doc.Document{Id: "a", Keywords: []*doc.KeyWord{{Field: "f", Word: "new"}}}
+	for _, d := range []*doc.Document{first, second} {
+		if _, err := crashed.AddDoc(d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	close(crashed.stop)
+
+	indexer := new(Indexer)
+	if err := indexer.Init(c); err != nil {
+		t.Fatal(err)
+	}
+	defer indexer.Close()
+	if _, exist := indexer.GetDocId(first.IntId); exist {
+		t.Errorf("IntId %d of the replaced doc is left", first.IntId)
+	}
+	if docId, exist := indexer.GetDocId(second.IntId); !exist || docId != "a" {
+		t.Errorf("IntId %d: got %q %v", second.IntId, docId, exist)
+	}
+	if n := indexer.LoadFromIndexFile(1); n != 1 {
+		t.Errorf("loaded %d docs, want 1", n)
+	}
+	if ids := searchIds(t, indexer, "f", "old"); len(ids) != 0 {
+		t.Errorf("replaced keyword is searchable: %v", ids)
+	}
+}
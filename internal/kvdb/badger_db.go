@@ -0,0 +1,303 @@
+package kvdb
+
+import (
+	"Research/util"
+	"bytes"
+	"errors"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+)
+
+const (
+	defaultGcInterval   = 10 * time.Minute // 默认value log垃圾回收间隔
+	defaultDiscardRatio = 0.5              // value log文件中可回收空间超过该比例时才重写
+)
+
+// 基于LSM-tree的嵌入式数据库，数据保存在本地目录中，适合写多读少的场景
+type Badger struct {
+	db           *badger.DB
+	path         string        // 数据目录
+	syncWrites   bool          // 每次写入是否同步刷盘
+	memTableSize int64         // 内存表大小，为0时使用badger默认值
+	gcInterval   time.Duration // value log垃圾回收间隔
+	cursor       []byte        // IterKeyByWeight上次遍历到的key
+	stop         chan struct{} // 通知后台gc协程退出
+	wg           sync.WaitGroup
+}
+
+func NewBadger() *Badger {
+	return &Badger{gcInterval: defaultGcInterval}
+}
+
+// 设置数据目录
+func (s *Badger) WithDataPath(path string) *Badger {
+	s.path = path
+	return s
+}
+
+// 设置写入时是否同步刷盘
+func (s *Badger) WithSyncWrites(sync bool) *Badger {
+	s.syncWrites = sync
+	return s
+}
+
+// 设置内存表大小
+func (s *Badger) WithMemTableSize(size int64) *Badger {
+	if size > 0 {
+		s.memTableSize = size
+	}
+	return s
+}
+
+// 设置value log垃圾回收间隔
+func (s *Badger) WithGcInterval(interval time.Duration) *Badger {
+	if interval > 0 {
+		s.gcInterval = interval
+	}
+	return s
+}
+
+func (s *Badger) Open() error {
+	if err := os.MkdirAll(s.path, os.ModePerm); err != nil {
+		return err
+	}
+	option := badger.DefaultOptions(s.path).
+		WithNumVersionsToKeep(1). // 正排索引只需要保留最新版本
+		WithSyncWrites(s.syncWrites).
+		WithLoggingLevel(badger.WARNING)
+	if s.memTableSize > 0 {
+		option = option.WithMemTableSize(s.memTableSize)
+	}
+	db, err := badger.Open(option)
+	if err != nil {
+		return err
+	}
+	s.db = db
+	util.Log.Printf("badger open %s", s.path)
+
+	// 后台定期回收value log
+	s.stop = make(chan struct{})
+	s.wg.Add(1)
+	go s.runGC()
+	return nil
+}
+
+// 定期执行value log垃圾回收，直到Close
+func (s *Badger) runGC() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.gcInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.checkAndGC()
+		}
+	}
+}
+
+// 一次回收可能只重写一个文件，循环到没有可回收的文件为止
+func (s *Badger) checkAndGC() {
+	lsmSize1, vlogSize1 := s.db.Size()
+	for {
+		if err := s.db.RunValueLogGC(defaultDiscardRatio); err != nil {
+			if !errors.Is(err, badger.ErrNoRewrite) && !errors.Is(err, badger.ErrRejected) {
+				util.Log.Printf("badger value log gc failed: %s", err)
+			}
+			break
+		}
+	}
+	lsmSize2, vlogSize2 := s.db.Size()
+	if vlogSize2 < vlogSize1 {
+		util.Log.Printf("badger before gc, lsm %d vlog %d; after gc, lsm %d vlog %d",
+			lsmSize1, vlogSize1, lsmSize2, vlogSize2)
+	}
+}
+
+func (s *Badger) GetDbPath() string {
+	return s.path
+}
+
+func (s *Badger) Set(k, v []byte) error {
+	return s.db.Update(func(txn *badger.Txn) error {
+		return txn.Set(k, v)
+	})
+}
+
+// 使用WriteBatch批量写入，数据量超过单个事务上限时会自动拆分
+func (s *Badger) BatchSet(keys, values [][]byte) error {
+	if len(keys) != len(values) {
+		return errors.New("key value not the same length")
+	}
+	wb := s.db.NewWriteBatch()
+	defer wb.Cancel()
+	for i, key := range keys {
+		if err := wb.Set(key, values[i]); err != nil {
+			return err
+		}
+	}
+	return wb.Flush()
+}
+
+func (s *Badger) Get(k []byte) ([]byte, error) {
+	var ival []byte
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(k)
+		if err != nil {
+			return err
+		}
+		ival, err = item.ValueCopy(nil)
+		return err
+	})
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return nil, ErrNoData
+	}
+	return ival, err
+}
+
+// 不存在的key对应的value为nil，返回值与keys一一对应
+func (s *Badger) BatchGet(keys [][]byte) ([][]byte, error) {
+	values := make([][]byte, len(keys))
+	err := s.db.View(func(txn *badger.Txn) error {
+		for i, key := range keys {
+			item, err := txn.Get(key)
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				continue
+			} else if err != nil {
+				return err
+			}
+			if values[i], err = item.ValueCopy(nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return values, err
+}
+
+func (s *Badger) Delete(k []byte) error {
+	return s.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete(k)
+	})
+}
+
+func (s *Badger) BatchDelete(keys [][]byte) error {
+	wb := s.db.NewWriteBatch()
+	defer wb.Cancel()
+	for _, key := range keys {
+		if err := wb.Delete(key); err != nil {
+			return err
+		}
+	}
+	return wb.Flush()
+}
+
+func (s *Badger) Has(k []byte) bool {
+	err := s.db.View(func(txn *badger.Txn) error {
+		_, err := txn.Get(k)
+		return err
+	})
+	return err == nil
+}
+
+func (s *Badger) IterDB(fn func(k, v []byte) error) int64 {
+	var total int64
+	_ = s.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			item := it.Item()
+			v, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			if err = fn(item.KeyCopy(nil), v); err != nil {
+				return err
+			}
+			total++
+		}
+		return nil
+	})
+	return total
+}
+
+func (s *Badger) IterKey(fn func(k []byte) error) int64 {
+	var total int64
+	_ = s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false // 只遍历key，不需要读取value
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			if err := fn(it.Item().KeyCopy(nil)); err != nil {
+				return err
+			}
+			total++
+		}
+		return nil
+	})
+	return total
+}
+
+// 每次遍历总数的rate比例，从上次结束的位置继续，遍历到末尾后下次从头开始
+// 在读事务中取出这一批数据，事务结束后再调用fn，与memory、bolt一致
+func (s *Badger) IterKeyByWeight(rate float64, fn func(k, v []byte) error) int64 {
+	count := s.IterKey(func(k []byte) error { return nil })
+	need := int64(math.Ceil(float64(count) * rate))
+
+	var keys, values [][]byte
+	_ = s.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+		if s.cursor == nil {
+			it.Rewind()
+		} else {
+			// 定位到上次结束位置的下一个key
+			it.Seek(s.cursor)
+			if it.Valid() && bytes.Equal(it.Item().Key(), s.cursor) {
+				it.Next()
+			}
+		}
+		for ; it.Valid() && int64(len(keys)) < need; it.Next() {
+			item := it.Item()
+			v, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			k := item.KeyCopy(nil)
+			keys = append(keys, k)
+			values = append(values, v)
+			s.cursor = k
+		}
+		// 写入游标
+		if !it.Valid() {
+			s.cursor = nil
+		}
+		return nil
+	})
+	for i := range keys {
+		// todo: 错误处理
+		_ = fn(keys[i], values[i])
+	}
+	return int64(len(keys))
+}
+
+// 把内存表和value log刷到磁盘
+func (s *Badger) Sync() error {
+	return s.db.Sync()
+}
+
+func (s *Badger) Close() error {
+	// Open失败时数据库和gc协程都没有启动
+	if s.db == nil {
+		return nil
+	}
+	close(s.stop)
+	s.wg.Wait()
+	return s.db.Close()
+}
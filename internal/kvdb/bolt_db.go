@@ -0,0 +1,289 @@
+package kvdb
+
+import (
+	"Research/util"
+	"bytes"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// 默认使用的bucket
+const DefaultBucket = "research"
+
+var ErrNoBucket = errors.New("bucket not exist")
+
+// 基于B+树的嵌入式数据库，数据保存在本地文件中
+type Bolt struct {
+	db     *bolt.DB
+	path   string // 数据文件路径
+	bucket []byte // 所有数据保存在同一个bucket中
+	cursor []byte // IterKeyByWeight上次遍历到的key
+}
+
+func NewBolt() *Bolt {
+	return &Bolt{bucket: []byte(DefaultBucket)}
+}
+
+// 设置数据文件路径
+func (s *Bolt) WithDataPath(path string) *Bolt {
+	s.path = path
+	return s
+}
+
+// 设置bucket
+func (s *Bolt) WithBucket(bucket string) *Bolt {
+	if len(bucket) > 0 {
+		s.bucket = []byte(bucket)
+	}
+	return s
+}
+
+func (s *Bolt) Open() error {
+	// 传入的是目录时，在目录下创建以bucket命名的数据文件，同一目录下不同bucket的索引不共用文件
+	if info, err := os.Stat(s.path); err == nil && info.IsDir() {
+		s.path = filepath.Join(s.path, string(s.bucket)+".db")
+	}
+	db, err := bolt.Open(s.GetDbPath(), 0o600, bolt.DefaultOptions)
+	if err != nil {
+		return err
+	}
+	// 不存在则创建bucket
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(s.bucket)
+		return err
+	})
+	if err != nil {
+		_ = db.Close()
+		return err
+	}
+	s.db = db
+	util.Log.Printf("bolt open %s bucket %s", s.path, string(s.bucket))
+	return nil
+}
+
+func (s *Bolt) GetDbPath() string {
+	return s.path
+}
+
+func (s *Bolt) Set(k, v []byte) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(s.bucket)
+		if bucket == nil {
+			return ErrNoBucket
+		}
+		return bucket.Put(k, v)
+	})
+}
+
+// 在一个事务中批量写入
+func (s *Bolt) BatchSet(keys, values [][]byte) error {
+	if len(keys) != len(values) {
+		return errors.New("key value not the same length")
+	}
+	return s.db.Batch(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(s.bucket)
+		if bucket == nil {
+			return ErrNoBucket
+		}
+		for i, key := range keys {
+			if err := bucket.Put(key, values[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Bolt) Get(k []byte) ([]byte, error) {
+	var ival []byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(s.bucket)
+		if bucket == nil {
+			return ErrNoBucket
+		}
+		// bolt返回的value只在事务内有效，需要拷贝出来
+		if val := bucket.Get(k); val != nil {
+			ival = bytes.Clone(val)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if ival == nil {
+		return nil, ErrNoData
+	}
+	return ival, nil
+}
+
+// 不存在的key对应的value为nil，返回值与keys一一对应
+func (s *Bolt) BatchGet(keys [][]byte) ([][]byte, error) {
+	values := make([][]byte, len(keys))
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(s.bucket)
+		if bucket == nil {
+			return ErrNoBucket
+		}
+		for i, key := range keys {
+			if val := bucket.Get(key); val != nil {
+				values[i] = bytes.Clone(val)
+			}
+		}
+		return nil
+	})
+	return values, err
+}
+
+func (s *Bolt) Delete(k []byte) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(s.bucket)
+		if bucket == nil {
+			return ErrNoBucket
+		}
+		return bucket.Delete(k)
+	})
+}
+
+func (s *Bolt) BatchDelete(keys [][]byte) error {
+	return s.db.Batch(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(s.bucket)
+		if bucket == nil {
+			return ErrNoBucket
+		}
+		for _, key := range keys {
+			if err := bucket.Delete(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Bolt) Has(k []byte) bool {
+	var exist bool
+	_ = s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(s.bucket)
+		if bucket == nil {
+			return ErrNoBucket
+		}
+		exist = bucket.Get(k) != nil
+		return nil
+	})
+	return exist
+}
+
+// 分批遍历，每批数据在读事务中复制出来，事务结束后再调用fn，fn中可以写入数据库
+func (s *Bolt) IterDB(fn func(k, v []byte) error) int64 {
+	var total int64
+	var after []byte
+	for {
+		keys, values := s.scan(after, iterBatch, true)
+		for i, k := range keys {
+			if err := fn(k, values[i]); err != nil {
+				return total
+			}
+			total++
+		}
+		if len(keys) < iterBatch {
+			return total
+		}
+		after = keys[len(keys)-1]
+	}
+}
+
+func (s *Bolt) IterKey(fn func(k []byte) error) int64 {
+	var total int64
+	var after []byte
+	for {
+		keys, _ := s.scan(after, iterBatch, false)
+		for _, k := range keys {
+			if err := fn(k); err != nil {
+				return total
+			}
+			total++
+		}
+		if len(keys) < iterBatch {
+			return total
+		}
+		after = keys[len(keys)-1]
+	}
+}
+
+// 在一个读事务中复制出after之后的最多limit条数据，after为nil时从头开始
+func (s *Bolt) scan(after []byte, limit int, withValue bool) ([][]byte, [][]byte) {
+	keys := make([][]byte, 0, limit)
+	var values [][]byte
+	if withValue {
+		values = make([][]byte, 0, limit)
+	}
+	_ = s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(s.bucket)
+		if bucket == nil {
+			return ErrNoBucket
+		}
+		c := bucket.Cursor()
+		var k, v []byte
+		if after == nil {
+			k, v = c.First()
+		} else if k, v = c.Seek(after); k != nil && bytes.Equal(k, after) {
+			k, v = c.Next()
+		}
+		for ; k != nil && len(keys) < limit; k, v = c.Next() {
+			keys = append(keys, bytes.Clone(k))
+			if withValue {
+				values = append(values, bytes.Clone(v))
+			}
+		}
+		return nil
+	})
+	return keys, values
+}
+
+// 每次遍历总数的rate比例，从上次结束的位置继续，遍历到末尾后下次从头开始
+// 在读事务中取出这一批数据，事务结束后再调用fn，fn中可以写入数据库(bolt的读事务中不能再开写事务)
+func (s *Bolt) IterKeyByWeight(rate float64, fn func(k, v []byte) error) int64 {
+	var keys, values [][]byte
+	_ = s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(s.bucket)
+		if bucket == nil {
+			return ErrNoBucket
+		}
+		need := int64(math.Ceil(float64(bucket.Stats().KeyN) * rate))
+
+		c := bucket.Cursor()
+		var k, v []byte
+		if s.cursor == nil {
+			k, v = c.First()
+		} else {
+			// 定位到上次结束位置的下一个key
+			k, v = c.Seek(s.cursor)
+			if k != nil && bytes.Equal(k, s.cursor) {
+				k, v = c.Next()
+			}
+		}
+		// 事务结束后k、v不再有效，需要复制
+		for ; k != nil && int64(len(keys)) < need; k, v = c.Next() {
+			keys = append(keys, bytes.Clone(k))
+			values = append(values, bytes.Clone(v))
+			s.cursor = keys[len(keys)-1]
+		}
+		// 写入游标
+		if k == nil {
+			s.cursor = nil
+		}
+		return nil
+	})
+	for i := range keys {
+		// todo: 错误处理
+		_ = fn(keys[i], values[i])
+	}
+	return int64(len(keys))
+}
+
+func (s *Bolt) Close() error {
+	return s.db.Close()
+}
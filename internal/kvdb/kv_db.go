@@ -1,11 +1,13 @@
 package kvdb
 
 import (
+	"Research/etc"
 	"Research/util"
 	"errors"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // 几种常见的基于LSM-tree算法实现的KV数据库
@@ -15,13 +17,15 @@ const (
 	REDIS
 )
 
+const iterBatch = 1000 // 本地数据库遍历时每次复制出的数据条数，复制完释放锁或事务后再调用回调
+
 type IKeyValueDB interface {
 	Open() error                                                    //初始化DB
 	GetDbPath() string                                              //获取存储数据的目录
 	Set(k, v []byte) error                                          //写入<key, value>
 	BatchSet(keys, values [][]byte) error                           //批量写入<key, value>
 	Get(k []byte) ([]byte, error)                                   //读取key对应的value
-	BatchGet(keys [][]byte) ([][]byte, error)                       //批量读取，注意不保证顺序
+	BatchGet(keys [][]byte) ([][]byte, error)                       //批量读取，与keys一一对应，不存在的key对应nil
 	Delete(k []byte) error                                          //删除
 	BatchDelete(keys [][]byte) error                                //批量删除
 	Has(k []byte) bool                                              //判断某个key是否存在
@@ -31,29 +35,52 @@ type IKeyValueDB interface {
 	Close() error                                                   //把内存中的数据flush到磁盘，同时释放文件锁
 }
 
+// 写入后不一定立即落盘的数据库实现该接口，检查点时调用Sync把数据刷到磁盘
+type ISyncer interface {
+	Sync() error
+}
+
 // Factory工厂模式，把类的创建和使用分隔开。Get函数就是一个工厂，它返回产品的接口，即它可以返回各种各样的具体产品。
-func GetKvdb(dbtype string, path string) (IKeyValueDB, error) {
+func GetKvdb(conf *etc.ForwardIndex) (IKeyValueDB, error) {
 	var db IKeyValueDB
-	switch dbtype {
+	path := conf.GetDateDir()
+	switch conf.Dbtype {
 	case "redis":
 		optsRedis, err := connectRemoteKvdb(path)
 		if err != nil {
 			return nil, err
 		}
+		if len(conf.Prefix) > 0 {
+			optsRedis = append(optsRedis, WithOptionPrefix(conf.Prefix))
+		}
+		if len(conf.SkipPrefix) > 0 {
+			optsRedis = append(optsRedis, WithOptionSkipPrefix(conf.SkipPrefix))
+		}
 		db = NewRedis(optsRedis...)
-	case "BADGER":
-		// todo: 添加这两个类
+	case "memory":
+		// 地址为空时不保存快照
+		if len(path) > 0 {
+			err := createLocalKvdb(path)
+			if err != nil {
+				return nil, err
+			}
+		}
+		db = NewMemory().WithDataPath(path)
+	case "badger", "BADGER":
 		err := createLocalKvdb(path)
 		if err != nil {
 			return nil, err
 		}
-		//db = new(Badger).WithDataPath(path)
+		db = NewBadger().WithDataPath(path).
+			WithSyncWrites(conf.SyncWrites).
+			WithMemTableSize(conf.MemTableSize).
+			WithGcInterval(time.Duration(conf.GcInterval) * time.Second)
 	default:
 		err := createLocalKvdb(path)
 		if err != nil {
 			return nil, err
 		}
-		//db = new(Bolt).WithDataPath(path).WithBucket("radic") //Builder生成器模式
+		db = NewBolt().WithDataPath(path).WithBucket(conf.Bucket) //Builder生成器模式
 	}
 	err := db.Open() //创建具体KVDB的细节隐藏在Open()函数里。在这里【创建类】
 	return db, err
@@ -0,0 +1,143 @@
+package kvdb
+
+import (
+	"Research/etc"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// 每种本地数据库各跑一遍
+func eachLocalDB(t *testing.T, fn func(t *testing.T, db IKeyValueDB)) {
+	for _, dbtype := range []string{"memory", "bolt", "badger"} {
+		t.Run(dbtype, func(t *testing.T) {
+			conf := &etc.ForwardIndex{Dbtype: dbtype, Addr: filepath.Join(t.TempDir(), "data", "db")}
+			db, err := GetKvdb(conf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() {
+				// 死锁时Close也会阻塞，失败时不关闭
+				if !t.Failed() {
+					_ = db.Close()
+				}
+			})
+			fn(t, db)
+		})
+	}
+}
+
+func TestIterKeyByWeightWritesInCallback(t *testing.T) {
+	eachLocalDB(t, func(t *testing.T, db IKeyValueDB) {
+		for i := 0; i < 10; i++ {
+			if err := db.Set([]byte("k"+strconv.Itoa(i)), []byte(strconv.Itoa(i))); err != nil {
+				t.Fatal(err)
+			}
+		}
+		done := make(chan int64)
+		go func() {
+			// 加载倒排索引时会在回调中写入IntId分配器的最大值
+			done <- db.IterKeyByWeight(1, func(k, v []byte) error {
+				return db.Set(append([]byte("copy_"), k...), v)
+			})
+		}()
+		select {
+		case n := <-done:
+			if n != 10 {
+				t.Errorf("iterated %d, want 10", n)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("writing in IterKeyByWeight callback deadlocks")
+		}
+		if v, err := db.Get([]byte("copy_k3")); err != nil || string(v) != "3" {
+			t.Errorf("got %q %v", v, err)
+		}
+	})
+}
+
+func TestIterKeyByWeightResumes(t *testing.T) {
+	eachLocalDB(t, func(t *testing.T, db IKeyValueDB) {
+		for i := 0; i < 10; i++ {
+			_ = db.Set([]byte("k"+strconv.Itoa(i)), []byte{byte(i)})
+		}
+		seen := make(map[string]int)
+		var total int64
+		// 每次遍历一半，两次遍历完，第三次从头开始
+		for i := 0; i < 3; i++ {
+			total += db.IterKeyByWeight(0.5, func(k, v []byte) error {
+				seen[string(k)]++
+				return nil
+			})
+		}
+		if total != 15 {
+			t.Errorf("iterated %d, want 15", total)
+		}
+		if len(seen) != 10 {
+			t.Errorf("saw %d keys, want 10", len(seen))
+		}
+	})
+}
+
+// 遍历过程中在回调里写入，不能死锁，写入的key不影响遍历的数量
+func TestIterDBWritesInCallback(t *testing.T) {
+	eachLocalDB(t, func(t *testing.T, db IKeyValueDB) {
+		for i := 0; i < iterBatch+10; i++ {
+			_ = db.Set([]byte("k"+strconv.Itoa(i)), []byte(strconv.Itoa(i)))
+		}
+		done := make(chan [2]int64)
+		go func() {
+			// 写入的key排在k之前，不会被遍历到
+			n := db.IterDB(func(k, v []byte) error {
+				return db.Set(append([]byte("copy_"), k...), v)
+			})
+			m := db.IterKey(func(k []byte) error {
+				return db.Set(append([]byte("a_"), k...), nil)
+			})
+			done <- [2]int64{n, m}
+		}()
+		select {
+		case n := <-done:
+			if n[0] != iterBatch+10 || n[1] != 2*(iterBatch+10) {
+				t.Errorf("iterated %d %d", n[0], n[1])
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatal("writing in IterDB callback deadlocks")
+		}
+		if v, err := db.Get([]byte("copy_k3")); err != nil || string(v) != "3" {
+			t.Errorf("got %q %v", v, err)
+		}
+	})
+}
+
+// 同一目录下不同bucket的bolt索引使用各自的数据文件
+func TestBoltFilePerBucket(t *testing.T) {
+	dir := t.TempDir()
+	a := NewBolt().WithDataPath(dir).WithBucket("a")
+	b := NewBolt().WithDataPath(dir).WithBucket("b")
+	for _, db := range []*Bolt{a, b} {
+		if err := db.Open(); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = db.Close() })
+	}
+	if a.GetDbPath() != filepath.Join(dir, "a.db") || b.GetDbPath() != filepath.Join(dir, "b.db") {
+		t.Errorf("got %s %s", a.GetDbPath(), b.GetDbPath())
+	}
+}
+
+// Open失败后Close不会panic
+func TestBadgerCloseAfterFailedOpen(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	db := NewBadger().WithDataPath(file)
+	if err := db.Open(); err == nil {
+		t.Fatal("open on a file: want error")
+	}
+	if err := db.Close(); err != nil {
+		t.Error(err)
+	}
+}
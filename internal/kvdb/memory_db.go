@@ -0,0 +1,257 @@
+package kvdb
+
+import (
+	"Research/util"
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"math"
+	"os"
+	"sync"
+
+	"github.com/huandu/skiplist"
+)
+
+// 纯内存的KV数据库，key有序，适合测试和临时索引
+// path不为空时，Open会加载快照文件，Close会把数据写入快照文件
+type Memory struct {
+	table  *skiplist.SkipList // key有序存储
+	path   string             // 快照文件路径
+	cursor []byte             // IterKeyByWeight上次遍历到的key
+	lock   sync.RWMutex
+}
+
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+// 设置快照文件路径
+func (s *Memory) WithDataPath(path string) *Memory {
+	s.path = path
+	return s
+}
+
+func (s *Memory) Open() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.table = skiplist.New(skiplist.String)
+	if len(s.path) == 0 {
+		return nil
+	}
+	// 加载快照
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	var snapshot map[string][]byte
+	if err = gob.NewDecoder(bytes.NewReader(data)).Decode(&snapshot); err != nil {
+		return err
+	}
+	for k, v := range snapshot {
+		s.table.Set(k, v)
+	}
+	util.Log.Printf("memory db load %d data from %s", len(snapshot), s.path)
+	return nil
+}
+
+func (s *Memory) GetDbPath() string {
+	return s.path
+}
+
+func (s *Memory) Set(k, v []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.table.Set(string(k), bytes.Clone(v))
+	return nil
+}
+
+func (s *Memory) BatchSet(keys, values [][]byte) error {
+	if len(keys) != len(values) {
+		return errors.New("key value not the same length")
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for i, key := range keys {
+		s.table.Set(string(key), bytes.Clone(values[i]))
+	}
+	return nil
+}
+
+func (s *Memory) Get(k []byte) ([]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	elem := s.table.Get(string(k))
+	if elem == nil {
+		return nil, ErrNoData
+	}
+	return bytes.Clone(elem.Value.([]byte)), nil
+}
+
+// 不存在的key对应的value为nil，返回值与keys一一对应
+func (s *Memory) BatchGet(keys [][]byte) ([][]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		if elem := s.table.Get(string(key)); elem != nil {
+			values[i] = bytes.Clone(elem.Value.([]byte))
+		}
+	}
+	return values, nil
+}
+
+func (s *Memory) Delete(k []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.table.Remove(string(k))
+	return nil
+}
+
+func (s *Memory) BatchDelete(keys [][]byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, key := range keys {
+		s.table.Remove(string(key))
+	}
+	return nil
+}
+
+func (s *Memory) Has(k []byte) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.table.Get(string(k)) != nil
+}
+
+// 遍历时持有读锁，fn中不能再写入数据库
+// 分批遍历，每批数据在锁内复制出来，释放锁之后再调用fn，fn中可以写入数据库
+func (s *Memory) IterDB(fn func(k, v []byte) error) int64 {
+	var total int64
+	var after []byte
+	for {
+		keys, values := s.scan(after, iterBatch, true)
+		for i, k := range keys {
+			if err := fn(k, values[i]); err != nil {
+				return total
+			}
+			total++
+		}
+		if len(keys) < iterBatch {
+			return total
+		}
+		after = keys[len(keys)-1]
+	}
+}
+
+func (s *Memory) IterKey(fn func(k []byte) error) int64 {
+	var total int64
+	var after []byte
+	for {
+		keys, _ := s.scan(after, iterBatch, false)
+		for _, k := range keys {
+			if err := fn(k); err != nil {
+				return total
+			}
+			total++
+		}
+		if len(keys) < iterBatch {
+			return total
+		}
+		after = keys[len(keys)-1]
+	}
+}
+
+// 在锁内复制出after之后的最多limit条数据，after为nil时从头开始
+func (s *Memory) scan(after []byte, limit int, withValue bool) ([][]byte, [][]byte) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	var elem *skiplist.Element
+	if after == nil {
+		elem = s.table.Front()
+	} else if elem = s.table.Find(string(after)); elem != nil && elem.Key().(string) == string(after) {
+		elem = elem.Next()
+	}
+	keys := make([][]byte, 0, limit)
+	var values [][]byte
+	if withValue {
+		values = make([][]byte, 0, limit)
+	}
+	for ; elem != nil && len(keys) < limit; elem = elem.Next() {
+		keys = append(keys, []byte(elem.Key().(string)))
+		if withValue {
+			values = append(values, bytes.Clone(elem.Value.([]byte)))
+		}
+	}
+	return keys, values
+}
+
+// 每次按key的顺序遍历总数的rate比例，从上次结束的位置继续，遍历到末尾后下次从头开始
+// 在锁内取出这一批数据，释放锁之后再调用fn，fn中可以写入数据库
+func (s *Memory) IterKeyByWeight(rate float64, fn func(k, v []byte) error) int64 {
+	keys, values := s.nextWeight(rate)
+	for i := range keys {
+		// todo: 错误处理
+		_ = fn(keys[i], values[i])
+	}
+	return int64(len(keys))
+}
+
+// 取出从游标开始的总数rate比例的数据，并移动游标
+func (s *Memory) nextWeight(rate float64) ([][]byte, [][]byte) {
+	s.lock.Lock() // 需要修改游标
+	defer s.lock.Unlock()
+	need := int64(math.Ceil(float64(s.table.Len()) * rate))
+
+	var elem *skiplist.Element
+	if s.cursor == nil {
+		elem = s.table.Front()
+	} else {
+		// 定位到上次结束位置的下一个key
+		elem = s.table.Find(string(s.cursor))
+		if elem != nil && elem.Key().(string) == string(s.cursor) {
+			elem = elem.Next()
+		}
+	}
+	keys := make([][]byte, 0, need)
+	values := make([][]byte, 0, need)
+	for ; elem != nil && int64(len(keys)) < need; elem = elem.Next() {
+		k := []byte(elem.Key().(string))
+		keys = append(keys, k)
+		values = append(values, bytes.Clone(elem.Value.([]byte)))
+		s.cursor = k
+	}
+	// 写入游标
+	if elem == nil {
+		s.cursor = nil
+	}
+	return keys, values
+}
+
+// 设置了快照路径时，把数据写入快照文件
+func (s *Memory) Sync() error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if len(s.path) == 0 {
+		return nil
+	}
+	snapshot := make(map[string][]byte, s.table.Len())
+	for elem := s.table.Front(); elem != nil; elem = elem.Next() {
+		snapshot[elem.Key().(string)] = elem.Value.([]byte)
+	}
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(snapshot); err != nil {
+		return err
+	}
+	// 先写临时文件再重命名，避免写到一半时崩溃损坏快照
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, buffer.Bytes(), 0o600); err != nil {
+		return err
+	}
+	util.Log.Printf("memory db save %d data to %s", len(snapshot), s.path)
+	return os.Rename(tmp, s.path)
+}
+
+func (s *Memory) Close() error {
+	return s.Sync()
+}
@@ -3,9 +3,11 @@ package kvdb
 import (
 	"Research/util"
 	"errors"
+	"fmt"
 	"github.com/go-redis/redis"
 	"math"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -16,6 +18,8 @@ type Redis struct {
 	addr   string        // 地址
 	passwd string        // 密码
 	dbno   int           // 数据库编号
+	prefix string        // key前缀，多个索引共用一个库时区分各自的key
+	skip   string        // 遍历时跳过prefix之后以skip开头的key，这些key属于其他索引
 }
 
 type OptionsRedis func(*Redis)
@@ -38,6 +42,18 @@ func WithOptionDbno(dbno int) OptionsRedis {
 	}
 }
 
+func WithOptionPrefix(prefix string) OptionsRedis {
+	return func(r *Redis) {
+		r.prefix = prefix
+	}
+}
+
+func WithOptionSkipPrefix(skip string) OptionsRedis {
+	return func(r *Redis) {
+		r.skip = skip
+	}
+}
+
 func NewRedis(opts ...OptionsRedis) *Redis {
 	r := &Redis{}
 	for _, opt := range opts {
@@ -62,8 +78,18 @@ func (r *Redis) GetDbPath() string {
 	panic("implement me")
 }
 
+// 加上前缀后的key
+func (r *Redis) key(k []byte) string {
+	return r.prefix + string(k)
+}
+
+// 遍历时去掉key的前缀
+func (r *Redis) trim(key string) []byte {
+	return []byte(strings.TrimPrefix(key, r.prefix))
+}
+
 func (r *Redis) Set(k, v []byte) error {
-	err := r.Db.Set(string(k), string(v), 0).Err()
+	err := r.Db.Set(r.key(k), string(v), 0).Err()
 	util.Log.Printf("redis set %s %s", string(k), string(v))
 	return err
 }
@@ -72,8 +98,8 @@ func (r *Redis) BatchSet(keys, values [][]byte) error {
 	length := len(keys) + len(values)
 	pairs := make([]any, 0, length)
 
-	for i := 0; i < length; i++ {
-		pairs = append(pairs, string(keys[i]), string(values[i]))
+	for i := 0; i < len(keys); i++ {
+		pairs = append(pairs, r.key(keys[i]), string(values[i]))
 	}
 	err := r.Db.MSet(pairs...).Err()
 	util.Log.Printf("redis BatchSet keys %d", len(keys))
@@ -81,7 +107,7 @@ func (r *Redis) BatchSet(keys, values [][]byte) error {
 }
 
 func (r *Redis) Get(k []byte) ([]byte, error) {
-	result, err := r.Db.Get(string(k)).Result()
+	result, err := r.Db.Get(r.key(k)).Result()
 	if err == redis.Nil {
 		return nil, ErrNoData
 	}
@@ -94,23 +120,23 @@ func (r *Redis) BatchGet(keys [][]byte) ([][]byte, error) {
 	ans := make([][]byte, 0, len(keys))
 	//构造参数
 	for i := 0; i < len(keys); i++ {
-		pairs = append(pairs, string(keys[i]))
+		pairs = append(pairs, r.key(keys[i]))
 	}
 
 	result, err := r.Db.MGet(pairs...).Result()
 	if err != nil {
 		return nil, err
 	}
-	//构造返回值
+	//构造返回值，与keys一一对应
 	for _, res := range result {
-		ans = append(ans, res.([]byte))
+		ans = append(ans, mgetValue(res))
 	}
 	util.Log.Printf("redis BatchGet result %v", ans)
 	return ans, nil
 }
 
 func (r *Redis) Delete(k []byte) error {
-	err := r.Db.Del(string(k)).Err()
+	err := r.Db.Del(r.key(k)).Err()
 	util.Log.Printf("redis delete %s", string(k))
 	return err
 }
@@ -118,7 +144,7 @@ func (r *Redis) Delete(k []byte) error {
 func (r *Redis) BatchDelete(keys [][]byte) error {
 	pairs := make([]string, 0, len(keys))
 	for i := 0; i < len(keys); i++ {
-		pairs = append(pairs, string(keys[i]))
+		pairs = append(pairs, r.key(keys[i]))
 	}
 	err := r.Db.Del(pairs...).Err()
 	util.Log.Printf("redis delete keys %d", len(keys))
@@ -126,21 +152,58 @@ func (r *Redis) BatchDelete(keys [][]byte) error {
 }
 
 func (r *Redis) Has(k []byte) bool {
-	result, _ := r.Db.Exists(string(k)).Result()
+	result, _ := r.Db.Exists(r.key(k)).Result()
 	util.Log.Printf("redis exists %s", string(k))
 	return result != 0
 }
 
+// MGet返回string，不存在的key对应nil
+func mgetValue(res interface{}) []byte {
+	if value, ok := res.(string); ok {
+		return []byte(value)
+	}
+	return nil
+}
+
+// 加载倒排索引时使用的锁和游标，以及其他索引的key，不是该索引的数据，遍历时跳过
+func (r *Redis) internal(key string) bool {
+	if len(r.skip) > 0 && strings.HasPrefix(key, r.prefix+r.skip) {
+		return true
+	}
+	return key == r.prefix+lock || key == r.prefix+CURSOR
+}
+
+// 去掉扫描到的key中不属于该索引数据的key
+func (r *Redis) dataKeys(keys []string) []string {
+	res := keys[:0]
+	for _, key := range keys {
+		if !r.internal(key) {
+			res = append(res, key)
+		}
+	}
+	return res
+}
+
 func (r *Redis) IterDB(fn func(k []byte, v []byte) error) int64 {
 	var cursor uint64 = 0
 	var ans int64 = 0
 	for {
-		keys, cursor := r.Db.Scan(cursor, ".*", 1000).Val()
-		result, _ := r.Db.MGet(keys...).Result()
-		ans += int64(len(keys))
-		for i := 0; i < len(keys); i++ {
+		var keys []string
+		keys, cursor = r.Db.Scan(cursor, r.prefix+"*", 1000).Val()
+		keys = r.dataKeys(keys)
+		result, err := r.mget(keys)
+		if err != nil {
+			util.Log.Printf("redis mget failed: %s", err)
+		}
+		for i := 0; i < len(result); i++ {
+			value := mgetValue(result[i])
+			// 扫描之后被删除的key跳过
+			if value == nil {
+				continue
+			}
+			ans++
 			// todo: 错误处理
-			_ = fn([]byte(keys[i]), result[i].([]byte))
+			_ = fn(r.trim(keys[i]), value)
 		}
 		if cursor == 0 {
 			break
@@ -149,15 +212,27 @@ func (r *Redis) IterDB(fn func(k []byte, v []byte) error) int64 {
 	return ans
 }
 
+// 扫描到的key为空时不请求redis，MGet不接受空参数
+func (r *Redis) mget(keys []string) ([]interface{}, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	return r.Db.MGet(keys...).Result()
+}
+
 func (r *Redis) IterKey(fn func(k []byte) error) int64 {
 	var cursor uint64 = 0
 	var ans int64 = 0
 	for {
-		keys, cursor := r.Db.Scan(cursor, ".*", 1000).Val()
-		ans += int64(len(keys))
+		var keys []string
+		keys, cursor = r.Db.Scan(cursor, r.prefix+"*", 1000).Val()
 		for i := 0; i < len(keys); i++ {
+			if r.internal(keys[i]) {
+				continue
+			}
+			ans++
 			// todo: 错误处理
-			_ = fn([]byte(keys[i]))
+			_ = fn(r.trim(keys[i]))
 		}
 		if cursor == 0 {
 			break
@@ -178,7 +253,7 @@ const (
 func (r *Redis) IterKeyByWeight(rate float64, fn func(k, v []byte) error) int64 {
 
 	// 直到获取锁
-	for err := r.Db.SetNX(lock, "", 0).Err(); err != nil; {
+	for err := r.Db.SetNX(r.prefix+lock, "", 0).Err(); err != nil; {
 		time.Sleep(100 * time.Millisecond)
 	}
 	// 获得keys数量
@@ -187,33 +262,44 @@ func (r *Redis) IterKeyByWeight(rate float64, fn func(k, v []byte) error) int64
 		util.Log.Println("执行lua err: ", err)
 		return 0
 	}
-	keys, _ := strconv.ParseFloat(result.(string), 64)
+	// dbsize返回整数
+	keys, _ := strconv.ParseFloat(fmt.Sprint(result), 64)
 	var cursor uint64 = 0
 	//判断是否存在cursor
-	cursorStr, err := r.Db.Get(CURSOR).Result()
+	cursorStr, err := r.Db.Get(r.prefix + CURSOR).Result()
 	if err == nil {
 		cursor, _ = strconv.ParseUint(cursorStr, 10, 64)
 	}
 	// 获得keySet
 	need := int64(math.Ceil(keys * rate))
 	cursor, _ = strconv.ParseUint(cursorStr, 10, 64)
-	keySet, cursor := r.Db.Scan(cursor, ".*", need).Val()
+	keySet, cursor := r.Db.Scan(cursor, r.prefix+"*", need).Val()
 	// 写入游标
 	if cursor == 0 {
-		r.Db.Del(CURSOR)
+		r.Db.Del(r.prefix + CURSOR)
 	} else {
-		r.Db.Set(CURSOR, cursor, 0)
+		r.Db.Set(r.prefix+CURSOR, cursor, 0)
 	}
 	// 解锁
-	r.Db.Del(lock)
+	r.Db.Del(r.prefix + lock)
 	// 将key插入倒排索引
-	val, _ := r.Db.MGet(keySet...).Result()
-	for i := 0; i < len(keySet); i++ {
+	keySet = r.dataKeys(keySet)
+	val, err := r.mget(keySet)
+	if err != nil {
+		util.Log.Printf("redis mget failed: %s", err)
+	}
+	var ans int64
+	for i := 0; i < len(val); i++ {
+		value := mgetValue(val[i])
+		if value == nil {
+			continue
+		}
+		ans++
 		// todo: 错误处理
-		_ = fn([]byte(keySet[i]), val[i].([]byte))
+		_ = fn(r.trim(keySet[i]), value)
 	}
 
-	return int64(len(keySet))
+	return ans
 }
 
 func (r *Redis) Close() error {
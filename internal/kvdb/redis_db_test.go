@@ -0,0 +1,55 @@
+package kvdb
+
+import (
+	"Research/internal/kvdb/redistest"
+	"bytes"
+	"testing"
+)
+
+func TestMgetValue(t *testing.T) {
+	// MGet返回string，不存在的key是nil
+	if v := mgetValue("doc"); !bytes.Equal(v, []byte("doc")) {
+		t.Errorf("got %q", v)
+	}
+	if v := mgetValue(nil); v != nil {
+		t.Errorf("missing key: got %q", v)
+	}
+}
+
+func TestMgetEmptyKeys(t *testing.T) {
+	// 没有扫描到key时不访问redis
+	r := &Redis{}
+	if res, err := r.mget(nil); err != nil || res != nil {
+		t.Errorf("got %v %v", res, err)
+	}
+}
+
+// 遍历时跳过其他索引的key以及加载用的锁和游标
+func TestRedisIterSkipsPrefix(t *testing.T) {
+	server := redistest.NewServer(t)
+	server.Set("app:a", "1")
+	server.Set("app:b", "2")
+	server.Set("app:sub:c", "3")
+	server.Set("other:d", "4")
+	r := NewRedis(WithOptionAddr(server.Addr()), WithOptionPrefix("app:"), WithOptionSkipPrefix("sub:"))
+	if err := r.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	for name, iter := range map[string]func(func(k, v []byte) error) int64{
+		"IterDB":          r.IterDB,
+		"IterKeyByWeight": func(fn func(k, v []byte) error) int64 { return r.IterKeyByWeight(1, fn) },
+	} {
+		seen := make(map[string]string)
+		n := iter(func(k, v []byte) error {
+			seen[string(k)] = string(v)
+			return nil
+		})
+		if n != 2 || len(seen) != 2 || seen["a"] != "1" || seen["b"] != "2" {
+			t.Errorf("%s: got %d %v", name, n, seen)
+		}
+	}
+	if n := r.IterKey(func(k []byte) error { return nil }); n != 2 {
+		t.Errorf("IterKey: got %d", n)
+	}
+}
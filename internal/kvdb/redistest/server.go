@@ -0,0 +1,192 @@
+// 测试用的redis服务，只实现正排索引用到的命令，数据保存在内存中
+package redistest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type Server struct {
+	listener net.Listener
+	data     map[string]string
+	lock     sync.Mutex
+}
+
+// 在随机端口启动服务，测试结束时关闭
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{listener: listener, data: make(map[string]string)}
+	go s.serve()
+	t.Cleanup(func() { _ = listener.Close() })
+	return s
+}
+
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// 直接读写数据，用于准备和检查测试数据
+func (s *Server) Set(key, value string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.data[key] = value
+}
+
+func (s *Server) Keys() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	keys := make([]string, 0, len(s.data))
+	for key := range s.data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (s *Server) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	writer := bufio.NewWriter(conn)
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		s.exec(writer, args)
+		if err = writer.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// 请求是由bulk string组成的数组
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := readLine(reader)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return nil, fmt.Errorf("unexpected line %q", line)
+	}
+	n, err := strconv.Atoi(line[1:])
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		if line, err = readLine(reader); err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimPrefix(line, "$"))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err = io.ReadFull(reader, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func readLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	return strings.TrimRight(line, "\r\n"), err
+}
+
+func (s *Server) exec(w *bufio.Writer, args []string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		fmt.Fprint(w, "+PONG\r\n")
+	case "SET":
+		s.data[args[1]] = args[2]
+		fmt.Fprint(w, "+OK\r\n")
+	case "MSET":
+		for i := 1; i+1 < len(args); i += 2 {
+			s.data[args[i]] = args[i+1]
+		}
+		fmt.Fprint(w, "+OK\r\n")
+	case "SETNX":
+		if _, exist := s.data[args[1]]; exist {
+			fmt.Fprint(w, ":0\r\n")
+		} else {
+			s.data[args[1]] = args[2]
+			fmt.Fprint(w, ":1\r\n")
+		}
+	case "GET":
+		writeValue(w, s.data, args[1])
+	case "MGET":
+		fmt.Fprintf(w, "*%d\r\n", len(args)-1)
+		for _, key := range args[1:] {
+			writeValue(w, s.data, key)
+		}
+	case "DEL", "EXISTS":
+		n := 0
+		for _, key := range args[1:] {
+			if _, exist := s.data[key]; exist {
+				n++
+				if strings.ToUpper(args[0]) == "DEL" {
+					delete(s.data, key)
+				}
+			}
+		}
+		fmt.Fprintf(w, ":%d\r\n", n)
+	case "SCAN":
+		// 一次返回全部匹配的key，只支持"前缀*"形式的MATCH
+		pattern := "*"
+		for i := 2; i+1 < len(args); i += 2 {
+			if strings.ToUpper(args[i]) == "MATCH" {
+				pattern = args[i+1]
+			}
+		}
+		prefix := strings.TrimSuffix(pattern, "*")
+		keys := make([]string, 0)
+		for key := range s.data {
+			if strings.HasPrefix(key, prefix) {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		fmt.Fprintf(w, "*2\r\n$1\r\n0\r\n*%d\r\n", len(keys))
+		for _, key := range keys {
+			fmt.Fprintf(w, "$%d\r\n%s\r\n", len(key), key)
+		}
+	case "EVAL":
+		// 只用于获取dbsize
+		fmt.Fprintf(w, ":%d\r\n", len(s.data))
+	default:
+		fmt.Fprintf(w, "-ERR unknown command '%s'\r\n", args[0])
+	}
+}
+
+func writeValue(w *bufio.Writer, data map[string]string, key string) {
+	if value, exist := data[key]; exist {
+		fmt.Fprintf(w, "$%d\r\n%s\r\n", len(value), value)
+	} else {
+		fmt.Fprint(w, "$-1\r\n")
+	}
+}
@@ -0,0 +1,164 @@
+package reverse_index
+
+import (
+	"Research/types/doc"
+	"Research/types/index"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// 聚合的数据来源，倒排索引根据匹配的文档集合提供统计方法
+type aggSource struct {
+	dict      *termDict
+	numerics  *numericIndex
+	termCount func(key string) int    // 关键词的倒排链上有多少匹配的文档
+	contains  func(intId uint64) bool // 文档是否匹配
+}
+
+// 在匹配的文档集合上计算聚合，source为nil表示没有匹配的文档
+func aggregate(aggs []*index.Aggregation, source *aggSource) []*index.AggregationResult {
+	if len(aggs) == 0 {
+		return nil
+	}
+	res := make([]*index.AggregationResult, 0, len(aggs))
+	for _, agg := range aggs {
+		result := &index.AggregationResult{Name: aggName(agg)}
+		if source != nil {
+			switch agg.Type {
+			case index.Aggregation_TERMS:
+				// 字段下每个词的倒排链与匹配的文档集合求交，不需要另外保存每个文档的关键词
+				for _, word := range source.dict.words(agg.Field) {
+					key := (&doc.KeyWord{Field: agg.Field, Word: word}).ToString()
+					if count := source.termCount(key); count > 0 {
+						result.Buckets = append(result.Buckets, &index.Bucket{Key: word, Count: int64(count)})
+					}
+				}
+			case index.Aggregation_HISTOGRAM:
+				if agg.Interval <= 0 {
+					break
+				}
+				// 数值从小到大遍历，同一区间的数值是连续的
+				var bucket *index.Bucket
+				source.numerics.each(agg.Field, func(value float64, docs map[uint64]any) {
+					count := countMatched(docs, source.contains)
+					if count == 0 {
+						return
+					}
+					if from := histogramFrom(value, agg.Interval); bucket == nil || bucket.From != from {
+						bucket = &index.Bucket{Key: strconv.FormatFloat(from, 'f', -1, 64), From: from}
+						result.Buckets = append(result.Buckets, bucket)
+					}
+					bucket.Count += int64(count)
+				})
+			case index.Aggregation_STATS:
+				source.numerics.each(agg.Field, func(value float64, docs map[uint64]any) {
+					count := countMatched(docs, source.contains)
+					if count == 0 {
+						return
+					}
+					if result.Count == 0 || value < result.Min {
+						result.Min = value
+					}
+					if result.Count == 0 || value > result.Max {
+						result.Max = value
+					}
+					result.Count += int64(count)
+					result.Sum += value * float64(count)
+				})
+			}
+		}
+		res = append(res, finishAggregation(agg, result))
+	}
+	return res
+}
+
+// 合并各节点的聚合结果，各节点的结果与aggs一一对应
+// 节点上的terms聚合需要返回全部词，合并后再取前Size个，结果才准确
+func MergeAggregations(aggs []*index.Aggregation, results ...[]*index.AggregationResult) []*index.AggregationResult {
+	if len(aggs) == 0 {
+		return nil
+	}
+	res := make([]*index.AggregationResult, 0, len(aggs))
+	for i, agg := range aggs {
+		merged := &index.AggregationResult{Name: aggName(agg)}
+		buckets := make(map[string]*index.Bucket)
+		for _, nodeResults := range results {
+			if i >= len(nodeResults) || nodeResults[i].Name != merged.Name {
+				continue
+			}
+			result := nodeResults[i]
+			for _, bucket := range result.Buckets {
+				if exist, ok := buckets[bucket.Key]; ok {
+					exist.Count += bucket.Count
+				} else {
+					copied := &index.Bucket{Key: bucket.Key, From: bucket.From, Count: bucket.Count}
+					buckets[bucket.Key] = copied
+					merged.Buckets = append(merged.Buckets, copied)
+				}
+			}
+			if result.Count > 0 {
+				if merged.Count == 0 || result.Min < merged.Min {
+					merged.Min = result.Min
+				}
+				if merged.Count == 0 || result.Max > merged.Max {
+					merged.Max = result.Max
+				}
+				merged.Count += result.Count
+				merged.Sum += result.Sum
+			}
+		}
+		res = append(res, finishAggregation(agg, merged))
+	}
+	return res
+}
+
+// 排序、截断并计算平均值
+func finishAggregation(agg *index.Aggregation, result *index.AggregationResult) *index.AggregationResult {
+	switch agg.Type {
+	case index.Aggregation_TERMS:
+		// 按文档数降序，相同时按词的字典序
+		sort.Slice(result.Buckets, func(i, j int) bool {
+			a, b := result.Buckets[i], result.Buckets[j]
+			if a.Count != b.Count {
+				return a.Count > b.Count
+			}
+			return a.Key < b.Key
+		})
+		if agg.Size > 0 && len(result.Buckets) > int(agg.Size) {
+			result.Buckets = result.Buckets[:agg.Size]
+		}
+	case index.Aggregation_HISTOGRAM:
+		sort.Slice(result.Buckets, func(i, j int) bool {
+			return result.Buckets[i].From < result.Buckets[j].From
+		})
+	case index.Aggregation_STATS:
+		if result.Count > 0 {
+			result.Avg = result.Sum / float64(result.Count)
+		}
+	}
+	return result
+}
+
+func aggName(agg *index.Aggregation) string {
+	if len(agg.Name) > 0 {
+		return agg.Name
+	}
+	return agg.Field
+}
+
+// 数值所在区间的下界
+func histogramFrom(value, interval float64) float64 {
+	return math.Floor(value/interval) * interval
+}
+
+// 集合中有多少匹配的文档
+func countMatched(docs map[uint64]any, contains func(intId uint64) bool) int {
+	count := 0
+	for intId := range docs {
+		if contains(intId) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,150 @@
+package reverse_index
+
+import (
+	"Research/types/doc"
+	"Research/types/index"
+	"Research/types/term_query"
+	"testing"
+)
+
+// 两种倒排索引各跑一遍，索引中有5个文档，前4个有tag:a
+func eachAggIndex(t *testing.T, fn func(t *testing.T, ri IReverseIndex)) {
+	for name, indexType := range map[string]int{"skiplist": SKIPLIST, "roaring": ROARING} {
+		t.Run(name, func(t *testing.T) {
+			ri := NewReverseIndex(indexType, 100)
+			docs := []struct {
+				brand string
+				price float64
+			}{{"x", 5}, {"y", 12}, {"x", 18}, {"z", 25}, {"x", 100}}
+			for i, d := range docs {
+				tag := "a"
+				if i == len(docs)-1 {
+					tag = "b"
+				}
+				ri.Add(doc.Document{
+					Id:       string(rune('0' + i)),
+					IntId:    uint64(i + 1),
+					Keywords: []*doc.KeyWord{{Field: "tag", Word: tag}, {Field: "brand", Word: d.brand}},
+					Numerics: []*doc.NumericField{{Field: "price", Value: d.price}},
+				})
+			}
+			fn(t, ri)
+		})
+	}
+}
+
+func tagQuery(word string) *term_query.TermQuery {
+	return term_query.NewTermQuery("tag", word)
+}
+
+func TestTermsAggregation(t *testing.T) {
+	eachAggIndex(t, func(t *testing.T, ri IReverseIndex) {
+		_, res := ri.Search(tagQuery("a"), nil, nil, nil, 0, []*index.Aggregation{
+			{Type: index.Aggregation_TERMS, Field: "brand"},
+			{Type: index.Aggregation_TERMS, Name: "top", Field: "brand", Size: 1},
+		})
+		if len(res) != 2 {
+			t.Fatalf("got %d results", len(res))
+		}
+		// 文档数降序，相同时按字典序，没有匹配文档的词不返回
+		assertBuckets(t, res[0].Buckets, []string{"x", "y", "z"}, []int64{2, 1, 1})
+		if res[1].Name != "top" {
+			t.Errorf("name: got %s", res[1].Name)
+		}
+		assertBuckets(t, res[1].Buckets, []string{"x"}, []int64{2})
+	})
+}
+
+// 文档更新和删除后，terms聚合按文档当前的关键词计数
+func TestTermsAggregationAfterUpdateAndDelete(t *testing.T) {
+	eachAggIndex(t, func(t *testing.T, ri IReverseIndex) {
+		// 文档2的brand从y改为z，文档3删除brand:x
+		ri.Update(&doc.Document{
+			Id:       "1",
+			IntId:    2,
+			Keywords: []*doc.KeyWord{{Field: "tag", Word: "a"}, {Field: "brand", Word: "y"}},
+			Numerics: []*doc.NumericField{{Field: "price", Value: 12}},
+		}, &doc.Document{
+			Id:       "1",
+			IntId:    2,
+			Keywords: []*doc.KeyWord{{Field: "tag", Word: "a"}, {Field: "brand", Word: "z"}},
+			Numerics: []*doc.NumericField{{Field: "price", Value: 12}},
+		})
+		ri.Delete(3, &doc.KeyWord{Field: "brand", Word: "x"})
+		_, res := ri.Search(tagQuery("a"), nil, nil, nil, 0, []*index.Aggregation{
+			{Type: index.Aggregation_TERMS, Field: "brand"},
+		})
+		assertBuckets(t, res[0].Buckets, []string{"z", "x"}, []int64{2, 1})
+	})
+}
+
+func TestHistogramAndStatsAggregation(t *testing.T) {
+	eachAggIndex(t, func(t *testing.T, ri IReverseIndex) {
+		_, res := ri.Search(tagQuery("a"), nil, nil, nil, 0, []*index.Aggregation{
+			{Type: index.Aggregation_HISTOGRAM, Field: "price", Interval: 10},
+			{Type: index.Aggregation_HISTOGRAM, Field: "price"},
+			{Type: index.Aggregation_STATS, Field: "price"},
+		})
+		assertBuckets(t, res[0].Buckets, []string{"0", "10", "20"}, []int64{1, 2, 1})
+		if len(res[1].Buckets) != 0 {
+			t.Errorf("histogram without interval: got %d buckets", len(res[1].Buckets))
+		}
+		stats := res[2]
+		if stats.Count != 4 || stats.Min != 5 || stats.Max != 25 || stats.Sum != 60 || stats.Avg != 15 {
+			t.Errorf("stats: count %d min %v max %v sum %v avg %v", stats.Count, stats.Min, stats.Max, stats.Sum, stats.Avg)
+		}
+	})
+}
+
+func TestAggregationWithoutMatches(t *testing.T) {
+	eachAggIndex(t, func(t *testing.T, ri IReverseIndex) {
+		_, res := ri.Search(tagQuery("none"), nil, nil, nil, 0, []*index.Aggregation{
+			{Type: index.Aggregation_TERMS, Field: "brand"},
+			{Type: index.Aggregation_STATS, Field: "price"},
+		})
+		if len(res) != 2 || len(res[0].Buckets) != 0 || res[1].Count != 0 {
+			t.Errorf("got %d results", len(res))
+		}
+	})
+}
+
+func TestMergeAggregations(t *testing.T) {
+	aggs := []*index.Aggregation{
+		{Type: index.Aggregation_TERMS, Field: "brand", Size: 2},
+		{Type: index.Aggregation_HISTOGRAM, Field: "price", Interval: 10},
+		{Type: index.Aggregation_STATS, Field: "price"},
+	}
+	node1 := []*index.AggregationResult{
+		{Name: "brand", Buckets: []*index.Bucket{{Key: "x", Count: 2}, {Key: "y", Count: 1}}},
+		{Name: "price", Buckets: []*index.Bucket{{Key: "10", From: 10, Count: 1}}},
+		{Name: "price", Count: 2, Min: 3, Max: 10, Sum: 13},
+	}
+	node2 := []*index.AggregationResult{
+		{Name: "brand", Buckets: []*index.Bucket{{Key: "z", Count: 2}, {Key: "y", Count: 2}}},
+		{Name: "price", Buckets: []*index.Bucket{{Key: "0", From: 0, Count: 1}, {Key: "10", From: 10, Count: 2}}},
+		{Name: "price", Count: 1, Min: 1, Max: 4, Sum: 4},
+	}
+	res := MergeAggregations(aggs, node1, node2, nil)
+	assertBuckets(t, res[0].Buckets, []string{"y", "x"}, []int64{3, 2})
+	assertBuckets(t, res[1].Buckets, []string{"0", "10"}, []int64{1, 3})
+	if stats := res[2]; stats.Count != 3 || stats.Min != 1 || stats.Max != 10 || stats.Sum != 17 {
+		t.Errorf("stats: count %d min %v max %v sum %v", stats.Count, stats.Min, stats.Max, stats.Sum)
+	}
+	// 节点上的原始结果不被修改
+	if node1[0].Buckets[1].Count != 1 {
+		t.Error("node result is modified")
+	}
+}
+
+func assertBuckets(t *testing.T, buckets []*index.Bucket, keys []string, counts []int64) {
+	t.Helper()
+	if len(buckets) != len(keys) {
+		t.Errorf("got %d buckets, want %v", len(buckets), keys)
+		return
+	}
+	for i, bucket := range buckets {
+		if bucket.Key != keys[i] || bucket.Count != counts[i] {
+			t.Errorf("bucket %d: got %s=%d, want %s=%d", i, bucket.Key, bucket.Count, keys[i], counts[i])
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package reverse_index
+
+import (
+	"Research/types/term_query"
+	"container/heap"
+	"math"
+	"sort"
+	"sync"
+)
+
+// BM25默认参数
+const (
+	DefaultK1 = 1.2  // 词频饱和度
+	DefaultB  = 0.75 // 文档长度归一化程度
+)
+
+// 检索命中的文档
+type SearchHit struct {
+	Id    string  // 业务id
+	IntId uint64  // 倒排索引上的id
+	Score float64 // 相关性得分
+}
+
+// BM25相关性打分
+type BM25 struct {
+	K1 float64
+	B  float64
+}
+
+func NewBM25() *BM25 {
+	return &BM25{K1: DefaultK1, B: DefaultB}
+}
+
+// 计算一个词对一篇文档的得分
+// tf 词在文档中出现的次数，docLen 文档长度，df 包含该词的文档数，docCount 文档总数，avgDocLen 平均文档长度
+func (s *BM25) Score(tf, docLen, df, docCount int, avgDocLen float64) float64 {
+	if tf <= 0 || df <= 0 {
+		return 0
+	}
+	idf := math.Log(1 + (float64(docCount)-float64(df)+0.5)/(float64(df)+0.5))
+	norm := 1 - s.B
+	if avgDocLen > 0 {
+		norm += s.B * float64(docLen) / avgDocLen
+	}
+	return idf * float64(tf) * (s.K1 + 1) / (float64(tf) + s.K1*norm)
+}
+
+// 文档长度统计，用于BM25的长度归一化
+type docStats struct {
+	lock     sync.RWMutex
+	docLens  map[uint64]int // 每个文档的关键词数量
+	totalLen int            // 所有文档的关键词总数
+}
+
+func newDocStats(DocNumEstimate int) *docStats {
+	return &docStats{docLens: make(map[uint64]int, DocNumEstimate)}
+}
+
+// 没有关键词的文档不参与打分，不计入文档数
+func (s *docStats) add(intId uint64, length int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.totalLen += length - s.docLens[intId]
+	if length > 0 {
+		s.docLens[intId] = length
+	} else {
+		delete(s.docLens, intId)
+	}
+}
+
+// 删除文档时对每个关键词都会调用，只有第一次生效
+func (s *docStats) remove(intId uint64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if length, exist := s.docLens[intId]; exist {
+		s.totalLen -= length
+		delete(s.docLens, intId)
+	}
+}
+
+func (s *docStats) docLen(intId uint64) int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.docLens[intId]
+}
+
+// 返回文档总数和平均文档长度
+func (s *docStats) collection() (int, float64) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if len(s.docLens) == 0 {
+		return 0, 0
+	}
+	return len(s.docLens), float64(s.totalLen) / float64(len(s.docLens))
+}
+
+// 统计每个关键词在文档中出现的次数
+func termFreqs(keys []string) map[string]int {
+	tfs := make(map[string]int, len(keys))
+	for _, key := range keys {
+		if len(key) > 0 {
+			tfs[key]++
+		}
+	}
+	return tfs
+}
+
+// 倒排索引提供打分需要的词频信息
+type termScorer interface {
+	docFreq(key string) int                  // 包含该词的文档数
+	termFreq(key string, intId uint64) int   // 词在文档中出现的次数
+	expand(t *term_query.MultiTerm) []string // 展开前缀、通配符、模糊查询
+}
+
+// 收集查询表达式中参与打分的关键词
+func queryTerms(q *term_query.TermQuery, scorer termScorer, terms []string) []string {
+	if q == nil {
+		return terms
+	}
+	if q.Keyword != nil {
+		return append(terms, q.Keyword.ToString())
+	}
+	if q.Phrase != nil {
+		return append(terms, q.Phrase.Keys()...)
+	}
+	if q.MultiTerm != nil {
+		return append(terms, scorer.expand(q.MultiTerm)...)
+	}
+	for _, val := range q.Must {
+		terms = queryTerms(val, scorer, terms)
+	}
+	for _, val := range q.Should {
+		terms = queryTerms(val, scorer, terms)
+	}
+	return terms
+}
+
+// 给命中的文档打分，each遍历命中的文档，topK大于0时只保留得分最高的topK个，结果按得分降序
+// 边打分边维护大小为topK的堆，不需要先收集所有命中的文档
+func rank(each func(fn func(id string, intId uint64)), q *term_query.TermQuery, scorer termScorer, stats *docStats, bm25 *BM25, topK int) []*SearchHit {
+	terms := termFreqs(queryTerms(q, scorer, nil))
+	docCount, avgDocLen := stats.collection()
+	dfs := make(map[string]int, len(terms))
+	for term := range terms {
+		dfs[term] = scorer.docFreq(term)
+	}
+
+	h := &hitHeap{}
+	each(func(id string, intId uint64) {
+		docLen := stats.docLen(intId)
+		hit := SearchHit{Id: id, IntId: intId}
+		for term, qtf := range terms {
+			tf := scorer.termFreq(term, intId)
+			// 查询中重复出现的词按次数加权
+			hit.Score += float64(qtf) * bm25.Score(tf, docLen, dfs[term], docCount, avgDocLen)
+		}
+		if topK <= 0 || h.Len() < topK {
+			pushed := hit
+			heap.Push(h, &pushed)
+		} else if h.less(h.hits[0], &hit) {
+			// 比堆中最差的文档好，替换掉堆顶
+			*h.hits[0] = hit
+			heap.Fix(h, 0)
+		}
+	})
+
+	res := h.hits
+	sort.Slice(res, func(i, j int) bool {
+		return h.less(res[j], res[i])
+	})
+	return res
+}
+
+// 最小堆，堆顶是得分最低的文档
+type hitHeap struct {
+	hits []*SearchHit
+}
+
+// a比b差：得分更低，得分相同时业务id更大
+// 各节点的IntId互不相关，用业务id保证集群上的排序一致，分页游标依赖这个顺序
+func (h *hitHeap) less(a, b *SearchHit) bool {
+	if a.Score != b.Score {
+		return a.Score < b.Score
+	}
+	return a.Id > b.Id
+}
+
+func (h *hitHeap) Len() int           { return len(h.hits) }
+func (h *hitHeap) Less(i, j int) bool { return h.less(h.hits[i], h.hits[j]) }
+func (h *hitHeap) Swap(i, j int)      { h.hits[i], h.hits[j] = h.hits[j], h.hits[i] }
+func (h *hitHeap) Push(x any)         { h.hits = append(h.hits, x.(*SearchHit)) }
+func (h *hitHeap) Pop() any {
+	n := len(h.hits)
+	x := h.hits[n-1]
+	h.hits = h.hits[:n-1]
+	return x
+}
@@ -0,0 +1,127 @@
+package reverse_index
+
+import (
+	"Research/types/doc"
+	"Research/types/term_query"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBM25Score(t *testing.T) {
+	bm25 := NewBM25()
+	base := bm25.Score(2, 10, 5, 100, 10)
+	cases := []struct {
+		name   string
+		score  float64
+		higher bool
+	}{
+		{"more occurrences", bm25.Score(4, 10, 5, 100, 10), true},
+		{"shorter doc", bm25.Score(2, 5, 5, 100, 10), true},
+		{"rarer term", bm25.Score(2, 10, 1, 100, 10), true},
+		{"longer doc", bm25.Score(2, 20, 5, 100, 10), false},
+		{"common term", bm25.Score(2, 10, 50, 100, 10), false},
+	}
+	for _, c := range cases {
+		if (c.score > base) != c.higher {
+			t.Errorf("%s: got %v, base %v", c.name, c.score, base)
+		}
+	}
+	if bm25.Score(0, 10, 5, 100, 10) != 0 || bm25.Score(2, 10, 0, 100, 10) != 0 {
+		t.Error("score without tf or df should be 0")
+	}
+}
+
+// 每个文档是空格分隔的词，同一个词重复出现就是词频
+func addTextDocs(ri IReverseIndex, texts map[string]string) {
+	intId := uint64(0)
+	ids := make([]string, 0, len(texts))
+	for id := range texts {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	for _, id := range ids {
+		intId++
+		keywords := make([]*doc.KeyWord, 0)
+		for _, word := range strings.Fields(texts[id]) {
+			keywords = append(keywords, &doc.KeyWord{Field: "text", Word: word})
+		}
+		ri.Add(doc.Document{Id: id, IntId: intId, Keywords: keywords})
+	}
+}
+
+func TestRankOrder(t *testing.T) {
+	cases := []struct {
+		name  string
+		query *term_query.TermQuery
+		topK  int
+		ids   []string
+	}{
+		// 词频高的在前，词频相同时短文档在前
+		{"tf and length", term_query.NewTermQuery("text", "go"), 0, []string{"a", "c", "d", "b", "e"}},
+		{"top k", term_query.NewTermQuery("text", "go"), 2, []string{"a", "c"}},
+		// 稀有词的idf更高
+		{"idf", term_query.NewTermQuery("text", "go").Or(term_query.NewTermQuery("text", "db")), 0, []string{"d", "a", "c", "b", "e"}},
+		// 得分相同时按业务id升序
+		{"tie", term_query.NewTermQuery("text", "rust"), 0, []string{"b", "e"}},
+		{"tie top k", term_query.NewTermQuery("text", "rust"), 1, []string{"b"}},
+	}
+	for name, indexType := range map[string]int{"skiplist": SKIPLIST, "roaring": ROARING} {
+		t.Run(name, func(t *testing.T) {
+			ri := NewReverseIndex(indexType, 100)
+			addTextDocs(ri, map[string]string{
+				"a": "go go go",
+				"b": "go rust rust rust",
+				"c": "go",
+				"d": "db go",
+				"e": "rust rust rust go",
+			})
+			for _, c := range cases {
+				hits, _ := ri.Search(c.query, nil, nil, nil, c.topK, nil)
+				ids := make([]string, 0, len(hits))
+				for i, hit := range hits {
+					ids = append(ids, hit.Id)
+					if i > 0 && hit.Score > hits[i-1].Score {
+						t.Errorf("%s: scores not descending", c.name)
+					}
+				}
+				if !slices.Equal(ids, c.ids) {
+					t.Errorf("%s: got %v, want %v", c.name, ids, c.ids)
+				}
+			}
+		})
+	}
+}
+
+// 删除文档后长度统计中不留下它，没有关键词的文档不计入文档数
+func TestDocStatsCleanup(t *testing.T) {
+	for name, indexType := range map[string]int{"skiplist": SKIPLIST, "roaring": ROARING} {
+		t.Run(name, func(t *testing.T) {
+			ri := NewReverseIndex(indexType, 100)
+			var stats *docStats
+			switch m := ri.(type) {
+			case *SkipListReverseIndex:
+				stats = m.stats
+			case *RoaringReverseIndex:
+				stats = m.stats
+			}
+			ri.Add(doc.Document{Id: "a", IntId: 1, Keywords: []*doc.KeyWord{{Field: "text", Word: "go"}, {Field: "text", Word: "db"}}})
+			ri.Add(doc.Document{Id: "b", IntId: 2, Numerics: []*doc.NumericField{{Field: "price", Value: 1}}})
+			ri.Add(doc.Document{Id: "c", IntId: 3})
+			if n, avg := stats.collection(); n != 1 || avg != 2 {
+				t.Errorf("after add: got %d docs, avg %v", n, avg)
+			}
+			// 更新成没有关键词
+			ri.Update(&doc.Document{Id: "a", IntId: 1, Keywords: []*doc.KeyWord{{Field: "text", Word: "go"}, {Field: "text", Word: "db"}}},
+				&doc.Document{Id: "a", IntId: 1, Numerics: []*doc.NumericField{{Field: "price", Value: 2}}})
+			if n, _ := stats.collection(); n != 0 {
+				t.Errorf("after update: got %d docs", n)
+			}
+			ri.Add(doc.Document{Id: "d", IntId: 4, Keywords: []*doc.KeyWord{{Field: "text", Word: "go"}}})
+			ri.Delete(4, &doc.KeyWord{Field: "text", Word: "go"})
+			if n, _ := stats.collection(); n != 0 || stats.totalLen != 0 {
+				t.Errorf("after delete: got %d docs, total length %d", n, stats.totalLen)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package reverse_index
+
+import (
+	"Research/types/doc"
+	"Research/types/term_query"
+	"github.com/huandu/skiplist"
+	"sync"
+)
+
+// 数值索引，每个field的数值按大小保存在跳表中，用于范围查询
+// 跳表的key是数值，value是该数值对应的文档IntId集合，集合的value由倒排索引自己决定
+type numericIndex struct {
+	lock   sync.RWMutex
+	fields map[string]*skiplist.SkipList // field -> 数值
+}
+
+func newNumericIndex() *numericIndex {
+	return &numericIndex{fields: make(map[string]*skiplist.SkipList)}
+}
+
+// 添加文档的数值字段，返回是否是新加入的
+func (n *numericIndex) add(intId uint64, numeric *doc.NumericField, value any) bool {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	values, exist := n.fields[numeric.Field]
+	if !exist {
+		values = skiplist.New(skiplist.Float64)
+		n.fields[numeric.Field] = values
+	}
+	var docs map[uint64]any
+	if elem := values.Get(numeric.Value); elem != nil {
+		docs = elem.Value.(map[uint64]any)
+	} else {
+		docs = make(map[uint64]any)
+		values.Set(numeric.Value, docs)
+	}
+	_, exist = docs[intId]
+	docs[intId] = value
+	return !exist
+}
+
+// 删除文档的数值字段，返回是否删除了
+func (n *numericIndex) remove(intId uint64, numeric *doc.NumericField) bool {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	values, exist := n.fields[numeric.Field]
+	if !exist {
+		return false
+	}
+	elem := values.Get(numeric.Value)
+	if elem == nil {
+		return false
+	}
+	docs := elem.Value.(map[uint64]any)
+	if _, exist = docs[intId]; !exist {
+		return false
+	}
+	delete(docs, intId)
+	if len(docs) == 0 {
+		values.RemoveElement(elem)
+	}
+	return true
+}
+
+// 遍历数值在范围内的文档
+func (n *numericIndex) search(r *term_query.Range, fn func(intId uint64, value any)) {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+	values, exist := n.fields[r.Field]
+	if !exist {
+		return
+	}
+	var elem *skiplist.Element
+	if r.From != nil {
+		elem = values.Find(*r.From)
+	} else {
+		elem = values.Front()
+	}
+	for ; elem != nil; elem = elem.Next() {
+		value := elem.Key().(float64)
+		if r.To != nil && value > *r.To {
+			break
+		}
+		if !r.Contains(value) {
+			continue
+		}
+		for intId, v := range elem.Value.(map[uint64]any) {
+			fn(intId, v)
+		}
+	}
+}
+
+// 按数值从小到大遍历field的所有数值及其文档
+func (n *numericIndex) each(field string, fn func(value float64, docs map[uint64]any)) {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+	values, exist := n.fields[field]
+	if !exist {
+		return
+	}
+	for elem := values.Front(); elem != nil; elem = elem.Next() {
+		fn(elem.Key().(float64), elem.Value.(map[uint64]any))
+	}
+}
+
+// 按数值从大到小遍历field的数值及其文档，fn返回false时停止
+func (n *numericIndex) eachDesc(field string, fn func(value float64, docs map[uint64]any) bool) {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+	values, exist := n.fields[field]
+	if !exist {
+		return
+	}
+	for elem := values.Back(); elem != nil; elem = elem.Prev() {
+		if !fn(elem.Key().(float64), elem.Value.(map[uint64]any)) {
+			return
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package reverse_index
+
+import (
+	"Research/types/doc"
+	"sort"
+)
+
+// 统计每个关键词在文档中出现的位置，结果升序去重
+// 关键词带有Positions时使用给定的位置，否则按关键词在同一个field中出现的顺序编号
+func termPositions(keywords []*doc.KeyWord) map[string][]uint32 {
+	res := make(map[string][]uint32, len(keywords))
+	ordinals := make(map[string]uint32) // 每个field已经出现的关键词数量
+	for _, keyWord := range keywords {
+		key := keyWord.ToString()
+		if len(key) == 0 {
+			continue
+		}
+		if len(keyWord.Positions) > 0 {
+			res[key] = append(res[key], keyWord.Positions...)
+		} else {
+			res[key] = append(res[key], ordinals[keyWord.Field])
+		}
+		ordinals[keyWord.Field]++
+	}
+	for key, positions := range res {
+		sort.Slice(positions, func(i, j int) bool { return positions[i] < positions[j] })
+		n := 1
+		for i := 1; i < len(positions); i++ {
+			if positions[i] != positions[n-1] {
+				positions[n] = positions[i]
+				n++
+			}
+		}
+		res[key] = positions[:n]
+	}
+	return res
+}
+
+// 批量添加时一个文档在某个关键词上的倒排
+type batchPosting struct {
+	doc       int // 文档在这一批中的下标
+	positions []uint32
+}
+
+// 把一批文档的倒排按关键词归并，同时返回每个文档的长度
+func groupPostings(docs []*doc.Document) (map[string][]batchPosting, []int) {
+	postings := make(map[string][]batchPosting)
+	docLens := make([]int, len(docs))
+	for i, d := range docs {
+		for key, positions := range termPositions(d.Keywords) {
+			docLens[i] += len(positions)
+			postings[key] = append(postings[key], batchPosting{doc: i, positions: positions})
+		}
+	}
+	return postings, docLens
+}
+
+// 判断各个词的位置能否按顺序组成短语，positions[i]是短语中第i个词在文档中的位置(升序)
+// slop是词之间最多插入的词数之和，为0时词必须相邻
+func matchPhrase(positions [][]uint32, slop int) bool {
+	if len(positions) == 0 {
+		return false
+	}
+	for _, start := range positions[0] {
+		prev, matched := start, true
+		for i := 1; i < len(positions); i++ {
+			// 每个词取前一个词之后最近的位置，这样后面的词离开头最近
+			j := sort.Search(len(positions[i]), func(k int) bool { return positions[i][k] > prev })
+			if j == len(positions[i]) {
+				// 更靠后的开头也找不到这个词
+				return false
+			}
+			prev = positions[i][j]
+			if int(prev-start)-i > slop {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
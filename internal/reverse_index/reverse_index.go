@@ -2,12 +2,34 @@ package reverse_index
 
 import (
 	"Research/types/doc"
+	"Research/types/index"
 	"Research/types/term_query"
 	"Research/util"
 )
 
+// 倒排索引使用的数据结构
+const (
+	SKIPLIST = iota + 1 // 跳表
+	ROARING             // roaring bitmap
+)
+
 type IReverseIndex interface {
-	Add(doc doc.Document)                                                                                       //添加一个doc
-	Delete(IntId uint64, keyword *doc.KeyWord)                                                                  //从key上删除对应的doc
-	Search(q *term_query.TermQuery, onFlag *util.Bitmap, offFlag *util.Bitmap, orFlags []*util.Bitmap) []string //查找,返回业务侧文档ID
+	Add(doc doc.Document)                                  //添加一个doc
+	BatchAdd(docs []*doc.Document)                         //批量添加doc，每个key只加一次锁
+	Update(old, new *doc.Document)                         //更新doc，IntId不变，只修改有变化的倒排链
+	Delete(IntId uint64, keyword *doc.KeyWord)             //从key上删除对应的doc
+	DeleteNumeric(IntId uint64, numeric *doc.NumericField) //从数值索引上删除对应的doc
+	//查找,返回按BM25得分降序的文档，topK大于0时只返回前topK个，同时返回所有匹配文档上的聚合结果
+	Search(q *term_query.TermQuery, onFlag *util.Bitmap, offFlag *util.Bitmap, orFlags []*util.Bitmap, topK int, aggs []*index.Aggregation) ([]*SearchHit, []*index.AggregationResult)
+	Suggest(request *index.SuggestRequest) []*index.Suggestion //用词典中的词补全前缀
+}
+
+// 工厂方法，根据索引类型创建倒排索引，默认使用跳表
+func NewReverseIndex(indexType int, DocNumEstimate int) IReverseIndex {
+	switch indexType {
+	case ROARING:
+		return NewRoaringReverseIndex(DocNumEstimate)
+	default:
+		return NewSkipListReverseIndex(DocNumEstimate)
+	}
 }
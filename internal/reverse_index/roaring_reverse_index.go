@@ -0,0 +1,507 @@
+package reverse_index
+
+import (
+	"Research/types/doc"
+	"Research/types/index"
+	"Research/types/term_query"
+	"Research/util"
+	"github.com/RoaringBitmap/roaring/roaring64"
+	farmhash "github.com/leemcloughlin/gofarmhash"
+	"runtime"
+	"slices"
+	"sync"
+)
+
+// 倒排索引整体上是个map，map的value是压缩后的IntId集合(roaring bitmap)
+// 文档的业务id和特征不随倒排链保存，统一放在docs中，每个文档只存一份
+type RoaringReverseIndex struct {
+	table    *util.ResearchMap //分段map，并发安全
+	locks    []sync.RWMutex    //修改倒排索引时，相同的key需要去竞争同一把锁
+	docs     map[uint64]*RoaringValue
+	docsLock sync.RWMutex
+	stats    *docStats //文档长度统计
+	bm25     *BM25
+	dict     *termDict     //词典
+	numerics *numericIndex //数值索引
+}
+
+// 倒排链
+type roaringPosting struct {
+	bitmap    *roaring64.Bitmap
+	positions map[uint64][]uint32 // 关键词在每个文档中出现的位置，位置数量就是词频
+}
+
+type RoaringValue struct {
+	Id          string
+	BitsFeature *util.Bitmap
+	refs        int // 文档在几条倒排链上，为0时从docs中删除
+}
+
+// DocNumEstimate是预估的doc数量
+func NewRoaringReverseIndex(DocNumEstimate int) *RoaringReverseIndex {
+	indexer := new(RoaringReverseIndex)
+	indexer.table = util.NewResearchMap(runtime.NumCPU(), DocNumEstimate) // 分片数量为cpu数量
+	indexer.locks = make([]sync.RWMutex, 1000)
+	indexer.docs = make(map[uint64]*RoaringValue, DocNumEstimate)
+	indexer.stats = newDocStats(DocNumEstimate)
+	indexer.bm25 = NewBM25()
+	indexer.dict = newTermDict()
+	indexer.numerics = newNumericIndex()
+	return indexer
+}
+
+// 根据key获取对应锁
+func (m *RoaringReverseIndex) getLock(key string) *sync.RWMutex {
+	n := int(farmhash.Hash32WithSeed([]byte(key), 0))
+	return &m.locks[n%len(m.locks)]
+}
+
+// 添加文档
+func (m *RoaringReverseIndex) Add(doc doc.Document) {
+	added, docLen := 0, 0
+	for key, positions := range termPositions(doc.Keywords) {
+		docLen += len(positions)
+		//对可能相同的key加锁
+		lock := m.getLock(key)
+		lock.Lock()
+
+		var posting *roaringPosting
+		if val, exist := m.table.Get(key); !exist {
+			//不存在，加入key，创建倒排链
+			posting = &roaringPosting{bitmap: roaring64.New(), positions: make(map[uint64][]uint32)}
+			m.table.Set(key, posting)
+		} else {
+			posting = val.(*roaringPosting)
+		}
+		if posting.bitmap.IsEmpty() {
+			m.dict.add(key)
+		}
+		if posting.bitmap.CheckedAdd(doc.IntId) {
+			added++
+		}
+		posting.positions[doc.IntId] = positions
+		lock.Unlock()
+	}
+	for _, numeric := range doc.Numerics {
+		if m.numerics.add(doc.IntId, numeric, nil) {
+			added++
+		}
+	}
+	if added == 0 {
+		return
+	}
+	m.stats.add(doc.IntId, docLen)
+
+	m.docsLock.Lock()
+	defer m.docsLock.Unlock()
+	if value, exist := m.docs[doc.IntId]; exist {
+		value.refs += added
+	} else {
+		m.docs[doc.IntId] = &RoaringValue{Id: doc.Id, BitsFeature: doc.BitsFeature, refs: added}
+	}
+}
+
+// 批量添加文档，所有文档的倒排按key归并后，每个key只加一次锁
+func (m *RoaringReverseIndex) BatchAdd(docs []*doc.Document) {
+	postings, docLens := groupPostings(docs)
+	added := make([]int, len(docs)) // 每个文档新加入的倒排数量
+	for key, list := range postings {
+		lock := m.getLock(key)
+		lock.Lock()
+
+		var posting *roaringPosting
+		if val, exist := m.table.Get(key); !exist {
+			posting = &roaringPosting{bitmap: roaring64.New(), positions: make(map[uint64][]uint32)}
+			m.table.Set(key, posting)
+		} else {
+			posting = val.(*roaringPosting)
+		}
+		if posting.bitmap.IsEmpty() {
+			m.dict.add(key)
+		}
+		for _, p := range list {
+			intId := docs[p.doc].IntId
+			if posting.bitmap.CheckedAdd(intId) {
+				added[p.doc]++
+			}
+			posting.positions[intId] = p.positions
+		}
+		lock.Unlock()
+	}
+	for i, d := range docs {
+		for _, numeric := range d.Numerics {
+			if m.numerics.add(d.IntId, numeric, nil) {
+				added[i]++
+			}
+		}
+		if added[i] > 0 {
+			m.stats.add(d.IntId, docLens[i])
+		}
+	}
+
+	m.docsLock.Lock()
+	defer m.docsLock.Unlock()
+	for i, d := range docs {
+		if added[i] == 0 {
+			continue
+		}
+		if value, exist := m.docs[d.IntId]; exist {
+			value.refs += added[i]
+		} else {
+			m.docs[d.IntId] = &RoaringValue{Id: d.Id, BitsFeature: d.BitsFeature, refs: added[i]}
+		}
+	}
+}
+
+// 删除doc
+func (m *RoaringReverseIndex) Delete(intId uint64, keyWord *doc.KeyWord) {
+	m.stats.remove(intId)
+	if m.removePosting(keyWord.ToString(), intId) {
+		m.release(intId)
+	}
+}
+
+// 从key的倒排链上删除doc，返回是否删除了
+func (m *RoaringReverseIndex) removePosting(key string, intId uint64) bool {
+	lock := m.getLock(key)
+	lock.Lock()
+	defer lock.Unlock()
+	removed := false
+	if val, exist := m.table.Get(key); exist {
+		posting := val.(*roaringPosting)
+		removed = posting.bitmap.CheckedRemove(intId)
+		delete(posting.positions, intId)
+		if removed && posting.bitmap.IsEmpty() {
+			//倒排链为空，从词典中删除
+			m.dict.remove(key)
+		}
+	}
+	return removed
+}
+
+// 更新doc，只修改关键词位置有变化的倒排链，特征只保存在文档上，直接替换
+func (m *RoaringReverseIndex) Update(old, new *doc.Document) {
+	oldPositions := termPositions(old.Keywords)
+	refs, docLen := 0, 0 // 引用计数的变化量
+	for key, positions := range termPositions(new.Keywords) {
+		docLen += len(positions)
+		oldPosition, exist := oldPositions[key]
+		delete(oldPositions, key)
+		if exist && slices.Equal(oldPosition, positions) {
+			continue
+		}
+		lock := m.getLock(key)
+		lock.Lock()
+		var posting *roaringPosting
+		if val, exist := m.table.Get(key); !exist {
+			posting = &roaringPosting{bitmap: roaring64.New(), positions: make(map[uint64][]uint32)}
+			m.table.Set(key, posting)
+		} else {
+			posting = val.(*roaringPosting)
+		}
+		if posting.bitmap.IsEmpty() {
+			m.dict.add(key)
+		}
+		if posting.bitmap.CheckedAdd(new.IntId) {
+			refs++
+		}
+		posting.positions[new.IntId] = positions
+		lock.Unlock()
+	}
+	// 新文档中已经没有的关键词
+	for key := range oldPositions {
+		if m.removePosting(key, new.IntId) {
+			refs--
+		}
+	}
+
+	removed, added, _ := diffNumerics(old.Numerics, new.Numerics)
+	for _, numeric := range removed {
+		if m.numerics.remove(new.IntId, numeric) {
+			refs--
+		}
+	}
+	for _, numeric := range added {
+		if m.numerics.add(new.IntId, numeric, nil) {
+			refs++
+		}
+	}
+
+	m.docsLock.Lock()
+	defer m.docsLock.Unlock()
+	value, exist := m.docs[new.IntId]
+	if !exist {
+		value = &RoaringValue{Id: new.Id}
+		m.docs[new.IntId] = value
+	}
+	value.BitsFeature = new.BitsFeature
+	value.refs += refs
+	if value.refs <= 0 {
+		delete(m.docs, new.IntId)
+		m.stats.remove(new.IntId)
+	} else {
+		m.stats.add(new.IntId, docLen)
+	}
+}
+
+// 从数值索引上删除doc
+func (m *RoaringReverseIndex) DeleteNumeric(intId uint64, numeric *doc.NumericField) {
+	if m.numerics.remove(intId, numeric) {
+		m.release(intId)
+	}
+}
+
+// 文档从一条倒排链或数值索引上删除后，减少引用计数
+func (m *RoaringReverseIndex) release(intId uint64) {
+	m.stats.remove(intId)
+
+	m.docsLock.Lock()
+	defer m.docsLock.Unlock()
+	if value, exist := m.docs[intId]; exist {
+		value.refs--
+		if value.refs <= 0 {
+			delete(m.docs, intId)
+		}
+	}
+}
+
+// 根据查询表达式查找结果,返回按得分降序的文档
+func (m *RoaringReverseIndex) Search(q *term_query.TermQuery, onFlag *util.Bitmap, offFlag *util.Bitmap, orFlags []*util.Bitmap, topK int, aggs []*index.Aggregation) ([]*SearchHit, []*index.AggregationResult) {
+	//获取查询结果
+	search := m.search(q, onFlag, offFlag, orFlags)
+	if search == nil || search.IsEmpty() {
+		return nil, aggregate(aggs, nil)
+	}
+	each := func(fn func(id string, intId uint64)) {
+		for it := search.Iterator(); it.HasNext(); {
+			intId := it.Next()
+			m.docsLock.RLock()
+			value, exist := m.docs[intId]
+			m.docsLock.RUnlock()
+			if exist {
+				fn(value.Id, intId)
+			}
+		}
+	}
+	return rank(each, q, m, m.stats, m.bm25, topK), aggregate(aggs, m.aggSource(search))
+}
+
+// 在匹配的文档集合上聚合
+func (m *RoaringReverseIndex) aggSource(matched *roaring64.Bitmap) *aggSource {
+	return &aggSource{
+		dict:     m.dict,
+		numerics: m.numerics,
+		termCount: func(key string) int {
+			value, exist := m.table.Get(key)
+			if !exist {
+				return 0
+			}
+			lock := m.getLock(key)
+			lock.RLock()
+			defer lock.RUnlock()
+			return int(value.(*roaringPosting).bitmap.AndCardinality(matched))
+		},
+		contains: matched.Contains,
+	}
+}
+
+// 输入提示
+func (m *RoaringReverseIndex) Suggest(request *index.SuggestRequest) []*index.Suggestion {
+	return suggest(request, &suggestSource{
+		dict:     m.dict,
+		numerics: m.numerics,
+		docFreq:  m.docFreq,
+		contains: func(key string, intId uint64) bool {
+			value, exist := m.table.Get(key)
+			if !exist {
+				return false
+			}
+			lock := m.getLock(key)
+			lock.RLock()
+			defer lock.RUnlock()
+			return value.(*roaringPosting).bitmap.Contains(intId)
+		},
+	})
+}
+
+// 包含关键词的文档数
+func (m *RoaringReverseIndex) docFreq(key string) int {
+	value, exist := m.table.Get(key)
+	if !exist {
+		return 0
+	}
+	lock := m.getLock(key)
+	lock.RLock()
+	defer lock.RUnlock()
+	return int(value.(*roaringPosting).bitmap.GetCardinality())
+}
+
+// 展开前缀、通配符、模糊查询
+func (m *RoaringReverseIndex) expand(t *term_query.MultiTerm) []string {
+	return m.dict.expand(t)
+}
+
+// 关键词在文档中出现的次数
+func (m *RoaringReverseIndex) termFreq(key string, intId uint64) int {
+	return len(m.positionsOf(key, intId))
+}
+
+// 关键词在文档中出现的位置
+func (m *RoaringReverseIndex) positionsOf(key string, intId uint64) []uint32 {
+	value, exist := m.table.Get(key)
+	if !exist {
+		return nil
+	}
+	lock := m.getLock(key)
+	lock.RLock()
+	defer lock.RUnlock()
+	return value.(*roaringPosting).positions[intId]
+}
+
+// 根据查询表达式查找结果，保存在bitmap中
+func (m *RoaringReverseIndex) search(q *term_query.TermQuery, onFlag *util.Bitmap, offFlag *util.Bitmap, orFlags []*util.Bitmap) *roaring64.Bitmap {
+	if q == nil {
+		return nil
+	}
+	res := m.searchPositive(q, onFlag, offFlag, orFlags)
+	if res == nil || len(q.MustNot) == 0 {
+		return res
+	}
+	//4、mustNot关系，去掉满足任意一个条件的文档
+	for _, val := range q.MustNot {
+		if bitmap := m.search(val, nil, nil, nil); bitmap != nil {
+			res.AndNot(bitmap)
+		}
+	}
+	return res
+}
+
+// 查找Keyword、Must、Should的结果，返回的bitmap可以直接修改
+func (m *RoaringReverseIndex) searchPositive(q *term_query.TermQuery, onFlag *util.Bitmap, offFlag *util.Bitmap, orFlags []*util.Bitmap) *roaring64.Bitmap {
+	//根据查询表达式分三种情况
+	//1、存在关键字
+	if q.Keyword != nil {
+		return m.searchKey(q.Keyword.ToString(), onFlag, offFlag, orFlags)
+	}
+
+	//1.1、短语
+	if q.Phrase != nil {
+		return m.searchPhrase(q.Phrase, onFlag, offFlag, orFlags)
+	}
+
+	//1.2、前缀、通配符、模糊查询，展开成多个关键词求并集
+	if q.MultiTerm != nil {
+		keys := m.dict.expand(q.MultiTerm)
+		res := make([]*roaring64.Bitmap, 0, len(keys))
+		for _, key := range keys {
+			if bitmap := m.searchKey(key, onFlag, offFlag, orFlags); bitmap != nil {
+				res = append(res, bitmap)
+			}
+		}
+		if len(res) == 0 {
+			return nil
+		}
+		return roaring64.FastOr(res...)
+	}
+
+	//1.3、数值范围
+	if q.Range != nil {
+		res := roaring64.New()
+		m.docsLock.RLock()
+		m.numerics.search(q.Range, func(intId uint64, _ any) {
+			if value, exist := m.docs[intId]; exist && filter(value.BitsFeature, onFlag, offFlag, orFlags) {
+				res.Add(intId)
+			}
+		})
+		m.docsLock.RUnlock()
+		return res
+	}
+
+	//2、must关系
+	if len(q.Must) > 0 {
+		res := make([]*roaring64.Bitmap, 0, len(q.Must))
+		for _, val := range q.Must {
+			bitmap := m.search(val, onFlag, offFlag, orFlags)
+			if bitmap == nil {
+				//有一个条件不满足，交集为空
+				return nil
+			}
+			res = append(res, bitmap)
+		}
+		//must求交集
+		return roaring64.FastAnd(res...)
+	}
+
+	//3、should关系
+	if len(q.Should) > 0 {
+		res := make([]*roaring64.Bitmap, 0, len(q.Should))
+		for _, val := range q.Should {
+			if bitmap := m.search(val, onFlag, offFlag, orFlags); bitmap != nil {
+				res = append(res, bitmap)
+			}
+		}
+		//should求并集
+		return roaring64.FastOr(res...)
+	}
+	return nil
+}
+
+// 查找关键词的倒排链，去掉不满足特征要求的文档
+func (m *RoaringReverseIndex) searchKey(key string, onFlag *util.Bitmap, offFlag *util.Bitmap, orFlags []*util.Bitmap) *roaring64.Bitmap {
+	//得到关键字的倒排链
+	value, exist := m.table.Get(key)
+	if !exist {
+		return nil
+	}
+	lock := m.getLock(key)
+	lock.RLock()
+	posting := value.(*roaringPosting).bitmap.Clone()
+	lock.RUnlock()
+
+	//docs上保存了文档特征，去掉不满足要求的文档
+	res := roaring64.New()
+	m.docsLock.RLock()
+	it := posting.Iterator()
+	for it.HasNext() {
+		intId := it.Next()
+		if value, exist := m.docs[intId]; exist && filter(value.BitsFeature, onFlag, offFlag, orFlags) {
+			res.Add(intId)
+		}
+	}
+	m.docsLock.RUnlock()
+	return res
+}
+
+// 查找短语，先对每个词的倒排链求交集，再根据位置判断是否组成短语
+func (m *RoaringReverseIndex) searchPhrase(phrase *term_query.Phrase, onFlag *util.Bitmap, offFlag *util.Bitmap, orFlags []*util.Bitmap) *roaring64.Bitmap {
+	keys := phrase.Keys()
+	if len(keys) == 0 {
+		return nil
+	}
+	bitmaps := make([]*roaring64.Bitmap, 0, len(keys))
+	for _, key := range keys {
+		bitmap := m.searchKey(key, onFlag, offFlag, orFlags)
+		if bitmap == nil {
+			return nil
+		}
+		bitmaps = append(bitmaps, bitmap)
+	}
+	candidates := roaring64.FastAnd(bitmaps...)
+	if len(bitmaps) == 1 {
+		return candidates
+	}
+
+	res := roaring64.New()
+	positions := make([][]uint32, len(keys))
+	it := candidates.Iterator()
+	for it.HasNext() {
+		intId := it.Next()
+		for i, key := range keys {
+			positions[i] = m.positionsOf(key, intId)
+		}
+		if matchPhrase(positions, int(phrase.Slop)) {
+			res.Add(intId)
+		}
+	}
+	return res
+}
@@ -0,0 +1,114 @@
+package reverse_index
+
+import (
+	"Research/types/doc"
+	"Research/types/term_query"
+	"Research/util"
+	"slices"
+	"testing"
+)
+
+func bits(indexes ...int) *util.Bitmap {
+	bitmap := util.NewBitmap(64)
+	for _, i := range indexes {
+		bitmap.SetBit(i)
+	}
+	return bitmap
+}
+
+func hitIds(hits []*SearchHit) []string {
+	ids := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.Id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// 同样的写入和查询，roaring和跳表的结果一致
+func TestRoaringMatchesSkipList(t *testing.T) {
+	build := func(ri IReverseIndex) {
+		ri.Add(doc.Document{Id: "a", IntId: 1, BitsFeature: bits(1, 2), Keywords: []*doc.KeyWord{{Field: "w", Word: "go"}, {Field: "w", Word: "db"}}})
+		ri.BatchAdd([]*doc.Document{
+			{Id: "b", IntId: 2, BitsFeature: bits(1), Keywords: []*doc.KeyWord{{Field: "w", Word: "go"}}},
+			{Id: "c", IntId: 3, Keywords: []*doc.KeyWord{{Field: "w", Word: "db"}}, Numerics: []*doc.NumericField{{Field: "price", Value: 5}}},
+			{Id: "d", IntId: 4, BitsFeature: bits(3), Keywords: []*doc.KeyWord{{Field: "w", Word: "go"}, {Field: "w", Word: "kv"}}},
+		})
+		// b的特征变化，d不再有go
+		ri.Update(&doc.Document{Id: "b", IntId: 2, BitsFeature: bits(1), Keywords: []*doc.KeyWord{{Field: "w", Word: "go"}}},
+			&doc.Document{Id: "b", IntId: 2, BitsFeature: bits(2), Keywords: []*doc.KeyWord{{Field: "w", Word: "go"}}})
+		ri.Update(&doc.Document{Id: "d", IntId: 4, BitsFeature: bits(3), Keywords: []*doc.KeyWord{{Field: "w", Word: "go"}, {Field: "w", Word: "kv"}}},
+			&doc.Document{Id: "d", IntId: 4, BitsFeature: bits(3), Keywords: []*doc.KeyWord{{Field: "w", Word: "kv"}}})
+		ri.Delete(1, &doc.KeyWord{Field: "w", Word: "db"})
+	}
+	cases := []struct {
+		name    string
+		query   *term_query.TermQuery
+		onFlag  *util.Bitmap
+		offFlag *util.Bitmap
+		orFlags []*util.Bitmap
+		ids     []string
+	}{
+		{"keyword", term_query.NewTermQuery("w", "go"), nil, nil, nil, []string{"a", "b"}},
+		{"deleted posting", term_query.NewTermQuery("w", "db"), nil, nil, nil, []string{"c"}},
+		{"must", term_query.NewTermQuery("w", "go").And(term_query.NewTermQuery("w", "db")), nil, nil, nil, []string{}},
+		{"should", term_query.NewTermQuery("w", "go").Or(term_query.NewTermQuery("w", "kv")), nil, nil, nil, []string{"a", "b", "d"}},
+		{"on flag", term_query.NewTermQuery("w", "go"), bits(1), nil, nil, []string{"a"}},
+		{"off flag", term_query.NewTermQuery("w", "go"), nil, bits(1), nil, []string{"b"}},
+		{"or flags", term_query.NewTermQuery("w", "go").Or(term_query.NewTermQuery("w", "kv")), nil, nil, []*util.Bitmap{bits(1, 3)}, []string{"a", "d"}},
+		// 没有特征的文档不满足任何特征条件
+		{"no features", term_query.NewTermQuery("w", "db"), bits(1), nil, nil, []string{}},
+		{"missing", term_query.NewTermQuery("w", "none"), nil, nil, nil, []string{}},
+	}
+	results := make(map[string][][]string)
+	for name, indexType := range map[string]int{"skiplist": SKIPLIST, "roaring": ROARING} {
+		ri := NewReverseIndex(indexType, 100)
+		build(ri)
+		for _, c := range cases {
+			hits, _ := ri.Search(c.query, c.onFlag, c.offFlag, c.orFlags, 0, nil)
+			results[name] = append(results[name], hitIds(hits))
+		}
+	}
+	for i, c := range cases {
+		if got := results["roaring"][i]; !slices.Equal(got, c.ids) {
+			t.Errorf("%s: roaring got %v, want %v", c.name, got, c.ids)
+		}
+		if got := results["skiplist"][i]; !slices.Equal(got, c.ids) {
+			t.Errorf("%s: skiplist got %v, want %v", c.name, got, c.ids)
+		}
+	}
+}
+
+// 文档从所有倒排链和数值索引上删除后，特征表中也不再保存
+func TestRoaringReleasesDocs(t *testing.T) {
+	ri := NewReverseIndex(ROARING, 100)
+	m, ok := ri.(*RoaringReverseIndex)
+	if !ok {
+		t.Fatalf("got %T", ri)
+	}
+	ri.Add(doc.Document{Id: "a", IntId: 1, BitsFeature: bits(1), Keywords: []*doc.KeyWord{{Field: "w", Word: "go"}, {Field: "w", Word: "db"}}, Numerics: []*doc.NumericField{{Field: "price", Value: 5}}})
+	// 重复添加不增加引用
+	ri.Add(doc.Document{Id: "a", IntId: 1, BitsFeature: bits(1), Keywords: []*doc.KeyWord{{Field: "w", Word: "go"}}})
+	steps := []struct {
+		name   string
+		remove func()
+		exist  bool
+	}{
+		{"delete go", func() { ri.Delete(1, &doc.KeyWord{Field: "w", Word: "go"}) }, true},
+		{"delete go again", func() { ri.Delete(1, &doc.KeyWord{Field: "w", Word: "go"}) }, true},
+		{"delete db", func() { ri.Delete(1, &doc.KeyWord{Field: "w", Word: "db"}) }, true},
+		{"delete price", func() { ri.DeleteNumeric(1, &doc.NumericField{Field: "price", Value: 5}) }, false},
+	}
+	for _, step := range steps {
+		step.remove()
+		m.docsLock.RLock()
+		_, exist := m.docs[1]
+		m.docsLock.RUnlock()
+		if exist != step.exist {
+			t.Errorf("%s: doc exist %v, want %v", step.name, exist, step.exist)
+		}
+	}
+	if words := m.dict.words("w"); len(words) != 0 {
+		t.Errorf("dict still has %v", words)
+	}
+}
@@ -0,0 +1,148 @@
+package reverse_index
+
+import (
+	"Research/types/doc"
+	"Research/types/term_query"
+	"Research/util"
+	"slices"
+	"testing"
+	"time"
+)
+
+// 两种倒排索引各跑一遍查询，比较命中文档的业务id
+type searchCase struct {
+	name   string
+	query  *term_query.TermQuery
+	onFlag *util.Bitmap
+	ids    []string
+}
+
+func runSearchCases(t *testing.T, docs []*doc.Document, cases []searchCase) {
+	t.Helper()
+	for name, indexType := range map[string]int{"skiplist": SKIPLIST, "roaring": ROARING} {
+		t.Run(name, func(t *testing.T) {
+			ri := NewReverseIndex(indexType, 100)
+			ri.BatchAdd(docs)
+			for _, c := range cases {
+				hits, _ := ri.Search(c.query, c.onFlag, nil, nil, 0, nil)
+				if ids := hitIds(hits); !slices.Equal(ids, c.ids) {
+					t.Errorf("%s: got %v, want %v", c.name, ids, c.ids)
+				}
+			}
+		})
+	}
+}
+
+func wordDoc(id string, intId uint64, words ...string) *doc.Document {
+	d := &doc.Document{Id: id, IntId: intId}
+	for _, word := range words {
+		d.Keywords = append(d.Keywords, &doc.KeyWord{Field: "w", Word: word})
+	}
+	return d
+}
+
+func word(w string) *term_query.TermQuery {
+	return term_query.NewTermQuery("w", w)
+}
+
+func TestMustNot(t *testing.T) {
+	docs := []*doc.Document{wordDoc("a", 1, "go", "db"), wordDoc("b", 2, "go", "kv"), wordDoc("c", 3, "db"), wordDoc("d", 4, "go")}
+	docs[0].BitsFeature = bits(1)
+	docs[3].BitsFeature = bits(1)
+	runSearchCases(t, docs, []searchCase{
+		{"not", word("go").Not(word("db")), nil, []string{"b", "d"}},
+		{"not any", word("go").Not(word("db"), word("kv")), nil, []string{"d"}},
+		{"not should", word("go").Not(word("db").Or(word("kv"))), nil, []string{"d"}},
+		{"nested", word("go").Not(word("kv")).Or(word("db")), nil, []string{"a", "c", "d"}},
+		{"not missing", word("go").Not(word("none")), nil, []string{"a", "b", "d"}},
+		// 排除条件不受特征过滤，满足排除条件的文档都去掉
+		{"not with flags", word("go").Not(word("db")), bits(1), []string{"d"}},
+		// 只有排除条件时没有结果
+		{"only not", &term_query.TermQuery{MustNot: []*term_query.TermQuery{word("db")}}, nil, []string{}},
+	})
+}
+
+func TestMatchPhrase(t *testing.T) {
+	cases := []struct {
+		positions [][]uint32
+		slop      int
+		match     bool
+	}{
+		{[][]uint32{{0}, {1}}, 0, true},
+		{[][]uint32{{0}, {2}}, 0, false},
+		{[][]uint32{{0}, {2}}, 1, true},
+		// 顺序颠倒不匹配
+		{[][]uint32{{1}, {0}}, 5, false},
+		// 第一个开头太远，换后面的开头
+		{[][]uint32{{0, 5}, {7}}, 1, true},
+		// slop是所有间隔之和
+		{[][]uint32{{0}, {2}, {4}}, 1, false},
+		{[][]uint32{{0}, {2}, {4}}, 2, true},
+		{[][]uint32{{0}, {}}, 3, false},
+		{nil, 0, false},
+	}
+	for i, c := range cases {
+		if got := matchPhrase(c.positions, c.slop); got != c.match {
+			t.Errorf("case %d %v~%d: got %v", i, c.positions, c.slop, got)
+		}
+	}
+}
+
+func TestPhraseSlop(t *testing.T) {
+	docs := []*doc.Document{
+		wordDoc("a", 1, "quick", "brown", "fox", "jumps"),
+		wordDoc("b", 2, "quick", "red", "brown", "fox"),
+		wordDoc("c", 3, "fox", "brown", "quick"),
+		wordDoc("d", 4, "quick", "quick", "brown"),
+	}
+	phrase := func(slop int, words ...string) *term_query.TermQuery {
+		return term_query.NewPhraseQuery("w", slop, words...)
+	}
+	runSearchCases(t, docs, []searchCase{
+		{"adjacent", phrase(0, "quick", "brown"), nil, []string{"a", "d"}},
+		{"slop 1", phrase(1, "quick", "brown"), nil, []string{"a", "b", "d"}},
+		{"slop too small", phrase(1, "quick", "fox"), nil, []string{"a"}},
+		{"slop 2", phrase(2, "quick", "fox"), nil, []string{"a", "b"}},
+		// 词的顺序不能颠倒
+		{"order", phrase(5, "brown", "quick"), nil, []string{"c"}},
+		{"three words", phrase(0, "quick", "brown", "fox"), nil, []string{"a"}},
+		{"three words slop", phrase(1, "quick", "brown", "fox"), nil, []string{"a", "b"}},
+		{"missing word", phrase(3, "quick", "lazy"), nil, []string{}},
+		{"one word", phrase(0, "jumps"), nil, []string{"a"}},
+		{"must", phrase(0, "brown", "fox").And(word("jumps")), nil, []string{"a"}},
+	})
+}
+
+func TestRangeQuery(t *testing.T) {
+	day := func(month time.Month, d int) time.Time { return time.Date(2026, month, d, 0, 0, 0, 0, time.UTC) }
+	docs := []*doc.Document{wordDoc("a", 1, "go"), wordDoc("b", 2, "go"), wordDoc("c", 3, "db"), wordDoc("d", 4, "db")}
+	for i, price := range []float64{10, 20, 20, 30} {
+		docs[i].Numerics = append(docs[i].Numerics, doc.NewNumericField("price", price))
+	}
+	docs[0].Numerics = append(docs[0].Numerics, doc.NewDateField("at", day(1, 1)))
+	docs[1].Numerics = append(docs[1].Numerics, doc.NewDateField("at", day(2, 1)))
+	docs[2].Numerics = append(docs[2].Numerics, doc.NewDateField("at", day(3, 1)))
+	num := func(v float64) *float64 { return &v }
+	price := func(from, to *float64) *term_query.TermQuery { return term_query.NewRangeQuery("price", from, to) }
+	exclusive := func(q *term_query.TermQuery, from, to bool) *term_query.TermQuery {
+		q.Range.ExcludeFrom, q.Range.ExcludeTo = from, to
+		return q
+	}
+	runSearchCases(t, docs, []searchCase{
+		{"inclusive", price(num(10), num(20)), nil, []string{"a", "b", "c"}},
+		{"exclude from", exclusive(price(num(10), num(20)), true, false), nil, []string{"b", "c"}},
+		{"exclude to", exclusive(price(num(10), num(20)), false, true), nil, []string{"a"}},
+		{"exclude both", exclusive(price(num(10), num(30)), true, true), nil, []string{"b", "c"}},
+		{"single value", price(num(20), num(20)), nil, []string{"b", "c"}},
+		{"between values", price(num(11), num(19)), nil, []string{}},
+		{"open from", price(nil, num(20)), nil, []string{"a", "b", "c"}},
+		{"open to", price(num(20), nil), nil, []string{"b", "c", "d"}},
+		{"open both", price(nil, nil), nil, []string{"a", "b", "c", "d"}},
+		{"missing field", term_query.NewRangeQuery("weight", nil, nil), nil, []string{}},
+		{"must", price(num(20), nil).And(word("go")), nil, []string{"b"}},
+		{"should", price(nil, num(10)).Or(word("db")), nil, []string{"a", "c", "d"}},
+		{"not", word("go").Not(price(num(20), nil)), nil, []string{"a"}},
+		{"date", term_query.NewDateRangeQuery("at", day(1, 15), day(3, 1)), nil, []string{"b", "c"}},
+		{"date open", term_query.NewDateRangeQuery("at", time.Time{}, day(2, 1)), nil, []string{"a", "b"}},
+	})
+}
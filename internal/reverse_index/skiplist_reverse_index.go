@@ -2,23 +2,31 @@ package reverse_index
 
 import (
 	"Research/types/doc"
+	"Research/types/index"
 	"Research/types/term_query"
 	"Research/util"
 	"github.com/huandu/skiplist"
 	farmhash "github.com/leemcloughlin/gofarmhash"
 	"runtime"
+	"slices"
 	"sync"
 )
 
 // 倒排索引整体上是个map，map的value是一个List
 type SkipListReverseIndex struct {
-	table *util.ResearchMap //分段map，并发安全
-	locks []sync.RWMutex    //修改倒排索引时，相同的key需要去竞争同一把锁
+	table    *util.ResearchMap //分段map，并发安全
+	locks    []sync.RWMutex    //修改倒排索引时，相同的key需要去竞争同一把锁
+	stats    *docStats         //文档长度统计
+	bm25     *BM25
+	dict     *termDict     //词典
+	numerics *numericIndex //数值索引
 }
 
 type SkipListValue struct {
 	Id          string
 	BitsFeature *util.Bitmap
+	Tf          int      //关键词在文档中出现的次数
+	Positions   []uint32 //关键词在文档中出现的位置，用于短语查询
 }
 
 // DocNumEstimate是预估的doc数量
@@ -26,6 +34,10 @@ func NewSkipListReverseIndex(DocNumEstimate int) *SkipListReverseIndex {
 	indexer := new(SkipListReverseIndex)
 	indexer.table = util.NewResearchMap(runtime.NumCPU(), DocNumEstimate) // 分片数量为cpu数量
 	indexer.locks = make([]sync.RWMutex, 1000)
+	indexer.stats = newDocStats(DocNumEstimate)
+	indexer.bm25 = NewBM25()
+	indexer.dict = newTermDict()
+	indexer.numerics = newNumericIndex()
 	return indexer
 }
 
@@ -37,84 +49,288 @@ func (indexer SkipListReverseIndex) getLock(key string) *sync.RWMutex {
 
 // 添加文档
 func (m *SkipListReverseIndex) Add(doc doc.Document) {
-	for _, keyWord := range doc.Keywords {
-		key := keyWord.ToString()
+	docLen := 0
+	for key, positions := range termPositions(doc.Keywords) {
+		docLen += len(positions)
 		//对可能相同的key加锁
 		lock := m.getLock(key)
 		lock.Lock()
 
-		skipListValue := &SkipListValue{Id: doc.Id, BitsFeature: doc.BitsFeature}
+		skipListValue := &SkipListValue{Id: doc.Id, BitsFeature: doc.BitsFeature, Tf: len(positions), Positions: positions}
 		if val, exist := m.table.Get(key); !exist {
 			//不存在，加入key，创建跳表
 			skipList := skiplist.New(skiplist.Uint64)
 			skipList.Set(doc.IntId, skipListValue)
 			m.table.Set(key, skipList)
+			m.dict.add(key)
 		} else {
 			//存在，获得跳表加入doc
 			skipList := val.(*skiplist.SkipList)
+			if skipList.Len() == 0 {
+				m.dict.add(key)
+			}
 			skipList.Set(doc.IntId, skipListValue)
 		}
 		lock.Unlock()
 	}
+	for _, numeric := range doc.Numerics {
+		m.numerics.add(doc.IntId, numeric, &SkipListValue{Id: doc.Id, BitsFeature: doc.BitsFeature})
+	}
+	m.stats.add(doc.IntId, docLen)
+}
+
+// 批量添加文档，所有文档的倒排按key归并后，每个key只加一次锁
+func (m *SkipListReverseIndex) BatchAdd(docs []*doc.Document) {
+	postings, docLens := groupPostings(docs)
+	for key, list := range postings {
+		lock := m.getLock(key)
+		lock.Lock()
+
+		var skipList *skiplist.SkipList
+		if val, exist := m.table.Get(key); !exist {
+			skipList = skiplist.New(skiplist.Uint64)
+			m.table.Set(key, skipList)
+		} else {
+			skipList = val.(*skiplist.SkipList)
+		}
+		if skipList.Len() == 0 {
+			m.dict.add(key)
+		}
+		for _, posting := range list {
+			d := docs[posting.doc]
+			skipList.Set(d.IntId, &SkipListValue{Id: d.Id, BitsFeature: d.BitsFeature, Tf: len(posting.positions), Positions: posting.positions})
+		}
+		lock.Unlock()
+	}
+	for i, d := range docs {
+		for _, numeric := range d.Numerics {
+			m.numerics.add(d.IntId, numeric, &SkipListValue{Id: d.Id, BitsFeature: d.BitsFeature})
+		}
+		m.stats.add(d.IntId, docLens[i])
+	}
 }
 
 // 删除doc
 func (m *SkipListReverseIndex) Delete(intId uint64, keyWord *doc.KeyWord) {
-	key := keyWord.ToString()
+	m.removePosting(keyWord.ToString(), intId)
+	m.stats.remove(intId)
+}
+
+// 从key的倒排链上删除doc
+func (m *SkipListReverseIndex) removePosting(key string, intId uint64) {
 	lock := m.getLock(key)
 	lock.Lock()
+	defer lock.Unlock()
 
 	if val, exist := m.table.Get(key); exist {
 		skipList := val.(*skiplist.SkipList)
 		skipList.Remove(intId)
+		if skipList.Len() == 0 {
+			//倒排链为空，从词典中删除
+			m.dict.remove(key)
+		}
 	}
+}
 
-	lock.Unlock()
+// 更新doc，只修改关键词位置有变化的倒排链；跳表的每个倒排上都保存了特征，特征变化时需要更新所有倒排
+func (m *SkipListReverseIndex) Update(old, new *doc.Document) {
+	featureChanged := !sameFeatures(old.BitsFeature, new.BitsFeature)
+	oldPositions := termPositions(old.Keywords)
+	docLen := 0
+	for key, positions := range termPositions(new.Keywords) {
+		docLen += len(positions)
+		oldPosition, exist := oldPositions[key]
+		delete(oldPositions, key)
+		if exist && slices.Equal(oldPosition, positions) && !featureChanged {
+			continue
+		}
+		lock := m.getLock(key)
+		lock.Lock()
+		var skipList *skiplist.SkipList
+		if val, exist := m.table.Get(key); !exist {
+			skipList = skiplist.New(skiplist.Uint64)
+			m.table.Set(key, skipList)
+		} else {
+			skipList = val.(*skiplist.SkipList)
+		}
+		if skipList.Len() == 0 {
+			m.dict.add(key)
+		}
+		skipList.Set(new.IntId, &SkipListValue{Id: new.Id, BitsFeature: new.BitsFeature, Tf: len(positions), Positions: positions})
+		lock.Unlock()
+	}
+	// 新文档中已经没有的关键词
+	for key := range oldPositions {
+		m.removePosting(key, new.IntId)
+	}
+
+	removed, added, kept := diffNumerics(old.Numerics, new.Numerics)
+	for _, numeric := range removed {
+		m.numerics.remove(new.IntId, numeric)
+	}
+	if featureChanged {
+		added = append(added, kept...)
+	}
+	for _, numeric := range added {
+		m.numerics.add(new.IntId, numeric, &SkipListValue{Id: new.Id, BitsFeature: new.BitsFeature})
+	}
+	m.stats.add(new.IntId, docLen)
 }
 
-// 根据查询表达式查找结果,返回业务id集合
-func (m *SkipListReverseIndex) Search(q *term_query.TermQuery, onFlag *util.Bitmap, offFlag *util.Bitmap, orFlags []*util.Bitmap) []string {
+// 从数值索引上删除doc
+func (m *SkipListReverseIndex) DeleteNumeric(intId uint64, numeric *doc.NumericField) {
+	m.numerics.remove(intId, numeric)
+	m.stats.remove(intId)
+}
+
+// 根据查询表达式查找结果,返回按得分降序的文档
+func (m *SkipListReverseIndex) Search(q *term_query.TermQuery, onFlag *util.Bitmap, offFlag *util.Bitmap, orFlags []*util.Bitmap, topK int, aggs []*index.Aggregation) ([]*SearchHit, []*index.AggregationResult) {
 	//获取查询结果
 	search := m.search(q, onFlag, offFlag, orFlags)
 	if search == nil {
-		return nil
+		return nil, aggregate(aggs, nil)
 	}
-	res := make([]string, 0, search.Len())
 	//遍历跳表找出业务id
-	node := search.Front()
-	for node != nil {
-		skv := node.Value.(*SkipListValue)
-		res = append(res, skv.Id)
-		node = node.Next()
+	each := func(fn func(id string, intId uint64)) {
+		for node := search.Front(); node != nil; node = node.Next() {
+			fn(node.Value.(*SkipListValue).Id, node.Key().(uint64))
+		}
 	}
-	return res
+	return rank(each, q, m, m.stats, m.bm25, topK), aggregate(aggs, m.aggSource(search))
+}
+
+// 在匹配的文档集合上聚合
+func (m *SkipListReverseIndex) aggSource(matched *skiplist.SkipList) *aggSource {
+	return &aggSource{
+		dict:     m.dict,
+		numerics: m.numerics,
+		termCount: func(key string) int {
+			value, exist := m.table.Get(key)
+			if !exist {
+				return 0
+			}
+			lock := m.getLock(key)
+			lock.RLock()
+			defer lock.RUnlock()
+			// 遍历较短的一个，在另一个上查找
+			short, long := value.(*skiplist.SkipList), matched
+			if short.Len() > long.Len() {
+				short, long = long, short
+			}
+			count := 0
+			for node := short.Front(); node != nil; node = node.Next() {
+				if long.Get(node.Key()) != nil {
+					count++
+				}
+			}
+			return count
+		},
+		contains: func(intId uint64) bool {
+			return matched.Get(intId) != nil
+		},
+	}
+}
+
+// 输入提示
+func (m *SkipListReverseIndex) Suggest(request *index.SuggestRequest) []*index.Suggestion {
+	return suggest(request, &suggestSource{
+		dict:     m.dict,
+		numerics: m.numerics,
+		docFreq:  m.docFreq,
+		contains: func(key string, intId uint64) bool {
+			value, exist := m.table.Get(key)
+			if !exist {
+				return false
+			}
+			lock := m.getLock(key)
+			lock.RLock()
+			defer lock.RUnlock()
+			return value.(*skiplist.SkipList).Get(intId) != nil
+		},
+	})
+}
+
+// 包含关键词的文档数
+func (m *SkipListReverseIndex) docFreq(key string) int {
+	value, exist := m.table.Get(key)
+	if !exist {
+		return 0
+	}
+	lock := m.getLock(key)
+	lock.RLock()
+	defer lock.RUnlock()
+	return value.(*skiplist.SkipList).Len()
+}
+
+// 展开前缀、通配符、模糊查询
+func (m *SkipListReverseIndex) expand(t *term_query.MultiTerm) []string {
+	return m.dict.expand(t)
+}
+
+// 关键词在文档中出现的次数
+func (m *SkipListReverseIndex) termFreq(key string, intId uint64) int {
+	value, exist := m.table.Get(key)
+	if !exist {
+		return 0
+	}
+	lock := m.getLock(key)
+	lock.RLock()
+	defer lock.RUnlock()
+	if elem := value.(*skiplist.SkipList).Get(intId); elem != nil {
+		return elem.Value.(*SkipListValue).Tf
+	}
+	return 0
 }
 
 // 根据查询表达式查找结果，保存在跳表中
 func (m *SkipListReverseIndex) search(q *term_query.TermQuery, onFlag *util.Bitmap, offFlag *util.Bitmap, orFlags []*util.Bitmap) *skiplist.SkipList {
+	if q == nil {
+		return nil
+	}
+	res := m.searchPositive(q, onFlag, offFlag, orFlags)
+	if res == nil || len(q.MustNot) == 0 {
+		return res
+	}
+	//4、mustNot关系，去掉满足任意一个条件的文档
+	excludes := make([]*skiplist.SkipList, 0, len(q.MustNot))
+	for _, val := range q.MustNot {
+		excludes = append(excludes, m.search(val, nil, nil, nil))
+	}
+	return util.DifferenceOfSkipList(res, excludes...)
+}
+
+// 查找Keyword、Must、Should的结果
+func (m *SkipListReverseIndex) searchPositive(q *term_query.TermQuery, onFlag *util.Bitmap, offFlag *util.Bitmap, orFlags []*util.Bitmap) *skiplist.SkipList {
 	//根据查询表达式分三种情况
 	//1、存在关键字
 	if q.Keyword != nil {
-		res := skiplist.New(skiplist.Uint64)
-		key := q.Keyword.ToString()
-		//得到关键字的跳表
-		value, exist := m.table.Get(key)
+		return m.searchKey(q.Keyword.ToString(), onFlag, offFlag, orFlags)
+	}
 
-		if !exist {
-			return nil
+	//1.1、短语
+	if q.Phrase != nil {
+		return m.searchPhrase(q.Phrase, onFlag, offFlag, orFlags)
+	}
+
+	//1.2、前缀、通配符、模糊查询，展开成多个关键词求并集
+	if q.MultiTerm != nil {
+		keys := m.dict.expand(q.MultiTerm)
+		res := make([]*skiplist.SkipList, 0, len(keys))
+		for _, key := range keys {
+			res = append(res, m.searchKey(key, onFlag, offFlag, orFlags))
 		}
-		list := value.(*skiplist.SkipList)
-		node := list.Front()
-		//遍历跳表
-		for node != nil {
-			intId := node.Key().(uint64)
-			skv, _ := node.Value.(SkipListValue)
-			//跳表上的BitsFeature保存了文档信息，判断文档是否满足要求
-			if intId > 0 && filter(skv.BitsFeature, onFlag, offFlag, orFlags) {
-				res.Set(node.Key(), node.Value)
+		return util.UnionsetOfSkipList(res...)
+	}
+
+	//1.3、数值范围
+	if q.Range != nil {
+		res := skiplist.New(skiplist.Uint64)
+		m.numerics.search(q.Range, func(intId uint64, value any) {
+			skv := value.(*SkipListValue)
+			if filter(skv.BitsFeature, onFlag, offFlag, orFlags) {
+				res.Set(intId, skv)
 			}
-			node = node.Next()
-		}
+		})
 		return res
 	}
 
@@ -142,23 +358,98 @@ func (m *SkipListReverseIndex) search(q *term_query.TermQuery, onFlag *util.Bitm
 	return nil
 }
 
-// 判断bitmap是否满足条件
+// 查找关键词的倒排链，去掉不满足特征要求的文档
+func (m *SkipListReverseIndex) searchKey(key string, onFlag *util.Bitmap, offFlag *util.Bitmap, orFlags []*util.Bitmap) *skiplist.SkipList {
+	res := skiplist.New(skiplist.Uint64)
+	//得到关键字的跳表
+	value, exist := m.table.Get(key)
+
+	if !exist {
+		return nil
+	}
+	list := value.(*skiplist.SkipList)
+	node := list.Front()
+	//遍历跳表
+	for node != nil {
+		intId := node.Key().(uint64)
+		skv := node.Value.(*SkipListValue)
+		//跳表上的BitsFeature保存了文档信息，判断文档是否满足要求
+		if intId > 0 && filter(skv.BitsFeature, onFlag, offFlag, orFlags) {
+			res.Set(node.Key(), node.Value)
+		}
+		node = node.Next()
+	}
+	return res
+}
+
+// 查找短语，先对每个词的倒排链求交集，再根据位置判断是否组成短语
+func (m *SkipListReverseIndex) searchPhrase(phrase *term_query.Phrase, onFlag *util.Bitmap, offFlag *util.Bitmap, orFlags []*util.Bitmap) *skiplist.SkipList {
+	keys := phrase.Keys()
+	if len(keys) == 0 {
+		return nil
+	}
+	lists := make([]*skiplist.SkipList, 0, len(keys))
+	for _, key := range keys {
+		list := m.searchKey(key, onFlag, offFlag, orFlags)
+		if list == nil {
+			return nil
+		}
+		lists = append(lists, list)
+	}
+	candidates := util.IntersectionOfSkipList(lists...)
+	if len(lists) == 1 {
+		return candidates
+	}
+
+	res := skiplist.New(skiplist.Uint64)
+	positions := make([][]uint32, len(lists))
+	node := candidates.Front()
+	for node != nil {
+		for i, list := range lists {
+			positions[i] = list.Get(node.Key()).Value.(*SkipListValue).Positions
+		}
+		if matchPhrase(positions, int(phrase.Slop)) {
+			res.Set(node.Key(), node.Value)
+		}
+		node = node.Next()
+	}
+	return res
+}
+
+// 判断bitmap是否满足条件，为nil的条件不做限制
 func filter(q *util.Bitmap, onFlag *util.Bitmap, offFlag *util.Bitmap, orFlags []*util.Bitmap) bool {
+	// 文档没有特征，只要有条件就不满足
+	if q == nil {
+		if onFlag != nil && !onFlag.IsZero() {
+			return false
+		}
+		for _, val := range orFlags {
+			if val != nil && !val.IsZero() {
+				return false
+			}
+		}
+		return true
+	}
+
 	// onFalg全部满足，求交集后判断是否等与onFlag
-	r1 := util.IntersectionOfBitmaps(q, onFlag)
-	if !onFlag.IsEqual(r1) {
-		return false
+	if onFlag != nil {
+		r1 := util.IntersectionOfBitmaps(q, onFlag)
+		if !onFlag.IsEqual(r1) {
+			return false
+		}
 	}
 
 	// offFalg全部满足，求交集后判断是否为0
-	r2 := util.IntersectionOfBitmaps(q, offFlag)
-	if !r2.IsZero() {
-		return false
+	if offFlag != nil {
+		r2 := util.IntersectionOfBitmaps(q, offFlag)
+		if !r2.IsZero() {
+			return false
+		}
 	}
 
 	// orFlag 表示至少满足一个
 	for _, val := range orFlags {
-		if val.IsZero() {
+		if val == nil || val.IsZero() {
 			continue
 		}
 		res := util.IntersectionOfBitmaps(q, val)
@@ -0,0 +1,176 @@
+package reverse_index
+
+import (
+	"Research/types/doc"
+	"Research/types/index"
+	"sort"
+)
+
+const defaultSuggestSize = 10 // 默认返回的提示数量
+
+// 补全的数据来源，倒排索引提供词的文档数和倒排链
+type suggestSource struct {
+	dict     *termDict
+	numerics *numericIndex
+	docFreq  func(key string) int
+	contains func(key string, intId uint64) bool // 关键词的倒排链上是否有该文档
+}
+
+// 补全的候选词，found表示已经在权重字段上找到了权重
+type candidate struct {
+	*index.Suggestion
+	key   string
+	found bool
+}
+
+// 用词典中以前缀开头的词补全，权重是包含该词的文档数，或这些文档上权重字段的最大值
+// 只计算可能排进前Size个的词的权重，编辑距离更大的词不会被返回，不用计算
+func suggest(request *index.SuggestRequest, source *suggestSource) []*index.Suggestion {
+	candidates := make([]*candidate, 0)
+	for _, field := range request.Fields {
+		for _, c := range source.dict.complete(field, request.Prefix, request.Fuzzy, int(request.Fuzziness)) {
+			candidates = append(candidates, &candidate{
+				Suggestion: &index.Suggestion{Text: c.word, Field: field, Distance: int32(c.distance)},
+				key:        (&doc.KeyWord{Field: field, Word: c.word}).ToString(),
+			})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Distance < candidates[j].Distance
+	})
+	size := suggestSize(request.Size, len(candidates))
+	if size < len(candidates) {
+		// 保留第size个词所在的整个距离组，组内再按权重选
+		n := size
+		for n < len(candidates) && candidates[n].Distance == candidates[size-1].Distance {
+			n++
+		}
+		candidates = candidates[:n]
+	}
+	if len(request.WeightField) == 0 {
+		for _, c := range candidates {
+			c.Weight = float64(source.docFreq(c.key))
+		}
+	} else {
+		fieldWeights(request.WeightField, size, candidates, source)
+		// 没有权重的词排在同一距离组内有权重的词后面
+		sort.SliceStable(candidates, func(i, j int) bool {
+			a, b := candidates[i], candidates[j]
+			if a.Distance != b.Distance {
+				return a.Distance < b.Distance
+			}
+			if a.found != b.found {
+				return a.found
+			}
+			return a.found && a.Weight > b.Weight
+		})
+		candidates = candidates[:min(size, len(candidates))]
+	}
+	res := make([]*index.Suggestion, 0, len(candidates))
+	for _, c := range candidates {
+		res = append(res, c.Suggestion)
+	}
+	return finishSuggestions(request.Size, res)
+}
+
+// 实际返回的提示数量，负数表示全部
+func suggestSize(size int32, total int) int {
+	if size == 0 {
+		return defaultSuggestSize
+	}
+	if size < 0 || int(size) > total {
+		return total
+	}
+	return int(size)
+}
+
+// 权重是包含该词的文档上权重字段的最大值，从大到小遍历权重字段的数值，词第一次命中时的数值就是它的权重
+// candidates已按编辑距离排序，前size个词及其顺序确定后就停止遍历
+func fieldWeights(field string, size int, candidates []*candidate, source *suggestSource) {
+	source.numerics.eachDesc(field, func(value float64, docs map[uint64]any) bool {
+		for _, c := range candidates {
+			if c.found {
+				continue
+			}
+			for intId := range docs {
+				if source.contains(c.key, intId) {
+					c.Weight, c.found = value, true
+					break
+				}
+			}
+		}
+		return !weightsDecided(candidates, size)
+	})
+}
+
+// 逐个距离组检查：组内已找到权重的词不少于剩余名额时，前面的名额都由它们占据；
+// 组内还有词没有找到权重且整组都要返回时，它们的顺序还不确定
+func weightsDecided(candidates []*candidate, size int) bool {
+	remain := size
+	for i := 0; i < len(candidates) && remain > 0; {
+		j, found := i, 0
+		for ; j < len(candidates) && candidates[j].Distance == candidates[i].Distance; j++ {
+			if candidates[j].found {
+				found++
+			}
+		}
+		if found >= remain {
+			return true
+		}
+		if found < j-i {
+			return false
+		}
+		remain -= j - i
+		i = j
+	}
+	return true
+}
+
+// 合并各节点的提示，同一字段的同一个词按文档数累加，使用权重字段时取最大值
+// 各节点只返回自己的前Size个，没排进某个节点前Size个的词少算了该节点上的文档数，按文档数排序时是近似结果
+func MergeSuggestions(request *index.SuggestRequest, results ...[]*index.Suggestion) []*index.Suggestion {
+	merged := make(map[string]*index.Suggestion)
+	res := make([]*index.Suggestion, 0)
+	for _, suggestions := range results {
+		for _, suggestion := range suggestions {
+			key := (&doc.KeyWord{Field: suggestion.Field, Word: suggestion.Text}).ToString()
+			exist, ok := merged[key]
+			if !ok {
+				copied := &index.Suggestion{Text: suggestion.Text, Field: suggestion.Field, Weight: suggestion.Weight, Distance: suggestion.Distance}
+				merged[key] = copied
+				res = append(res, copied)
+				continue
+			}
+			if len(request.WeightField) == 0 {
+				exist.Weight += suggestion.Weight
+			} else if suggestion.Weight > exist.Weight {
+				exist.Weight = suggestion.Weight
+			}
+		}
+	}
+	return finishSuggestions(request.Size, res)
+}
+
+// 按编辑距离升序、权重降序、词的字典序排序，再截断
+func finishSuggestions(size int32, suggestions []*index.Suggestion) []*index.Suggestion {
+	sort.Slice(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		if a.Weight != b.Weight {
+			return a.Weight > b.Weight
+		}
+		if a.Text != b.Text {
+			return a.Text < b.Text
+		}
+		return a.Field < b.Field
+	})
+	if size == 0 {
+		size = defaultSuggestSize
+	}
+	if size > 0 && len(suggestions) > int(size) {
+		suggestions = suggestions[:size]
+	}
+	return suggestions
+}
@@ -0,0 +1,85 @@
+package reverse_index
+
+import (
+	"Research/types/doc"
+	"Research/types/index"
+	"testing"
+)
+
+// 两种倒排索引各跑一遍，title字段的词及其文档：apple 1,2,3；app 1,4；apply 4；appendix 6；banana 5
+// 文档6没有权重字段pop
+func eachSuggestIndex(t *testing.T, fn func(t *testing.T, ri IReverseIndex)) {
+	for name, indexType := range map[string]int{"skiplist": SKIPLIST, "roaring": ROARING} {
+		t.Run(name, func(t *testing.T) {
+			ri := NewReverseIndex(indexType, 100)
+			docs := []struct {
+				words []string
+				pop   float64
+			}{{[]string{"apple", "app"}, 1}, {[]string{"apple"}, 50}, {[]string{"apple"}, 7}, {[]string{"apply", "app"}, 20}, {[]string{"banana"}, 99}, {[]string{"appendix"}, -1}}
+			for i, d := range docs {
+				keywords := make([]*doc.KeyWord, 0, len(d.words))
+				for _, word := range d.words {
+					keywords = append(keywords, &doc.KeyWord{Field: "title", Word: word})
+				}
+				var numerics []*doc.NumericField
+				if d.pop >= 0 {
+					numerics = []*doc.NumericField{{Field: "pop", Value: d.pop}}
+				}
+				ri.Add(doc.Document{Id: string(rune('1' + i)), IntId: uint64(i + 1), Keywords: keywords, Numerics: numerics})
+			}
+			fn(t, ri)
+		})
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	cases := []struct {
+		name    string
+		request *index.SuggestRequest
+		texts   []string
+		weights []float64
+	}{
+		// 按包含该词的文档数降序，相同时按字典序
+		{"doc freq", &index.SuggestRequest{Prefix: "app", Size: -1}, []string{"apple", "app", "appendix", "apply"}, []float64{3, 2, 1, 1}},
+		{"size", &index.SuggestRequest{Prefix: "app", Size: 2}, []string{"apple", "app"}, []float64{3, 2}},
+		// 权重是文档上pop的最大值，没有pop的词排在最后
+		{"weight field", &index.SuggestRequest{Prefix: "app", Size: -1, WeightField: "pop"}, []string{"apple", "app", "apply", "appendix"}, []float64{50, 20, 20, 0}},
+		{"weight field size", &index.SuggestRequest{Prefix: "app", Size: 1, WeightField: "pop"}, []string{"apple"}, []float64{50}},
+		// 精确匹配前缀的词排在有拼写错误的前面，距离更大的组不会排进前Size个
+		{"fuzzy", &index.SuggestRequest{Prefix: "appl", Size: -1, Fuzzy: true, Fuzziness: 1}, []string{"apple", "apply", "app", "appendix"}, []float64{3, 1, 2, 1}},
+		{"fuzzy size", &index.SuggestRequest{Prefix: "appl", Size: 2, Fuzzy: true, Fuzziness: 1, WeightField: "pop"}, []string{"apple", "apply"}, []float64{50, 20}},
+		{"no match", &index.SuggestRequest{Prefix: "cherry"}, nil, nil},
+	}
+	eachSuggestIndex(t, func(t *testing.T, ri IReverseIndex) {
+		for _, c := range cases {
+			c.request.Fields = []string{"title"}
+			res := ri.Suggest(c.request)
+			if len(res) != len(c.texts) {
+				t.Errorf("%s: got %d suggestions, want %v", c.name, len(res), c.texts)
+				continue
+			}
+			for i, suggestion := range res {
+				if suggestion.Text != c.texts[i] || suggestion.Weight != c.weights[i] {
+					t.Errorf("%s %d: got %s=%v, want %s=%v", c.name, i, suggestion.Text, suggestion.Weight, c.texts[i], c.weights[i])
+				}
+			}
+		}
+	})
+}
+
+func TestMergeSuggestions(t *testing.T) {
+	node1 := []*index.Suggestion{{Text: "apple", Field: "title", Weight: 3}, {Text: "app", Field: "title", Weight: 2}}
+	node2 := []*index.Suggestion{{Text: "app", Field: "title", Weight: 2}, {Text: "apply", Field: "title", Weight: 1}}
+	res := MergeSuggestions(&index.SuggestRequest{Size: 2}, node1, node2, nil)
+	if len(res) != 2 || res[0].Text != "app" || res[0].Weight != 4 || res[1].Text != "apple" {
+		t.Errorf("doc freq: got %v", res)
+	}
+	// 使用权重字段时取最大值
+	res = MergeSuggestions(&index.SuggestRequest{Size: 2, WeightField: "pop"}, node1, node2, nil)
+	if len(res) != 2 || res[0].Text != "apple" || res[1].Weight != 2 {
+		t.Errorf("weight field: got %v", res)
+	}
+	if node1[1].Weight != 2 {
+		t.Error("node result is modified")
+	}
+}
@@ -0,0 +1,294 @@
+package reverse_index
+
+import (
+	"Research/types/doc"
+	"Research/types/term_query"
+	"github.com/huandu/skiplist"
+	"math"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+const defaultMaxExpansions = 64 // 前缀、通配符、模糊查询默认最多展开的关键词数量
+
+// 词典，每个field的词按字典序保存在跳表中，用于前缀、通配符、模糊查询展开关键词
+// 倒排链非空的词才在词典中，修改倒排链和词典时都持有该词的锁，保证两者一致
+type termDict struct {
+	lock   sync.RWMutex
+	fields map[string]*skiplist.SkipList // field -> 词
+}
+
+func newTermDict() *termDict {
+	return &termDict{fields: make(map[string]*skiplist.SkipList)}
+}
+
+// 倒排索引的key是field\001word，拆分出field和word
+func splitKey(key string) (string, string) {
+	field, word, _ := strings.Cut(key, "\001")
+	return field, word
+}
+
+func (d *termDict) add(key string) {
+	field, word := splitKey(key)
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	words, exist := d.fields[field]
+	if !exist {
+		words = skiplist.New(skiplist.String)
+		d.fields[field] = words
+	}
+	words.Set(word, struct{}{})
+}
+
+func (d *termDict) remove(key string) {
+	field, word := splitKey(key)
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if words, exist := d.fields[field]; exist {
+		words.Remove(word)
+		if words.Len() == 0 {
+			delete(d.fields, field)
+		}
+	}
+}
+
+// field下的所有词，按字典序
+func (d *termDict) words(field string) []string {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	words, exist := d.fields[field]
+	if !exist {
+		return nil
+	}
+	res := make([]string, 0, words.Len())
+	for elem := words.Front(); elem != nil; elem = elem.Next() {
+		res = append(res, elem.Key().(string))
+	}
+	return res
+}
+
+// 把查询展开成倒排索引的key，最多返回MaxExpansions个
+func (d *termDict) expand(t *term_query.MultiTerm) []string {
+	maxExpansions := int(t.MaxExpansions)
+	if maxExpansions <= 0 {
+		maxExpansions = defaultMaxExpansions
+	}
+	var words []string
+	switch t.Type {
+	case term_query.MultiTerm_PREFIX:
+		words = d.scan(t.Field, t.Pattern, maxExpansions, func(string) bool { return true })
+	case term_query.MultiTerm_WILDCARD:
+		// 第一个通配符之前的部分是前缀，只需要扫描这个范围
+		prefix := t.Pattern
+		if i := strings.IndexAny(prefix, "*?"); i >= 0 {
+			prefix = prefix[:i]
+		}
+		pattern := []rune(t.Pattern)
+		words = d.scan(t.Field, prefix, maxExpansions, func(word string) bool {
+			return matchWildcard(pattern, []rune(word))
+		})
+	case term_query.MultiTerm_FUZZY:
+		words = d.fuzzy(t.Field, t.Pattern, int(t.Fuzziness), int(t.PrefixLength), maxExpansions)
+	}
+	keys := make([]string, 0, len(words))
+	for _, word := range words {
+		keys = append(keys, (&doc.KeyWord{Field: t.Field, Word: word}).ToString())
+	}
+	return keys
+}
+
+// 按字典序扫描以prefix开头的词，返回满足match的前limit个
+func (d *termDict) scan(field, prefix string, limit int, match func(string) bool) []string {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	words, exist := d.fields[field]
+	if !exist {
+		return nil
+	}
+	res := make([]string, 0)
+	for elem := words.Find(prefix); elem != nil && len(res) < limit; elem = elem.Next() {
+		word := elem.Key().(string)
+		if !strings.HasPrefix(word, prefix) {
+			break
+		}
+		if match(word) {
+			res = append(res, word)
+		}
+	}
+	return res
+}
+
+// 找出编辑距离不超过fuzziness的词，距离近的优先，返回前limit个
+// prefixLength大于0时只扫描与word开头相同的词，词长相差超过fuzziness的词不计算编辑距离
+func (d *termDict) fuzzy(field, word string, fuzziness, prefixLength, limit int) []string {
+	target := []rune(word)
+	if fuzziness <= 0 {
+		fuzziness = autoFuzziness(len(target))
+	}
+	prefix := ""
+	if prefixLength > 0 {
+		prefix = string(target[:min(prefixLength, len(target))])
+	}
+	// 按编辑距离分组，组内是字典序，每组最多limit个
+	groups := make([][]string, fuzziness+1)
+	closer := 0 // 距离小于fuzziness的词数
+	d.lock.RLock()
+	if words, exist := d.fields[field]; exist {
+		for elem := words.Find(prefix); elem != nil; elem = elem.Next() {
+			w := elem.Key().(string)
+			if !strings.HasPrefix(w, prefix) {
+				break
+			}
+			if abs(utf8.RuneCountInString(w)-len(target)) > fuzziness {
+				continue
+			}
+			distance, ok := editDistance(target, []rune(w), fuzziness)
+			if !ok || len(groups[distance]) >= limit {
+				continue
+			}
+			groups[distance] = append(groups[distance], w)
+			if distance < fuzziness {
+				closer++
+			}
+			// 距离更近的词已经有limit个，之后只找距离更近的
+			for fuzziness > 0 && closer >= limit {
+				fuzziness--
+				closer -= len(groups[fuzziness])
+			}
+		}
+	}
+	d.lock.RUnlock()
+
+	res := make([]string, 0, limit)
+	for _, group := range groups {
+		for _, w := range group {
+			if len(res) == limit {
+				return res
+			}
+			res = append(res, w)
+		}
+	}
+	return res
+}
+
+// 补全时匹配到的词
+type completion struct {
+	word     string
+	distance int // 与前缀的编辑距离
+}
+
+// 找出以prefix开头的词，fuzzy时允许前缀有不超过fuzziness的编辑距离
+func (d *termDict) complete(field, prefix string, fuzzy bool, fuzziness int) []completion {
+	if !fuzzy {
+		words := d.scan(field, prefix, math.MaxInt, func(string) bool { return true })
+		res := make([]completion, 0, len(words))
+		for _, word := range words {
+			res = append(res, completion{word: word})
+		}
+		return res
+	}
+	target := []rune(prefix)
+	if fuzziness <= 0 {
+		fuzziness = autoFuzziness(len(target))
+	}
+	res := make([]completion, 0)
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	if words, exist := d.fields[field]; exist {
+		for elem := words.Front(); elem != nil; elem = elem.Next() {
+			w := elem.Key().(string)
+			if distance, ok := prefixDistance(target, []rune(w), fuzziness); ok {
+				res = append(res, completion{word: w, distance: distance})
+			}
+		}
+	}
+	return res
+}
+
+// 根据词长选择编辑距离，短词容易误匹配
+func autoFuzziness(length int) int {
+	switch {
+	case length <= 2:
+		return 0
+	case length <= 5:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// 计算编辑距离，超过max时提前结束并返回false
+func editDistance(a, b []rune, max int) (int, bool) {
+	if abs(len(a)-len(b)) > max {
+		return 0, false
+	}
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			rowMin = min(rowMin, cur[j])
+		}
+		if rowMin > max {
+			return 0, false
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)], prev[len(b)] <= max
+}
+
+// 前缀与词的开头部分的最小编辑距离，开头部分的长度与前缀相差不超过max
+func prefixDistance(prefix, word []rune, max int) (int, bool) {
+	best, found := 0, false
+	for n := len(prefix) - max; n <= len(prefix)+max && n <= len(word); n++ {
+		if n < 0 {
+			continue
+		}
+		if distance, ok := editDistance(prefix, word[:n], max); ok && (!found || distance < best) {
+			best, found = distance, true
+		}
+	}
+	return best, found
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// 通配符匹配，*匹配任意个字符，?匹配一个字符
+func matchWildcard(pattern, word []rune) bool {
+	p, w := 0, 0
+	star, mark := -1, 0 // 最近一个*的位置，以及*开始匹配的位置
+	for w < len(word) {
+		if p < len(pattern) && (pattern[p] == '?' || pattern[p] == word[w]) {
+			p++
+			w++
+		} else if p < len(pattern) && pattern[p] == '*' {
+			star, mark = p, w
+			p++
+		} else if star >= 0 {
+			// 回溯，让*多匹配一个字符
+			mark++
+			p, w = star+1, mark
+		} else {
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
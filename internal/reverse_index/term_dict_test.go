@@ -0,0 +1,72 @@
+package reverse_index
+
+import (
+	"Research/types/doc"
+	"Research/types/term_query"
+	"slices"
+	"testing"
+)
+
+func TestMultiTermQuery(t *testing.T) {
+	words := []string{"apple", "apply", "ample", "maple", "apricot", "banana"}
+	cases := []struct {
+		name  string
+		query *term_query.MultiTerm
+		ids   []uint64
+	}{
+		{"prefix", &term_query.MultiTerm{Type: term_query.MultiTerm_PREFIX, Pattern: "ap"}, []uint64{1, 2, 5}},
+		// 按字典序展开，超过MaxExpansions的词不查
+		{"prefix max expansions", &term_query.MultiTerm{Type: term_query.MultiTerm_PREFIX, Pattern: "ap", MaxExpansions: 1}, []uint64{1}},
+		{"prefix no match", &term_query.MultiTerm{Type: term_query.MultiTerm_PREFIX, Pattern: "c"}, nil},
+		{"wildcard ?", &term_query.MultiTerm{Type: term_query.MultiTerm_WILDCARD, Pattern: "a?ple"}, []uint64{1, 3}},
+		{"wildcard *", &term_query.MultiTerm{Type: term_query.MultiTerm_WILDCARD, Pattern: "*ple"}, []uint64{1, 3, 4}},
+		{"fuzzy", &term_query.MultiTerm{Type: term_query.MultiTerm_FUZZY, Pattern: "apple", Fuzziness: 1}, []uint64{1, 2, 3}},
+		// 词长为4时自动选择编辑距离1
+		{"fuzzy auto", &term_query.MultiTerm{Type: term_query.MultiTerm_FUZZY, Pattern: "aple"}, []uint64{1, 3, 4}},
+		{"fuzzy prefix length", &term_query.MultiTerm{Type: term_query.MultiTerm_FUZZY, Pattern: "apple", Fuzziness: 1, PrefixLength: 2}, []uint64{1, 2}},
+		// 距离近的优先，距离相同时按字典序
+		{"fuzzy max expansions", &term_query.MultiTerm{Type: term_query.MultiTerm_FUZZY, Pattern: "apple", Fuzziness: 1, MaxExpansions: 2}, []uint64{1, 3}},
+	}
+	for name, indexType := range map[string]int{"skiplist": SKIPLIST, "roaring": ROARING} {
+		t.Run(name, func(t *testing.T) {
+			ri := NewReverseIndex(indexType, 100)
+			for i, word := range words {
+				ri.Add(doc.Document{Id: word, IntId: uint64(i + 1), Keywords: []*doc.KeyWord{{Field: "w", Word: word}}})
+			}
+			for _, c := range cases {
+				c.query.Field = "w"
+				hits, _ := ri.Search(&term_query.TermQuery{MultiTerm: c.query}, nil, nil, nil, 0, nil)
+				ids := make([]uint64, 0, len(hits))
+				for _, hit := range hits {
+					ids = append(ids, hit.IntId)
+				}
+				slices.Sort(ids)
+				if !slices.Equal(ids, c.ids) {
+					t.Errorf("%s: got %v, want %v", c.name, ids, c.ids)
+				}
+			}
+		})
+	}
+}
+
+// 距离近的词够了之后只找距离更近的，结果与全部找出再排序截断相同
+func TestFuzzyLimit(t *testing.T) {
+	d := newTermDict()
+	for _, word := range []string{"abcd", "abce", "abcf", "abc", "abcde"} {
+		d.add((&doc.KeyWord{Field: "w", Word: word}).ToString())
+	}
+	cases := []struct {
+		limit int
+		want  []string
+	}{
+		{1, []string{"abcd"}},
+		{2, []string{"abcd", "abc"}},
+		{4, []string{"abcd", "abc", "abcde", "abce"}},
+		{10, []string{"abcd", "abc", "abcde", "abce", "abcf"}},
+	}
+	for _, c := range cases {
+		if got := d.fuzzy("w", "abcd", 1, 0, c.limit); !slices.Equal(got, c.want) {
+			t.Errorf("limit %d: got %v, want %v", c.limit, got, c.want)
+		}
+	}
+}
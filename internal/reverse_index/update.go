@@ -0,0 +1,40 @@
+package reverse_index
+
+import (
+	"Research/types/doc"
+	"Research/util"
+)
+
+// 比较更新前后的数值字段，返回删除的、新增的和没有变化的数值
+func diffNumerics(old, new []*doc.NumericField) ([]*doc.NumericField, []*doc.NumericField, []*doc.NumericField) {
+	type numericKey struct {
+		field string
+		value float64
+	}
+	olds := make(map[numericKey]*doc.NumericField, len(old))
+	for _, numeric := range old {
+		olds[numericKey{numeric.Field, numeric.Value}] = numeric
+	}
+	var removed, added, kept []*doc.NumericField
+	for _, numeric := range new {
+		key := numericKey{numeric.Field, numeric.Value}
+		if _, exist := olds[key]; exist {
+			kept = append(kept, numeric)
+			delete(olds, key)
+		} else {
+			added = append(added, numeric)
+		}
+	}
+	for _, numeric := range olds {
+		removed = append(removed, numeric)
+	}
+	return removed, added, kept
+}
+
+// 两个特征是否相同，都为空时相同
+func sameFeatures(a, b *util.Bitmap) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.IsEqual(b)
+}
@@ -18,9 +18,7 @@ func main() {
 	c := etc.GetConfig("etc/etc.yaml")
 	//构造索引服务
 	worker, err := index_service.NewIndexServiceWorker(
-		c.ReverseIndex.DocNumEstimate,
-		c.ForwardIndex.Dbtype,
-		c.GetDateDir(),
+		c,
 		ServiceHub.NewEndPoint(c.Server.NodeIp, c.Server.Port, c.Server.Weight))
 	if err != nil {
 		return
@@ -33,7 +31,7 @@ func main() {
 		return
 	}
 	// 根据权重加载数据
-	worker.Indexer.LoadFromIndexFile(worker.Endpoint.Weight)
+	worker.LoadFromIndexFile(worker.Endpoint.Weight)
 	//监听端口
 	lis, err := net.Listen("tcp", strconv.Itoa(c.Server.Port))
 	if err != nil {
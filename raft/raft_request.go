@@ -11,6 +11,7 @@ import (
 	"context"
 	"encoding/gob"
 	"errors"
+	"google.golang.org/protobuf/proto"
 )
 
 type RaftRequest struct {
@@ -29,54 +30,57 @@ var (
 	errArgs      = errors.New("args error")
 )
 
-func (r *RaftRequest) Research(ctx context.Context, req *raft.ResearchRequest) (resp *raft.ResearchResponse, err error) {
-	*resp.Success = false
+func (r *RaftRequest) Research(ctx context.Context, req *raft.ResearchRequest) (*raft.ResearchResponse, error) {
+	resp := &raft.ResearchResponse{Success: proto.Bool(false)}
+	fail := func(err error) (*raft.ResearchResponse, error) {
+		resp.ErrorMsg = proto.String(err.Error())
+		return resp, nil
+	}
 	// 参数校验
-	if len(req.Args) == 0 || *req.ReqType == 3 && len(req.Args) != 4 {
-		*resp.ErrorMsg = errArgs.Error()
-		return
+	if len(req.Args) == 0 || req.GetReqType() == 3 && len(req.Args) != 4 {
+		return fail(errArgs)
 	}
 
-	// 1: add, 2: delete, 3: search
-	resp = &raft.ResearchResponse{}
-	switch *req.ReqType {
+	// 1: add, 2: delete, 3: search, 4: update
+	switch req.GetReqType() {
 	case 1:
-		docs, err := Deserialize[*doc.Document](req.Args[0])
+		request, err := Deserialize[*index.AddDocRequest](req.Args[0])
+		if err != nil {
+			return fail(err)
+		}
+		addDoc, err := r.AddDocTo(ctx, request)
 		if err != nil {
-			*resp.ErrorMsg = err.Error()
-			return
+			return fail(err)
 		}
-		addDoc, err := r.AddDoc(ctx, docs)
-		resp.Values = append(resp.Values, addDoc.String())
+		// 版本冲突也是正常结果，由Values中的Conflict标记
+		resp.Values = index_service.SerializeAffectedCount(addDoc)
 	case 2:
 		docId, err := Deserialize[*index.DocId](req.Args[0])
 		if err != nil {
-			*resp.ErrorMsg = err.Error()
-			return
+			return fail(err)
 		}
-		addDoc, err := r.DeleteDoc(ctx, docId)
-		resp.Values = append(resp.Values, addDoc.String())
+		deleteDoc, err := r.DeleteDoc(ctx, docId)
+		if err != nil {
+			return fail(err)
+		}
+		resp.Values = index_service.SerializeAffectedCount(deleteDoc)
 	case 3:
 
 		query, err := Deserialize[*term_query.TermQuery](req.Args[0])
 		if err != nil {
-			*resp.ErrorMsg = err.Error()
-			return
+			return fail(err)
 		}
 		onFlag, err := Deserialize[*util.Bitmap](req.Args[1])
 		if err != nil {
-			*resp.ErrorMsg = err.Error()
-			return
+			return fail(err)
 		}
 		offFlag, err := Deserialize[*util.Bitmap](req.Args[2])
 		if err != nil {
-			*resp.ErrorMsg = err.Error()
-			return
+			return fail(err)
 		}
 		OrFlags, err := Deserialize[[]*util.Bitmap](req.Args[3])
 		if err != nil {
-			*resp.ErrorMsg = err.Error()
-			return
+			return fail(err)
 		}
 		isr := &index.SearchRequest{
 			Query:   query,
@@ -85,32 +89,46 @@ func (r *RaftRequest) Research(ctx context.Context, req *raft.ResearchRequest) (
 			OrFlags: OrFlags,
 		}
 		searchRes, err := r.Search(ctx, isr)
+		if err != nil {
+			return fail(err)
+		}
 		for _, v := range searchRes.Results {
 			str, err := Serialize[*doc.Document](v)
 			if err != nil {
-				*resp.ErrorMsg = err.Error()
-				return
+				return fail(err)
 			}
 			resp.Values = append(resp.Values, str)
 		}
+	case 4:
+		request, err := Deserialize[*index.UpdateDocRequest](req.Args[0])
+		if err != nil {
+			return fail(err)
+		}
+		updateDoc, err := r.UpdateDoc(ctx, request)
+		if err != nil {
+			return fail(err)
+		}
+		resp.Values = index_service.SerializeAffectedCount(updateDoc)
+	default:
+		return fail(errArgs)
 	}
-	*resp.Success = true
-	return
+	resp.Success = proto.Bool(true)
+	return resp, nil
 }
 
 //type SerializeType[T interface{*doc.Document} | *index.DocId | *term_query.TermQuery | *util.Bitmap | []*util.Bitmap] interface {}
 
 // 将序列化后的字符串转为相应类型
-func Deserialize[T *doc.Document | *index.DocId | *term_query.TermQuery | *util.Bitmap | []*util.Bitmap](docStr string) (target T, err error) {
+func Deserialize[T *doc.Document | *index.AddDocRequest | *index.UpdateDocRequest | *index.DocId | *term_query.TermQuery | *util.Bitmap | []*util.Bitmap](docStr string) (target T, err error) {
 	reader := bytes.NewReader([]byte{})
 	reader.Reset([]byte(docStr))
 	decoder := gob.NewDecoder(reader)
-	err = decoder.Decode(target)
+	err = decoder.Decode(&target)
 	return
 }
 
 // 反序列化
-func Serialize[T *doc.Document | *index.DocId | *term_query.TermQuery | *util.Bitmap | []*util.Bitmap](input T) (str string, err error) {
+func Serialize[T *doc.Document | *index.AddDocRequest | *index.UpdateDocRequest | *index.DocId | *term_query.TermQuery | *util.Bitmap | []*util.Bitmap](input T) (str string, err error) {
 	var buffer bytes.Buffer
 	encoder := gob.NewEncoder(&buffer)
 	err = encoder.Encode(input)
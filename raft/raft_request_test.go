@@ -0,0 +1,72 @@
+package raft
+
+import (
+	"Research/etc"
+	"Research/index_service"
+	"Research/types/doc"
+	"Research/types/index"
+	"Research/types/raft"
+	"context"
+	"testing"
+)
+
+func newTestRaftRequest(t *testing.T) *RaftRequest {
+	t.Helper()
+	c := &etc.Config{}
+	c.ForwardIndex.Dbtype = "memory"
+	c.Expire.SweepInterval = -1
+	worker, err := index_service.NewIndexServiceWorker(c, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = worker.Close() })
+	return NewRaftRequest(worker)
+}
+
+func research(t *testing.T, r *RaftRequest, reqType int64, args ...string) *raft.ResearchResponse {
+	t.Helper()
+	resp, err := r.Research(context.Background(), &raft.ResearchRequest{ReqType: &reqType, Args: args})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestResearchUpdateDoc(t *testing.T) {
+	r := newTestRaftRequest(t)
+	add, _ := Serialize[*index.AddDocRequest](&index.AddDocRequest{Doc: &doc.Document{Id: "a", Keywords: []*doc.KeyWord{{Field: "f", Word: "old"}}}})
+	if resp := research(t, r, 1, add); !resp.GetSuccess() {
+		t.Fatalf("add: %s", resp.GetErrorMsg())
+	}
+
+	update, _ := Serialize[*index.UpdateDocRequest](&index.UpdateDocRequest{
+		DocId:     "a",
+		Keywords:  []*doc.KeyWord{{Field: "f", Word: "new"}},
+		Condition: &index.Condition{IfVersion: 1},
+	})
+	resp := research(t, r, 4, update)
+	if !resp.GetSuccess() {
+		t.Fatalf("update: %s", resp.GetErrorMsg())
+	}
+	affected, err := index_service.DeserializeAffectedCount(resp.Values)
+	if err != nil || affected.Count != 1 || affected.Version != 2 || affected.Conflict {
+		t.Fatalf("update: got values %v error %v", resp.Values, err)
+	}
+	if d := r.Indexes.Default.GetDoc("a"); d == nil || d.Version != 2 {
+		t.Errorf("doc is not updated")
+	}
+
+	// 版本不满足时是冲突，不是失败
+	resp = research(t, r, 4, update)
+	affected, _ = index_service.DeserializeAffectedCount(resp.Values)
+	if !resp.GetSuccess() || !affected.GetConflict() || affected.GetVersion() != 2 {
+		t.Errorf("stale update: success %v values %v", resp.GetSuccess(), resp.Values)
+	}
+}
+
+func TestResearchUnknownType(t *testing.T) {
+	r := newTestRaftRequest(t)
+	if resp := research(t, r, 9, "x"); resp.GetSuccess() || resp.GetErrorMsg() != errArgs.Error() {
+		t.Errorf("got success %v error %q", resp.GetSuccess(), resp.GetErrorMsg())
+	}
+}
@@ -0,0 +1,78 @@
+package schema
+
+import (
+	"Research/util"
+	"fmt"
+)
+
+// 所有Bitmap使用相同的容量，过滤时IsEqual会比较容量
+func (s *Schema) featureCap() int {
+	return len(s.Features) + 1
+}
+
+// 把特征名称编译成Bitmap，可以用作文档的BitsFeature，也可以用作检索时的OnFlag、OffFlag
+func (s *Schema) Flags(names ...string) (*util.Bitmap, error) {
+	bitmap := util.NewBitmap(s.featureCap())
+	for _, name := range names {
+		index, exist := s.features[name]
+		if !exist {
+			return nil, fmt.Errorf("unknown feature %s", name)
+		}
+		bitmap.SetBit(index)
+	}
+	return bitmap, nil
+}
+
+// 编译检索条件：on中的特征必须都有，off中的特征必须都没有，or中的每一组至少有一个
+func (s *Schema) SearchFlags(on, off []string, or ...[]string) (onFlag, offFlag *util.Bitmap, orFlags []*util.Bitmap, err error) {
+	if len(on) > 0 {
+		if onFlag, err = s.Flags(on...); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	if len(off) > 0 {
+		if offFlag, err = s.Flags(off...); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	for _, names := range or {
+		flag, err := s.Flags(names...)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		orFlags = append(orFlags, flag)
+	}
+	return onFlag, offFlag, orFlags, nil
+}
+
+// 返回Bitmap中设置了的特征名称
+func (s *Schema) FeatureNames(bitmap *util.Bitmap) []string {
+	names := make([]string, 0)
+	if bitmap == nil {
+		return names
+	}
+	for i, name := range s.Features {
+		if bit, ok := bitmap.GetBit(i + 1); ok && bit == 1 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// 检查Bitmap中没有未定义的特征，并转换成schema统一的容量
+func (s *Schema) NormalizeFeatures(bitmap *util.Bitmap) (*util.Bitmap, error) {
+	if bitmap == nil {
+		return nil, nil
+	}
+	res := util.NewBitmap(s.featureCap())
+	for i := 1; i < bitmap.Cap(); i++ {
+		if bit, _ := bitmap.GetBit(i); bit == 0 {
+			continue
+		}
+		if i > len(s.Features) {
+			return nil, fmt.Errorf("feature bit %d is not defined", i)
+		}
+		res.SetBit(i)
+	}
+	return res, nil
+}
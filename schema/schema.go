@@ -0,0 +1,121 @@
+package schema
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+)
+
+// 字段类型
+const (
+	KEYWORD = "keyword" // 关键词，直接写入倒排索引
+	TEXT    = "text"    // 原始文本，经过分析后生成关键词
+	NUMERIC = "numeric" // 数值，支持范围查询
+	DATE    = "date"    // 日期，保存为unix毫秒时间戳，支持范围查询
+)
+
+// 索引的字段定义
+type Field struct {
+	Name     string `yaml:"name" json:"name"`
+	Type     string `yaml:"type" json:"type"`         // keyword、text、numeric、date
+	Index    *bool  `yaml:"index" json:"index"`       // 是否写入倒排索引，默认是
+	Store    *bool  `yaml:"store" json:"store"`       // 是否在正排索引中保存原始文本，只对text有效，默认是
+	Required bool   `yaml:"required" json:"required"` // 文档中是否必须有该字段
+	Analyzer string `yaml:"analyzer" json:"analyzer"` // text使用的分析器，为空时使用analysis中的配置
+}
+
+// 是否写入倒排索引
+func (f *Field) Indexed() bool {
+	return f.Index == nil || *f.Index
+}
+
+// 是否保存原始文本
+func (f *Field) Stored() bool {
+	return f.Store == nil || *f.Store
+}
+
+// 索引的schema，描述有哪些字段以及BitsFeature中每一位的含义
+type Schema struct {
+	Name     string   `yaml:"name" json:"name"`
+	Strict   bool     `yaml:"strict" json:"strict"`     // 为true时不允许出现未定义的字段
+	Fields   []Field  `yaml:"fields" json:"fields"`     // 字段定义
+	Features []string `yaml:"features" json:"features"` // 特征名称，依次对应BitsFeature的第1、2、3...位
+
+	fields   map[string]*Field
+	features map[string]int // 特征名称到bit下标
+}
+
+// 从YAML或JSON文件加载schema，JSON是YAML的子集，统一按YAML解析
+func Load(path string) (*Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("load schema %s failed: %w", path, err)
+	}
+	return s, nil
+}
+
+// 解析YAML或JSON格式的schema
+func Parse(data []byte) (*Schema, error) {
+	s := new(Schema)
+	if err := yaml.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+	if err := s.Init(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// 检查定义并建立字段和特征的索引，直接构造Schema时需要先调用
+func (s *Schema) Init() error {
+	s.fields = make(map[string]*Field, len(s.Fields))
+	for i := range s.Fields {
+		field := &s.Fields[i]
+		if len(field.Name) == 0 {
+			return fmt.Errorf("field name is empty")
+		}
+		if _, exist := s.fields[field.Name]; exist {
+			return fmt.Errorf("duplicate field %s", field.Name)
+		}
+		switch field.Type {
+		case KEYWORD, TEXT, NUMERIC, DATE:
+		default:
+			return fmt.Errorf("unknown type %s of field %s", field.Type, field.Name)
+		}
+		if len(field.Analyzer) > 0 && field.Type != TEXT {
+			return fmt.Errorf("field %s of type %s can not have analyzer", field.Name, field.Type)
+		}
+		s.fields[field.Name] = field
+	}
+	s.features = make(map[string]int, len(s.Features))
+	for i, name := range s.Features {
+		if len(name) == 0 {
+			return fmt.Errorf("feature name is empty")
+		}
+		if _, exist := s.features[name]; exist {
+			return fmt.Errorf("duplicate feature %s", name)
+		}
+		s.features[name] = i + 1 // Bitmap的下标从1开始
+	}
+	return nil
+}
+
+// 返回字段定义，未定义时返回nil
+func (s *Schema) Field(name string) *Field {
+	return s.fields[name]
+}
+
+// text字段使用的分析器，field到分析器名称
+func (s *Schema) Analyzers() map[string]string {
+	analyzers := make(map[string]string)
+	for _, field := range s.Fields {
+		if len(field.Analyzer) > 0 {
+			analyzers[field.Name] = field.Analyzer
+		}
+	}
+	return analyzers
+}
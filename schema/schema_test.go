@@ -0,0 +1,188 @@
+package schema
+
+import (
+	"Research/types/doc"
+	"Research/util"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const testSchema = `
+name: products
+strict: true
+fields:
+  - name: title
+    type: text
+    analyzer: chinese
+  - name: brand
+    type: keyword
+    required: true
+  - name: price
+    type: numeric
+  - name: created
+    type: date
+    index: false
+  - name: desc
+    type: text
+    store: false
+features: [on_sale, new, hot]
+`
+
+func mustParse(t *testing.T, data string) *Schema {
+	t.Helper()
+	s, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestParse(t *testing.T) {
+	s := mustParse(t, testSchema)
+	if s.Name != "products" || !s.Strict || len(s.Fields) != 5 {
+		t.Fatalf("got name %s strict %v fields %d", s.Name, s.Strict, len(s.Fields))
+	}
+	if f := s.Field("created"); f == nil || f.Type != DATE || f.Indexed() || !f.Stored() {
+		t.Errorf("field created: %+v", f)
+	}
+	if f := s.Field("desc"); f == nil || f.Stored() || !f.Indexed() {
+		t.Errorf("field desc: %+v", f)
+	}
+	if s.Field("missing") != nil {
+		t.Error("undefined field should be nil")
+	}
+	if analyzers := s.Analyzers(); len(analyzers) != 1 || analyzers["title"] != "chinese" {
+		t.Errorf("analyzers: %v", analyzers)
+	}
+}
+
+func TestLoadJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schema.json")
+	data := `{"name": "books", "fields": [{"name": "isbn", "type": "keyword"}], "features": ["ebook"]}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Name != "books" || s.Field("isbn") == nil || len(s.Features) != 1 {
+		t.Errorf("got %+v", s)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	cases := map[string]string{
+		"fields: [{name: a, type: blob}]":                           "unknown type",
+		"fields: [{name: a, type: keyword}, {name: a, type: text}]": "duplicate field",
+		"fields: [{type: keyword}]":                                 "field name is empty",
+		"fields: [{name: a, type: keyword, analyzer: chinese}]":     "can not have analyzer",
+		"features: [a, a]":                                          "duplicate feature",
+	}
+	for data, want := range cases {
+		if _, err := Parse([]byte(data)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: got %v, want %q", data, err, want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	s := mustParse(t, testSchema)
+	valid := &doc.Document{
+		Keywords: []*doc.KeyWord{{Field: "brand", Word: "acme"}, {Field: "title", Word: "phone"}},
+		Numerics: []*doc.NumericField{{Field: "price", Value: 9}, {Field: "created", Value: 1}},
+		Texts:    []*doc.TextField{{Field: "title", Text: "a phone"}},
+	}
+	if err := s.Validate(valid); err != nil {
+		t.Errorf("valid doc: %s", err)
+	}
+	cases := map[string]*doc.Document{
+		"required field brand is missing": {Numerics: []*doc.NumericField{{Field: "price", Value: 1}}},
+		"field brand is keyword": {
+			Keywords: []*doc.KeyWord{{Field: "brand", Word: "acme"}},
+			Texts:    []*doc.TextField{{Field: "brand", Text: "acme"}},
+		},
+		"field price is numeric":     {Keywords: []*doc.KeyWord{{Field: "brand", Word: "acme"}, {Field: "price", Word: "9"}}},
+		"field color is not defined": {Keywords: []*doc.KeyWord{{Field: "brand", Word: "acme"}, {Field: "color", Word: "red"}}},
+	}
+	for want, d := range cases {
+		if err := s.Validate(d); err == nil || err.Error() != want {
+			t.Errorf("got %v, want %q", err, want)
+		}
+	}
+
+	// 非strict时允许未定义的字段
+	s.Strict = false
+	if err := s.Validate(cases["field color is not defined"]); err != nil {
+		t.Errorf("not strict: %s", err)
+	}
+}
+
+func TestIndexedAndDropUnstored(t *testing.T) {
+	s := mustParse(t, testSchema)
+	d := &doc.Document{
+		Id:       "a",
+		Keywords: []*doc.KeyWord{{Field: "brand", Word: "acme"}},
+		Numerics: []*doc.NumericField{{Field: "price", Value: 9}, {Field: "created", Value: 1}},
+		Texts:    []*doc.TextField{{Field: "title", Text: "phone"}, {Field: "desc", Text: "long text"}},
+	}
+	indexed := s.Indexed(d)
+	if len(indexed.Numerics) != 1 || indexed.Numerics[0].Field != "price" || len(indexed.Keywords) != 1 {
+		t.Errorf("indexed numerics: %d", len(indexed.Numerics))
+	}
+	if len(d.Numerics) != 2 {
+		t.Error("Indexed should not modify the doc")
+	}
+	plain := &doc.Document{Keywords: []*doc.KeyWord{{Field: "brand", Word: "acme"}}}
+	if s.Indexed(plain) != plain {
+		t.Error("doc without unindexed fields should be returned as is")
+	}
+
+	s.DropUnstored(d)
+	if len(d.Texts) != 1 || d.Texts[0].Field != "title" {
+		t.Errorf("texts after drop: %d", len(d.Texts))
+	}
+}
+
+func TestFeatures(t *testing.T) {
+	s := mustParse(t, testSchema)
+	flags, err := s.Flags("on_sale", "hot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := s.FeatureNames(flags); !slices.Equal(names, []string{"on_sale", "hot"}) {
+		t.Errorf("feature names: %v", names)
+	}
+	if _, err = s.Flags("unknown"); err == nil {
+		t.Error("unknown feature: want error")
+	}
+
+	on, off, or, err := s.SearchFlags([]string{"on_sale"}, nil, []string{"new", "hot"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if off != nil || len(or) != 1 || !slices.Equal(s.FeatureNames(on), []string{"on_sale"}) || !slices.Equal(s.FeatureNames(or[0]), []string{"new", "hot"}) {
+		t.Errorf("search flags: on %v or %d", s.FeatureNames(on), len(or))
+	}
+
+	// 容量不同的Bitmap转成统一的容量
+	bitmap := util.NewBitmap(64)
+	bitmap.SetBit(2)
+	normalized, err := s.NormalizeFeatures(bitmap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if normalized.Cap() != flags.Cap() || !slices.Equal(s.FeatureNames(normalized), []string{"new"}) {
+		t.Errorf("normalized cap %d names %v", normalized.Cap(), s.FeatureNames(normalized))
+	}
+	bitmap.SetBit(10)
+	if _, err = s.NormalizeFeatures(bitmap); err == nil {
+		t.Error("undefined feature bit: want error")
+	}
+	if err = s.Validate(&doc.Document{Keywords: []*doc.KeyWord{{Field: "brand", Word: "a"}}, BitsFeature: bitmap}); err == nil {
+		t.Error("doc with undefined feature bit: want error")
+	}
+}
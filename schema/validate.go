@@ -0,0 +1,86 @@
+package schema
+
+import (
+	"Research/types/doc"
+	"fmt"
+)
+
+// 检查文档是否符合schema
+func (s *Schema) Validate(d *doc.Document) error {
+	present := make(map[string]struct{})
+	check := func(field string, types ...string) error {
+		present[field] = struct{}{}
+		def := s.fields[field]
+		if def == nil {
+			if s.Strict {
+				return fmt.Errorf("field %s is not defined", field)
+			}
+			return nil
+		}
+		for _, t := range types {
+			if def.Type == t {
+				return nil
+			}
+		}
+		return fmt.Errorf("field %s is %s", field, def.Type)
+	}
+	for _, keyword := range d.Keywords {
+		if err := check(keyword.Field, KEYWORD, TEXT); err != nil {
+			return err
+		}
+	}
+	for _, numeric := range d.Numerics {
+		if err := check(numeric.Field, NUMERIC, DATE); err != nil {
+			return err
+		}
+	}
+	for _, text := range d.Texts {
+		if err := check(text.Field, TEXT); err != nil {
+			return err
+		}
+	}
+	for _, field := range s.Fields {
+		if _, exist := present[field.Name]; field.Required && !exist {
+			return fmt.Errorf("required field %s is missing", field.Name)
+		}
+	}
+	if _, err := s.NormalizeFeatures(d.BitsFeature); err != nil {
+		return err
+	}
+	return nil
+}
+
+// 去掉不需要保存的原始文本，在分析完文本之后调用
+func (s *Schema) DropUnstored(d *doc.Document) {
+	texts := d.Texts[:0]
+	for _, text := range d.Texts {
+		if def := s.fields[text.Field]; def == nil || def.Stored() {
+			texts = append(texts, text)
+		}
+	}
+	d.Texts = texts
+}
+
+// 返回需要写入倒排索引的文档，去掉了不需要索引的字段，没有需要去掉的字段时返回d本身
+func (s *Schema) Indexed(d *doc.Document) *doc.Document {
+	indexed := func(field string) bool {
+		def := s.fields[field]
+		return def == nil || def.Indexed()
+	}
+	keywords := make([]*doc.KeyWord, 0, len(d.Keywords))
+	for _, keyword := range d.Keywords {
+		if indexed(keyword.Field) {
+			keywords = append(keywords, keyword)
+		}
+	}
+	numerics := make([]*doc.NumericField, 0, len(d.Numerics))
+	for _, numeric := range d.Numerics {
+		if indexed(numeric.Field) {
+			numerics = append(numerics, numeric)
+		}
+	}
+	if len(keywords) == len(d.Keywords) && len(numerics) == len(d.Numerics) {
+		return d
+	}
+	return &doc.Document{Id: d.Id, IntId: d.IntId, BitsFeature: d.BitsFeature, Keywords: keywords, Numerics: numerics}
+}
@@ -1,5 +1,7 @@
 package doc
 
+import "time"
+
 //type KeyWord struct {
 //	Field string
 //	Word  string
@@ -20,3 +22,13 @@ func (kw *KeyWord) ToString() string {
 		return ""
 	}
 }
+
+// 创建数值字段
+func NewNumericField(field string, value float64) *NumericField {
+	return &NumericField{Field: field, Value: value}
+}
+
+// 创建日期字段，保存为unix毫秒时间戳
+func NewDateField(field string, t time.Time) *NumericField {
+	return &NumericField{Field: field, Value: float64(t.UnixMilli())}
+}
@@ -26,8 +26,9 @@ type KeyWord struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Field string `protobuf:"bytes,1,opt,name=Field,proto3" json:"Field,omitempty"`
-	Word  string `protobuf:"bytes,2,opt,name=Word,proto3" json:"Word,omitempty"`
+	Field     string   `protobuf:"bytes,1,opt,name=Field,proto3" json:"Field,omitempty"`
+	Word      string   `protobuf:"bytes,2,opt,name=Word,proto3" json:"Word,omitempty"`
+	Positions []uint32 `protobuf:"varint,3,rep,packed,name=Positions,proto3" json:"Positions,omitempty"` //关键词在Field中出现的位置，为空时按Keywords中同一Field的顺序编号
 }
 
 func (x *KeyWord) Reset() {
@@ -76,22 +77,146 @@ func (x *KeyWord) GetWord() string {
 	return ""
 }
 
+func (x *KeyWord) GetPositions() []uint32 {
+	if x != nil {
+		return x.Positions
+	}
+	return nil
+}
+
+// 数值字段，可以按范围查询
+type NumericField struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Field string  `protobuf:"bytes,1,opt,name=Field,proto3" json:"Field,omitempty"`
+	Value float64 `protobuf:"fixed64,2,opt,name=Value,proto3" json:"Value,omitempty"` //日期保存为unix毫秒时间戳
+}
+
+func (x *NumericField) Reset() {
+	*x = NumericField{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_doc_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NumericField) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NumericField) ProtoMessage() {}
+
+func (x *NumericField) ProtoReflect() protoreflect.Message {
+	mi := &file_doc_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NumericField.ProtoReflect.Descriptor instead.
+func (*NumericField) Descriptor() ([]byte, []int) {
+	return file_doc_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *NumericField) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *NumericField) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+// 原始文本字段，添加文档时由分析器分词生成Keywords
+type TextField struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Field string `protobuf:"bytes,1,opt,name=Field,proto3" json:"Field,omitempty"`
+	Text  string `protobuf:"bytes,2,opt,name=Text,proto3" json:"Text,omitempty"`
+}
+
+func (x *TextField) Reset() {
+	*x = TextField{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_doc_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TextField) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TextField) ProtoMessage() {}
+
+func (x *TextField) ProtoReflect() protoreflect.Message {
+	mi := &file_doc_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TextField.ProtoReflect.Descriptor instead.
+func (*TextField) Descriptor() ([]byte, []int) {
+	return file_doc_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TextField) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *TextField) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
 type Document struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id          string       `protobuf:"bytes,1,opt,name=Id,proto3" json:"Id,omitempty"`                   //业务使用的唯一Id，索引上此Id不会重复
-	IntId       uint64       `protobuf:"varint,2,opt,name=IntId,proto3" json:"IntId,omitempty"`            //倒排索引上使用的文档id(业务侧不用管这个字段)
-	BitsFeature *util.Bitmap `protobuf:"bytes,3,opt,name=BitsFeature,proto3" json:"BitsFeature,omitempty"` //每个bit都表示某种特征的取值
-	Keywords    []*KeyWord   `protobuf:"bytes,4,rep,name=Keywords,proto3" json:"Keywords,omitempty"`       //倒排索引的key
-	Bytes       []byte       `protobuf:"bytes,5,opt,name=Bytes,proto3" json:"Bytes,omitempty"`             //业务实体序列化之后的结果
+	Id          string          `protobuf:"bytes,1,opt,name=Id,proto3" json:"Id,omitempty"`                   //业务使用的唯一Id，索引上此Id不会重复
+	IntId       uint64          `protobuf:"varint,2,opt,name=IntId,proto3" json:"IntId,omitempty"`            //倒排索引上使用的文档id(业务侧不用管这个字段)
+	BitsFeature *util.Bitmap    `protobuf:"bytes,3,opt,name=BitsFeature,proto3" json:"BitsFeature,omitempty"` //每个bit都表示某种特征的取值
+	Keywords    []*KeyWord      `protobuf:"bytes,4,rep,name=Keywords,proto3" json:"Keywords,omitempty"`       //倒排索引的key
+	Bytes       []byte          `protobuf:"bytes,5,opt,name=Bytes,proto3" json:"Bytes,omitempty"`             //业务实体序列化之后的结果
+	Score       float64         `protobuf:"fixed64,6,opt,name=Score,proto3" json:"Score,omitempty"`           //检索时计算的相关性得分，只在检索结果中有效
+	Numerics    []*NumericField `protobuf:"bytes,7,rep,name=Numerics,proto3" json:"Numerics,omitempty"`       //数值和日期字段
+	Texts       []*TextField    `protobuf:"bytes,8,rep,name=Texts,proto3" json:"Texts,omitempty"`             //需要分词的原始文本
+	Version     uint64          `protobuf:"varint,9,opt,name=Version,proto3" json:"Version,omitempty"`        //文档版本号，由索引维护，第一次写入为1，之后每次修改加1
+	ExpireAt    int64           `protobuf:"varint,10,opt,name=ExpireAt,proto3" json:"ExpireAt,omitempty"`     //过期时间，unix毫秒时间戳，0表示不过期，过期后检索不到并被后台删除
 }
 
 func (x *Document) Reset() {
 	*x = Document{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_doc_proto_msgTypes[1]
+		mi := &file_doc_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -104,7 +229,7 @@ func (x *Document) String() string {
 func (*Document) ProtoMessage() {}
 
 func (x *Document) ProtoReflect() protoreflect.Message {
-	mi := &file_doc_proto_msgTypes[1]
+	mi := &file_doc_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -117,7 +242,7 @@ func (x *Document) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Document.ProtoReflect.Descriptor instead.
 func (*Document) Descriptor() ([]byte, []int) {
-	return file_doc_proto_rawDescGZIP(), []int{1}
+	return file_doc_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *Document) GetId() string {
@@ -155,26 +280,82 @@ func (x *Document) GetBytes() []byte {
 	return nil
 }
 
+func (x *Document) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Document) GetNumerics() []*NumericField {
+	if x != nil {
+		return x.Numerics
+	}
+	return nil
+}
+
+func (x *Document) GetTexts() []*TextField {
+	if x != nil {
+		return x.Texts
+	}
+	return nil
+}
+
+func (x *Document) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Document) GetExpireAt() int64 {
+	if x != nil {
+		return x.ExpireAt
+	}
+	return 0
+}
+
 var File_doc_proto protoreflect.FileDescriptor
 
 var file_doc_proto_rawDesc = []byte{
 	0x0a, 0x09, 0x64, 0x6f, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x74, 0x79, 0x70,
 	0x65, 0x73, 0x1a, 0x11, 0x75, 0x74, 0x69, 0x6c, 0x2f, 0x62, 0x69, 0x74, 0x6d, 0x61, 0x70, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x33, 0x0a, 0x07, 0x4b, 0x65, 0x79, 0x57, 0x6f, 0x72, 0x64,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x51, 0x0a, 0x07, 0x4b, 0x65, 0x79, 0x57, 0x6f, 0x72, 0x64,
 	0x12, 0x14, 0x0a, 0x05, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
 	0x05, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x57, 0x6f, 0x72, 0x64, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x57, 0x6f, 0x72, 0x64, 0x22, 0xa2, 0x01, 0x0a, 0x08, 0x44,
-	0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x49, 0x6e, 0x74, 0x49, 0x64,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x49, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x2e, 0x0a,
-	0x0b, 0x42, 0x69, 0x74, 0x73, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x75, 0x74, 0x69, 0x6c, 0x2e, 0x42, 0x69, 0x74, 0x6d, 0x61, 0x70,
-	0x52, 0x0b, 0x42, 0x69, 0x74, 0x73, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x2a, 0x0a,
-	0x08, 0x4b, 0x65, 0x79, 0x77, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x0e, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x4b, 0x65, 0x79, 0x57, 0x6f, 0x72, 0x64, 0x52,
-	0x08, 0x4b, 0x65, 0x79, 0x77, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x42, 0x79, 0x74,
-	0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x42, 0x79, 0x74, 0x65, 0x73, 0x42,
-	0x07, 0x5a, 0x05, 0x2e, 0x2f, 0x64, 0x6f, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x57, 0x6f, 0x72, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x50, 0x6f,
+	0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x09, 0x50,
+	0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x3a, 0x0a, 0x0c, 0x4e, 0x75, 0x6d, 0x65,
+	0x72, 0x69, 0x63, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x46, 0x69, 0x65, 0x6c,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x14,
+	0x0a, 0x05, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x56,
+	0x61, 0x6c, 0x75, 0x65, 0x22, 0x35, 0x0a, 0x09, 0x54, 0x65, 0x78, 0x74, 0x46, 0x69, 0x65, 0x6c,
+	0x64, 0x12, 0x14, 0x0a, 0x05, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x54, 0x65, 0x78, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x54, 0x65, 0x78, 0x74, 0x22, 0xc7, 0x02, 0x0a, 0x08,
+	0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x49, 0x6e, 0x74, 0x49,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x49, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x2e,
+	0x0a, 0x0b, 0x42, 0x69, 0x74, 0x73, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x75, 0x74, 0x69, 0x6c, 0x2e, 0x42, 0x69, 0x74, 0x6d, 0x61,
+	0x70, 0x52, 0x0b, 0x42, 0x69, 0x74, 0x73, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x2a,
+	0x0a, 0x08, 0x4b, 0x65, 0x79, 0x77, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0e, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x4b, 0x65, 0x79, 0x57, 0x6f, 0x72, 0x64,
+	0x52, 0x08, 0x4b, 0x65, 0x79, 0x77, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x42, 0x79,
+	0x74, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x42, 0x79, 0x74, 0x65, 0x73,
+	0x12, 0x14, 0x0a, 0x05, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x05, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x2f, 0x0a, 0x08, 0x4e, 0x75, 0x6d, 0x65, 0x72, 0x69,
+	0x63, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73,
+	0x2e, 0x4e, 0x75, 0x6d, 0x65, 0x72, 0x69, 0x63, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x52, 0x08, 0x4e,
+	0x75, 0x6d, 0x65, 0x72, 0x69, 0x63, 0x73, 0x12, 0x26, 0x0a, 0x05, 0x54, 0x65, 0x78, 0x74, 0x73,
+	0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x54,
+	0x65, 0x78, 0x74, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x52, 0x05, 0x54, 0x65, 0x78, 0x74, 0x73, 0x12,
+	0x18, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x45, 0x78, 0x70,
+	0x69, 0x72, 0x65, 0x41, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x45, 0x78, 0x70,
+	0x69, 0x72, 0x65, 0x41, 0x74, 0x42, 0x14, 0x5a, 0x12, 0x52, 0x65, 0x73, 0x65, 0x61, 0x72, 0x63,
+	0x68, 0x2f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2f, 0x64, 0x6f, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
 }
 
 var (
@@ -189,20 +370,24 @@ func file_doc_proto_rawDescGZIP() []byte {
 	return file_doc_proto_rawDescData
 }
 
-var file_doc_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_doc_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_doc_proto_goTypes = []interface{}{
-	(*KeyWord)(nil),     // 0: types.KeyWord
-	(*Document)(nil),    // 1: types.Document
-	(*util.Bitmap)(nil), // 2: util.Bitmap
+	(*KeyWord)(nil),      // 0: types.KeyWord
+	(*NumericField)(nil), // 1: types.NumericField
+	(*TextField)(nil),    // 2: types.TextField
+	(*Document)(nil),     // 3: types.Document
+	(*util.Bitmap)(nil),  // 4: util.Bitmap
 }
 var file_doc_proto_depIdxs = []int32{
-	2, // 0: types.Document.BitsFeature:type_name -> util.Bitmap
+	4, // 0: types.Document.BitsFeature:type_name -> util.Bitmap
 	0, // 1: types.Document.Keywords:type_name -> types.KeyWord
-	2, // [2:2] is the sub-list for method output_type
-	2, // [2:2] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	1, // 2: types.Document.Numerics:type_name -> types.NumericField
+	2, // 3: types.Document.Texts:type_name -> types.TextField
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_doc_proto_init() }
@@ -224,6 +409,30 @@ func file_doc_proto_init() {
 			}
 		}
 		file_doc_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NumericField); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_doc_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TextField); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_doc_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Document); i {
 			case 0:
 				return &v.state
@@ -242,7 +451,7 @@ func file_doc_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_doc_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -23,18 +23,125 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type Aggregation_AggregationType int32
+
+const (
+	Aggregation_TERMS     Aggregation_AggregationType = 0 //按关键词统计文档数
+	Aggregation_HISTOGRAM Aggregation_AggregationType = 1 //按数值区间统计个数
+	Aggregation_STATS     Aggregation_AggregationType = 2 //数值的个数、最小值、最大值、和、平均值
+)
+
+// Enum value maps for Aggregation_AggregationType.
+var (
+	Aggregation_AggregationType_name = map[int32]string{
+		0: "TERMS",
+		1: "HISTOGRAM",
+		2: "STATS",
+	}
+	Aggregation_AggregationType_value = map[string]int32{
+		"TERMS":     0,
+		"HISTOGRAM": 1,
+		"STATS":     2,
+	}
+)
+
+func (x Aggregation_AggregationType) Enum() *Aggregation_AggregationType {
+	p := new(Aggregation_AggregationType)
+	*p = x
+	return p
+}
+
+func (x Aggregation_AggregationType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Aggregation_AggregationType) Descriptor() protoreflect.EnumDescriptor {
+	return file_index_proto_enumTypes[0].Descriptor()
+}
+
+func (Aggregation_AggregationType) Type() protoreflect.EnumType {
+	return &file_index_proto_enumTypes[0]
+}
+
+func (x Aggregation_AggregationType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Aggregation_AggregationType.Descriptor instead.
+func (Aggregation_AggregationType) EnumDescriptor() ([]byte, []int) {
+	return file_index_proto_rawDescGZIP(), []int{4, 0}
+}
+
+// 写入条件，不满足时不写入，返回版本冲突
+type Condition struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	IfVersion uint64 `protobuf:"varint,1,opt,name=IfVersion,proto3" json:"IfVersion,omitempty"` //文档当前版本等于IfVersion时才写入，0表示不检查
+	IfAbsent  bool   `protobuf:"varint,2,opt,name=IfAbsent,proto3" json:"IfAbsent,omitempty"`   //文档不存在时才写入，只对添加有效
+}
+
+func (x *Condition) Reset() {
+	*x = Condition{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_index_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Condition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Condition) ProtoMessage() {}
+
+func (x *Condition) ProtoReflect() protoreflect.Message {
+	mi := &file_index_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Condition.ProtoReflect.Descriptor instead.
+func (*Condition) Descriptor() ([]byte, []int) {
+	return file_index_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Condition) GetIfVersion() uint64 {
+	if x != nil {
+		return x.IfVersion
+	}
+	return 0
+}
+
+func (x *Condition) GetIfAbsent() bool {
+	if x != nil {
+		return x.IfAbsent
+	}
+	return false
+}
+
 type DocId struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	DocId string `protobuf:"bytes,1,opt,name=DocId,proto3" json:"DocId,omitempty"`
+	DocId     string     `protobuf:"bytes,1,opt,name=DocId,proto3" json:"DocId,omitempty"`
+	Index     string     `protobuf:"bytes,2,opt,name=Index,proto3" json:"Index,omitempty"`         //索引名称，为空表示默认索引
+	Condition *Condition `protobuf:"bytes,3,opt,name=Condition,proto3" json:"Condition,omitempty"` //删除的条件，为空时直接删除
 }
 
 func (x *DocId) Reset() {
 	*x = DocId{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_index_proto_msgTypes[0]
+		mi := &file_index_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -47,7 +154,7 @@ func (x *DocId) String() string {
 func (*DocId) ProtoMessage() {}
 
 func (x *DocId) ProtoReflect() protoreflect.Message {
-	mi := &file_index_proto_msgTypes[0]
+	mi := &file_index_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -60,7 +167,7 @@ func (x *DocId) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DocId.ProtoReflect.Descriptor instead.
 func (*DocId) Descriptor() ([]byte, []int) {
-	return file_index_proto_rawDescGZIP(), []int{0}
+	return file_index_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *DocId) GetDocId() string {
@@ -70,18 +177,97 @@ func (x *DocId) GetDocId() string {
 	return ""
 }
 
+func (x *DocId) GetIndex() string {
+	if x != nil {
+		return x.Index
+	}
+	return ""
+}
+
+func (x *DocId) GetCondition() *Condition {
+	if x != nil {
+		return x.Condition
+	}
+	return nil
+}
+
+type AddDocRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Index     string        `protobuf:"bytes,1,opt,name=Index,proto3" json:"Index,omitempty"` //索引名称，为空表示默认索引
+	Doc       *doc.Document `protobuf:"bytes,2,opt,name=Doc,proto3" json:"Doc,omitempty"`
+	Condition *Condition    `protobuf:"bytes,3,opt,name=Condition,proto3" json:"Condition,omitempty"` //添加的条件，为空时直接添加
+}
+
+func (x *AddDocRequest) Reset() {
+	*x = AddDocRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_index_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddDocRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddDocRequest) ProtoMessage() {}
+
+func (x *AddDocRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_index_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddDocRequest.ProtoReflect.Descriptor instead.
+func (*AddDocRequest) Descriptor() ([]byte, []int) {
+	return file_index_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AddDocRequest) GetIndex() string {
+	if x != nil {
+		return x.Index
+	}
+	return ""
+}
+
+func (x *AddDocRequest) GetDoc() *doc.Document {
+	if x != nil {
+		return x.Doc
+	}
+	return nil
+}
+
+func (x *AddDocRequest) GetCondition() *Condition {
+	if x != nil {
+		return x.Condition
+	}
+	return nil
+}
+
 type AffectedCount struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Count int32 `protobuf:"varint,1,opt,name=Count,proto3" json:"Count,omitempty"`
+	Count    int32  `protobuf:"varint,1,opt,name=Count,proto3" json:"Count,omitempty"`
+	Conflict bool   `protobuf:"varint,2,opt,name=Conflict,proto3" json:"Conflict,omitempty"` //写入条件不满足，没有写入
+	Version  uint64 `protobuf:"varint,3,opt,name=Version,proto3" json:"Version,omitempty"`   //添加和更新后文档的版本，冲突时是文档当前的版本，0表示不存在
 }
 
 func (x *AffectedCount) Reset() {
 	*x = AffectedCount{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_index_proto_msgTypes[1]
+		mi := &file_index_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -94,7 +280,7 @@ func (x *AffectedCount) String() string {
 func (*AffectedCount) ProtoMessage() {}
 
 func (x *AffectedCount) ProtoReflect() protoreflect.Message {
-	mi := &file_index_proto_msgTypes[1]
+	mi := &file_index_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -107,7 +293,7 @@ func (x *AffectedCount) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AffectedCount.ProtoReflect.Descriptor instead.
 func (*AffectedCount) Descriptor() ([]byte, []int) {
-	return file_index_proto_rawDescGZIP(), []int{1}
+	return file_index_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *AffectedCount) GetCount() int32 {
@@ -117,34 +303,49 @@ func (x *AffectedCount) GetCount() int32 {
 	return 0
 }
 
-type SearchRequest struct {
+func (x *AffectedCount) GetConflict() bool {
+	if x != nil {
+		return x.Conflict
+	}
+	return false
+}
+
+func (x *AffectedCount) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type Aggregation struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Query   *term_query.TermQuery `protobuf:"bytes,1,opt,name=Query,proto3" json:"Query,omitempty"` //TermQuery类型引用自term_query.proto
-	OnFlag  *util.Bitmap          `protobuf:"bytes,2,opt,name=OnFlag,proto3" json:"OnFlag,omitempty"`
-	OffFlag *util.Bitmap          `protobuf:"bytes,3,opt,name=OffFlag,proto3" json:"OffFlag,omitempty"`
-	OrFlags []*util.Bitmap        `protobuf:"bytes,4,rep,name=OrFlags,proto3" json:"OrFlags,omitempty"`
+	Type     Aggregation_AggregationType `protobuf:"varint,1,opt,name=Type,proto3,enum=index_service.Aggregation_AggregationType" json:"Type,omitempty"`
+	Name     string                      `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"` //结果名称，为空时使用Field
+	Field    string                      `protobuf:"bytes,3,opt,name=Field,proto3" json:"Field,omitempty"`
+	Size     int32                       `protobuf:"varint,4,opt,name=Size,proto3" json:"Size,omitempty"`          //terms只返回文档数最多的Size个词，0表示返回全部
+	Interval float64                     `protobuf:"fixed64,5,opt,name=Interval,proto3" json:"Interval,omitempty"` //histogram的区间宽度
 }
 
-func (x *SearchRequest) Reset() {
-	*x = SearchRequest{}
+func (x *Aggregation) Reset() {
+	*x = Aggregation{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_index_proto_msgTypes[2]
+		mi := &file_index_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SearchRequest) String() string {
+func (x *Aggregation) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SearchRequest) ProtoMessage() {}
+func (*Aggregation) ProtoMessage() {}
 
-func (x *SearchRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_index_proto_msgTypes[2]
+func (x *Aggregation) ProtoReflect() protoreflect.Message {
+	mi := &file_index_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -155,64 +356,73 @@ func (x *SearchRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SearchRequest.ProtoReflect.Descriptor instead.
-func (*SearchRequest) Descriptor() ([]byte, []int) {
-	return file_index_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use Aggregation.ProtoReflect.Descriptor instead.
+func (*Aggregation) Descriptor() ([]byte, []int) {
+	return file_index_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *SearchRequest) GetQuery() *term_query.TermQuery {
+func (x *Aggregation) GetType() Aggregation_AggregationType {
 	if x != nil {
-		return x.Query
+		return x.Type
 	}
-	return nil
+	return Aggregation_TERMS
 }
 
-func (x *SearchRequest) GetOnFlag() *util.Bitmap {
+func (x *Aggregation) GetName() string {
 	if x != nil {
-		return x.OnFlag
+		return x.Name
 	}
-	return nil
+	return ""
 }
 
-func (x *SearchRequest) GetOffFlag() *util.Bitmap {
+func (x *Aggregation) GetField() string {
 	if x != nil {
-		return x.OffFlag
+		return x.Field
 	}
-	return nil
+	return ""
 }
 
-func (x *SearchRequest) GetOrFlags() []*util.Bitmap {
+func (x *Aggregation) GetSize() int32 {
 	if x != nil {
-		return x.OrFlags
+		return x.Size
 	}
-	return nil
+	return 0
 }
 
-type SearchResult struct {
+func (x *Aggregation) GetInterval() float64 {
+	if x != nil {
+		return x.Interval
+	}
+	return 0
+}
+
+type Bucket struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Results []*doc.Document `protobuf:"bytes,1,rep,name=Results,proto3" json:"Results,omitempty"`
+	Key   string  `protobuf:"bytes,1,opt,name=Key,proto3" json:"Key,omitempty"`     //terms是关键词，histogram是区间下界
+	From  float64 `protobuf:"fixed64,2,opt,name=From,proto3" json:"From,omitempty"` //histogram的区间下界
+	Count int64   `protobuf:"varint,3,opt,name=Count,proto3" json:"Count,omitempty"`
 }
 
-func (x *SearchResult) Reset() {
-	*x = SearchResult{}
+func (x *Bucket) Reset() {
+	*x = Bucket{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_index_proto_msgTypes[3]
+		mi := &file_index_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SearchResult) String() string {
+func (x *Bucket) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SearchResult) ProtoMessage() {}
+func (*Bucket) ProtoMessage() {}
 
-func (x *SearchResult) ProtoReflect() protoreflect.Message {
-	mi := &file_index_proto_msgTypes[3]
+func (x *Bucket) ProtoReflect() protoreflect.Message {
+	mi := &file_index_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -223,41 +433,63 @@ func (x *SearchResult) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SearchResult.ProtoReflect.Descriptor instead.
-func (*SearchResult) Descriptor() ([]byte, []int) {
-	return file_index_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use Bucket.ProtoReflect.Descriptor instead.
+func (*Bucket) Descriptor() ([]byte, []int) {
+	return file_index_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *SearchResult) GetResults() []*doc.Document {
+func (x *Bucket) GetKey() string {
 	if x != nil {
-		return x.Results
+		return x.Key
 	}
-	return nil
+	return ""
 }
 
-type CountRequest struct {
+func (x *Bucket) GetFrom() float64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *Bucket) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type AggregationResult struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Name    string    `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	Buckets []*Bucket `protobuf:"bytes,2,rep,name=Buckets,proto3" json:"Buckets,omitempty"`
+	Count   int64     `protobuf:"varint,3,opt,name=Count,proto3" json:"Count,omitempty"` //stats的数值个数
+	Min     float64   `protobuf:"fixed64,4,opt,name=Min,proto3" json:"Min,omitempty"`
+	Max     float64   `protobuf:"fixed64,5,opt,name=Max,proto3" json:"Max,omitempty"`
+	Sum     float64   `protobuf:"fixed64,6,opt,name=Sum,proto3" json:"Sum,omitempty"`
+	Avg     float64   `protobuf:"fixed64,7,opt,name=Avg,proto3" json:"Avg,omitempty"`
 }
 
-func (x *CountRequest) Reset() {
-	*x = CountRequest{}
+func (x *AggregationResult) Reset() {
+	*x = AggregationResult{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_index_proto_msgTypes[4]
+		mi := &file_index_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *CountRequest) String() string {
+func (x *AggregationResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CountRequest) ProtoMessage() {}
+func (*AggregationResult) ProtoMessage() {}
 
-func (x *CountRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_index_proto_msgTypes[4]
+func (x *AggregationResult) ProtoReflect() protoreflect.Message {
+	mi := &file_index_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -268,103 +500,1877 @@ func (x *CountRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CountRequest.ProtoReflect.Descriptor instead.
-func (*CountRequest) Descriptor() ([]byte, []int) {
-	return file_index_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use AggregationResult.ProtoReflect.Descriptor instead.
+func (*AggregationResult) Descriptor() ([]byte, []int) {
+	return file_index_proto_rawDescGZIP(), []int{6}
 }
 
-var File_index_proto protoreflect.FileDescriptor
-
-var file_index_proto_rawDesc = []byte{
-	0x0a, 0x0b, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0d, 0x69,
-	0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x1a, 0x0f, 0x74, 0x79,
-	0x70, 0x65, 0x73, 0x2f, 0x64, 0x6f, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x16, 0x74,
-	0x79, 0x70, 0x65, 0x73, 0x2f, 0x74, 0x65, 0x72, 0x6d, 0x5f, 0x71, 0x75, 0x65, 0x72, 0x79, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x11, 0x75, 0x74, 0x69, 0x6c, 0x2f, 0x62, 0x69, 0x74, 0x6d,
-	0x61, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x1d, 0x0a, 0x05, 0x44, 0x6f, 0x63, 0x49,
-	0x64, 0x12, 0x14, 0x0a, 0x05, 0x44, 0x6f, 0x63, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x05, 0x44, 0x6f, 0x63, 0x49, 0x64, 0x22, 0x25, 0x0a, 0x0d, 0x41, 0x66, 0x66, 0x65, 0x63,
-	0x74, 0x65, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x43, 0x6f, 0x75, 0x6e,
-	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0xad,
-	0x01, 0x0a, 0x0d, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x12, 0x26, 0x0a, 0x05, 0x51, 0x75, 0x65, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x10, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x54, 0x65, 0x72, 0x6d, 0x51, 0x75, 0x65, 0x72,
-	0x79, 0x52, 0x05, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12, 0x24, 0x0a, 0x06, 0x4f, 0x6e, 0x46, 0x6c,
-	0x61, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x75, 0x74, 0x69, 0x6c, 0x2e,
-	0x42, 0x69, 0x74, 0x6d, 0x61, 0x70, 0x52, 0x06, 0x4f, 0x6e, 0x46, 0x6c, 0x61, 0x67, 0x12, 0x26,
-	0x0a, 0x07, 0x4f, 0x66, 0x66, 0x46, 0x6c, 0x61, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x0c, 0x2e, 0x75, 0x74, 0x69, 0x6c, 0x2e, 0x42, 0x69, 0x74, 0x6d, 0x61, 0x70, 0x52, 0x07, 0x4f,
-	0x66, 0x66, 0x46, 0x6c, 0x61, 0x67, 0x12, 0x26, 0x0a, 0x07, 0x4f, 0x72, 0x46, 0x6c, 0x61, 0x67,
-	0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x75, 0x74, 0x69, 0x6c, 0x2e, 0x42,
-	0x69, 0x74, 0x6d, 0x61, 0x70, 0x52, 0x07, 0x4f, 0x72, 0x46, 0x6c, 0x61, 0x67, 0x73, 0x22, 0x39,
-	0x0a, 0x0c, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x29,
-	0x0a, 0x07, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x0f, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74,
-	0x52, 0x07, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x22, 0x0e, 0x0a, 0x0c, 0x43, 0x6f, 0x75,
-	0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x32, 0x91, 0x02, 0x0a, 0x0c, 0x49, 0x6e,
-	0x64, 0x65, 0x78, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3f, 0x0a, 0x09, 0x44, 0x65,
-	0x6c, 0x65, 0x74, 0x65, 0x44, 0x6f, 0x63, 0x12, 0x14, 0x2e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f,
-	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x44, 0x6f, 0x63, 0x49, 0x64, 0x1a, 0x1c, 0x2e,
-	0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x41, 0x66,
-	0x66, 0x65, 0x63, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x37, 0x0a, 0x06, 0x41,
-	0x64, 0x64, 0x44, 0x6f, 0x63, 0x12, 0x0f, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x44, 0x6f,
-	0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x1a, 0x1c, 0x2e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x41, 0x66, 0x66, 0x65, 0x63, 0x74, 0x65, 0x64, 0x43,
-	0x6f, 0x75, 0x6e, 0x74, 0x12, 0x43, 0x0a, 0x06, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x12, 0x1c,
-	0x2e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53,
-	0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x69,
-	0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x61,
-	0x72, 0x63, 0x68, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x42, 0x0a, 0x05, 0x43, 0x6f, 0x75,
-	0x6e, 0x74, 0x12, 0x1b, 0x2e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69,
-	0x63, 0x65, 0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x1c, 0x2e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
-	0x41, 0x66, 0x66, 0x65, 0x63, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x42, 0x18, 0x5a,
-	0x16, 0x52, 0x65, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x2f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f,
-	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+func (x *AggregationResult) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
 }
 
-var (
-	file_index_proto_rawDescOnce sync.Once
-	file_index_proto_rawDescData = file_index_proto_rawDesc
-)
+func (x *AggregationResult) GetBuckets() []*Bucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
 
-func file_index_proto_rawDescGZIP() []byte {
-	file_index_proto_rawDescOnce.Do(func() {
-		file_index_proto_rawDescData = protoimpl.X.CompressGZIP(file_index_proto_rawDescData)
-	})
-	return file_index_proto_rawDescData
+func (x *AggregationResult) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
 }
 
-var file_index_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
-var file_index_proto_goTypes = []interface{}{
-	(*DocId)(nil),                // 0: index_service.DocId
-	(*AffectedCount)(nil),        // 1: index_service.AffectedCount
-	(*SearchRequest)(nil),        // 2: index_service.SearchRequest
-	(*SearchResult)(nil),         // 3: index_service.SearchResult
-	(*CountRequest)(nil),         // 4: index_service.CountRequest
-	(*term_query.TermQuery)(nil), // 5: types.TermQuery
-	(*util.Bitmap)(nil),          // 6: util.Bitmap
-	(*doc.Document)(nil),         // 7: types.Document
+func (x *AggregationResult) GetMin() float64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
 }
-var file_index_proto_depIdxs = []int32{
-	5, // 0: index_service.SearchRequest.Query:type_name -> types.TermQuery
-	6, // 1: index_service.SearchRequest.OnFlag:type_name -> util.Bitmap
-	6, // 2: index_service.SearchRequest.OffFlag:type_name -> util.Bitmap
-	6, // 3: index_service.SearchRequest.OrFlags:type_name -> util.Bitmap
-	7, // 4: index_service.SearchResult.Results:type_name -> types.Document
-	0, // 5: index_service.IndexService.DeleteDoc:input_type -> index_service.DocId
-	7, // 6: index_service.IndexService.AddDoc:input_type -> types.Document
-	2, // 7: index_service.IndexService.Search:input_type -> index_service.SearchRequest
-	4, // 8: index_service.IndexService.Count:input_type -> index_service.CountRequest
-	1, // 9: index_service.IndexService.DeleteDoc:output_type -> index_service.AffectedCount
-	1, // 10: index_service.IndexService.AddDoc:output_type -> index_service.AffectedCount
-	3, // 11: index_service.IndexService.Search:output_type -> index_service.SearchResult
-	1, // 12: index_service.IndexService.Count:output_type -> index_service.AffectedCount
-	9, // [9:13] is the sub-list for method output_type
-	5, // [5:9] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+
+func (x *AggregationResult) GetMax() float64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *AggregationResult) GetSum() float64 {
+	if x != nil {
+		return x.Sum
+	}
+	return 0
+}
+
+func (x *AggregationResult) GetAvg() float64 {
+	if x != nil {
+		return x.Avg
+	}
+	return 0
+}
+
+type Highlight struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Fields       []string `protobuf:"bytes,1,rep,name=Fields,proto3" json:"Fields,omitempty"`              //需要高亮的文本字段，为空时高亮所有文本字段
+	PreTag       string   `protobuf:"bytes,2,opt,name=PreTag,proto3" json:"PreTag,omitempty"`              //匹配词前插入的标签，默认<em>
+	PostTag      string   `protobuf:"bytes,3,opt,name=PostTag,proto3" json:"PostTag,omitempty"`            //匹配词后插入的标签，默认</em>
+	FragmentSize int32    `protobuf:"varint,4,opt,name=FragmentSize,proto3" json:"FragmentSize,omitempty"` //片段长度(字符数)，0表示默认100，小于0表示返回整段文本
+	NumFragments int32    `protobuf:"varint,5,opt,name=NumFragments,proto3" json:"NumFragments,omitempty"` //每个字段最多返回的片段数，0表示默认3
+}
+
+func (x *Highlight) Reset() {
+	*x = Highlight{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_index_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Highlight) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Highlight) ProtoMessage() {}
+
+func (x *Highlight) ProtoReflect() protoreflect.Message {
+	mi := &file_index_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Highlight.ProtoReflect.Descriptor instead.
+func (*Highlight) Descriptor() ([]byte, []int) {
+	return file_index_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Highlight) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+func (x *Highlight) GetPreTag() string {
+	if x != nil {
+		return x.PreTag
+	}
+	return ""
+}
+
+func (x *Highlight) GetPostTag() string {
+	if x != nil {
+		return x.PostTag
+	}
+	return ""
+}
+
+func (x *Highlight) GetFragmentSize() int32 {
+	if x != nil {
+		return x.FragmentSize
+	}
+	return 0
+}
+
+func (x *Highlight) GetNumFragments() int32 {
+	if x != nil {
+		return x.NumFragments
+	}
+	return 0
+}
+
+type HighlightField struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Field     string   `protobuf:"bytes,1,opt,name=Field,proto3" json:"Field,omitempty"`
+	Fragments []string `protobuf:"bytes,2,rep,name=Fragments,proto3" json:"Fragments,omitempty"`
+}
+
+func (x *HighlightField) Reset() {
+	*x = HighlightField{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_index_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HighlightField) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HighlightField) ProtoMessage() {}
+
+func (x *HighlightField) ProtoReflect() protoreflect.Message {
+	mi := &file_index_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HighlightField.ProtoReflect.Descriptor instead.
+func (*HighlightField) Descriptor() ([]byte, []int) {
+	return file_index_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *HighlightField) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *HighlightField) GetFragments() []string {
+	if x != nil {
+		return x.Fragments
+	}
+	return nil
+}
+
+type DocHighlight struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id     string            `protobuf:"bytes,1,opt,name=Id,proto3" json:"Id,omitempty"` //文档业务id
+	Fields []*HighlightField `protobuf:"bytes,2,rep,name=Fields,proto3" json:"Fields,omitempty"`
+}
+
+func (x *DocHighlight) Reset() {
+	*x = DocHighlight{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_index_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DocHighlight) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DocHighlight) ProtoMessage() {}
+
+func (x *DocHighlight) ProtoReflect() protoreflect.Message {
+	mi := &file_index_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DocHighlight.ProtoReflect.Descriptor instead.
+func (*DocHighlight) Descriptor() ([]byte, []int) {
+	return file_index_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DocHighlight) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DocHighlight) GetFields() []*HighlightField {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+// 部分更新文档，只修改请求中给出的部分，文档的IntId不变
+// Keywords、Numerics、Texts按Field整体替换，Texts替换时该Field的关键词也由新文本重新分析生成
+type UpdateDocRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Index          string              `protobuf:"bytes,1,opt,name=Index,proto3" json:"Index,omitempty"` //索引名称，为空表示默认索引
+	DocId          string              `protobuf:"bytes,2,opt,name=DocId,proto3" json:"DocId,omitempty"`
+	Keywords       []*doc.KeyWord      `protobuf:"bytes,3,rep,name=Keywords,proto3" json:"Keywords,omitempty"`
+	Numerics       []*doc.NumericField `protobuf:"bytes,4,rep,name=Numerics,proto3" json:"Numerics,omitempty"`
+	Texts          []*doc.TextField    `protobuf:"bytes,5,rep,name=Texts,proto3" json:"Texts,omitempty"`
+	RemoveFields   []string            `protobuf:"bytes,6,rep,name=RemoveFields,proto3" json:"RemoveFields,omitempty"`   //删除这些Field的关键词、数值和文本
+	SetBits        []int32             `protobuf:"varint,7,rep,packed,name=SetBits,proto3" json:"SetBits,omitempty"`     //置1的特征位，下标从1开始
+	ClearBits      []int32             `protobuf:"varint,8,rep,packed,name=ClearBits,proto3" json:"ClearBits,omitempty"` //置0的特征位
+	Bytes          []byte              `protobuf:"bytes,9,opt,name=Bytes,proto3" json:"Bytes,omitempty"`
+	UpdateBytes    bool                `protobuf:"varint,10,opt,name=UpdateBytes,proto3" json:"UpdateBytes,omitempty"` //是否用Bytes替换业务实体，Bytes为空时也替换
+	Condition      *Condition          `protobuf:"bytes,11,opt,name=Condition,proto3" json:"Condition,omitempty"`      //更新的条件，为空时直接更新
+	ExpireAt       int64               `protobuf:"varint,12,opt,name=ExpireAt,proto3" json:"ExpireAt,omitempty"`
+	UpdateExpireAt bool                `protobuf:"varint,13,opt,name=UpdateExpireAt,proto3" json:"UpdateExpireAt,omitempty"` //是否用ExpireAt替换过期时间，ExpireAt为0表示不再过期
+}
+
+func (x *UpdateDocRequest) Reset() {
+	*x = UpdateDocRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_index_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateDocRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDocRequest) ProtoMessage() {}
+
+func (x *UpdateDocRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_index_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDocRequest.ProtoReflect.Descriptor instead.
+func (*UpdateDocRequest) Descriptor() ([]byte, []int) {
+	return file_index_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UpdateDocRequest) GetIndex() string {
+	if x != nil {
+		return x.Index
+	}
+	return ""
+}
+
+func (x *UpdateDocRequest) GetDocId() string {
+	if x != nil {
+		return x.DocId
+	}
+	return ""
+}
+
+func (x *UpdateDocRequest) GetKeywords() []*doc.KeyWord {
+	if x != nil {
+		return x.Keywords
+	}
+	return nil
+}
+
+func (x *UpdateDocRequest) GetNumerics() []*doc.NumericField {
+	if x != nil {
+		return x.Numerics
+	}
+	return nil
+}
+
+func (x *UpdateDocRequest) GetTexts() []*doc.TextField {
+	if x != nil {
+		return x.Texts
+	}
+	return nil
+}
+
+func (x *UpdateDocRequest) GetRemoveFields() []string {
+	if x != nil {
+		return x.RemoveFields
+	}
+	return nil
+}
+
+func (x *UpdateDocRequest) GetSetBits() []int32 {
+	if x != nil {
+		return x.SetBits
+	}
+	return nil
+}
+
+func (x *UpdateDocRequest) GetClearBits() []int32 {
+	if x != nil {
+		return x.ClearBits
+	}
+	return nil
+}
+
+func (x *UpdateDocRequest) GetBytes() []byte {
+	if x != nil {
+		return x.Bytes
+	}
+	return nil
+}
+
+func (x *UpdateDocRequest) GetUpdateBytes() bool {
+	if x != nil {
+		return x.UpdateBytes
+	}
+	return false
+}
+
+func (x *UpdateDocRequest) GetCondition() *Condition {
+	if x != nil {
+		return x.Condition
+	}
+	return nil
+}
+
+func (x *UpdateDocRequest) GetExpireAt() int64 {
+	if x != nil {
+		return x.ExpireAt
+	}
+	return 0
+}
+
+func (x *UpdateDocRequest) GetUpdateExpireAt() bool {
+	if x != nil {
+		return x.UpdateExpireAt
+	}
+	return false
+}
+
+type SearchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Query        *term_query.TermQuery `protobuf:"bytes,1,opt,name=Query,proto3" json:"Query,omitempty"` //TermQuery类型引用自term_query.proto
+	OnFlag       *util.Bitmap          `protobuf:"bytes,2,opt,name=OnFlag,proto3" json:"OnFlag,omitempty"`
+	OffFlag      *util.Bitmap          `protobuf:"bytes,3,opt,name=OffFlag,proto3" json:"OffFlag,omitempty"`
+	OrFlags      []*util.Bitmap        `protobuf:"bytes,4,rep,name=OrFlags,proto3" json:"OrFlags,omitempty"`
+	TopK         int32                 `protobuf:"varint,5,opt,name=TopK,proto3" json:"TopK,omitempty"`                 //只返回相关性得分最高的TopK个文档，0表示返回全部，流式检索时每个节点最多返回10000个，超过时在最后一批返回游标
+	Offset       int32                 `protobuf:"varint,6,opt,name=Offset,proto3" json:"Offset,omitempty"`             //跳过前Offset个文档
+	Limit        int32                 `protobuf:"varint,7,opt,name=Limit,proto3" json:"Limit,omitempty"`               //每页文档数量，0表示不分页
+	Cursor       string                `protobuf:"bytes,8,opt,name=Cursor,proto3" json:"Cursor,omitempty"`              //上一页返回的NextCursor，从该位置之后继续查询
+	Index        string                `protobuf:"bytes,9,opt,name=Index,proto3" json:"Index,omitempty"`                //索引名称，为空表示默认索引
+	Aggregations []*Aggregation        `protobuf:"bytes,10,rep,name=Aggregations,proto3" json:"Aggregations,omitempty"` //在所有匹配的文档上聚合，不受分页影响
+	Highlight    *Highlight            `protobuf:"bytes,11,opt,name=Highlight,proto3" json:"Highlight,omitempty"`       //高亮返回文档中的匹配词，为空时不高亮
+	BatchSize    int32                 `protobuf:"varint,12,opt,name=BatchSize,proto3" json:"BatchSize,omitempty"`      //流式检索每批返回的文档数，0表示默认值100
+}
+
+func (x *SearchRequest) Reset() {
+	*x = SearchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_index_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchRequest) ProtoMessage() {}
+
+func (x *SearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_index_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchRequest.ProtoReflect.Descriptor instead.
+func (*SearchRequest) Descriptor() ([]byte, []int) {
+	return file_index_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SearchRequest) GetQuery() *term_query.TermQuery {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+func (x *SearchRequest) GetOnFlag() *util.Bitmap {
+	if x != nil {
+		return x.OnFlag
+	}
+	return nil
+}
+
+func (x *SearchRequest) GetOffFlag() *util.Bitmap {
+	if x != nil {
+		return x.OffFlag
+	}
+	return nil
+}
+
+func (x *SearchRequest) GetOrFlags() []*util.Bitmap {
+	if x != nil {
+		return x.OrFlags
+	}
+	return nil
+}
+
+func (x *SearchRequest) GetTopK() int32 {
+	if x != nil {
+		return x.TopK
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetIndex() string {
+	if x != nil {
+		return x.Index
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetAggregations() []*Aggregation {
+	if x != nil {
+		return x.Aggregations
+	}
+	return nil
+}
+
+func (x *SearchRequest) GetHighlight() *Highlight {
+	if x != nil {
+		return x.Highlight
+	}
+	return nil
+}
+
+func (x *SearchRequest) GetBatchSize() int32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+// 流式检索的一批结果，批与批之间按相关性得分降序
+type SearchBatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Docs       []*doc.Document `protobuf:"bytes,1,rep,name=Docs,proto3" json:"Docs,omitempty"`
+	Highlights []*DocHighlight `protobuf:"bytes,2,rep,name=Highlights,proto3" json:"Highlights,omitempty"`
+	NextCursor string          `protobuf:"bytes,3,opt,name=NextCursor,proto3" json:"NextCursor,omitempty"` //只在最后一批中设置，结果被截断时用它继续检索，为空表示结果完整
+}
+
+func (x *SearchBatch) Reset() {
+	*x = SearchBatch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_index_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SearchBatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchBatch) ProtoMessage() {}
+
+func (x *SearchBatch) ProtoReflect() protoreflect.Message {
+	mi := &file_index_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchBatch.ProtoReflect.Descriptor instead.
+func (*SearchBatch) Descriptor() ([]byte, []int) {
+	return file_index_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SearchBatch) GetDocs() []*doc.Document {
+	if x != nil {
+		return x.Docs
+	}
+	return nil
+}
+
+func (x *SearchBatch) GetHighlights() []*DocHighlight {
+	if x != nil {
+		return x.Highlights
+	}
+	return nil
+}
+
+func (x *SearchBatch) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type SearchResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Results      []*doc.Document      `protobuf:"bytes,1,rep,name=Results,proto3" json:"Results,omitempty"`
+	NextCursor   string               `protobuf:"bytes,2,opt,name=NextCursor,proto3" json:"NextCursor,omitempty"` //下一页的游标，为空表示没有更多结果
+	Aggregations []*AggregationResult `protobuf:"bytes,3,rep,name=Aggregations,proto3" json:"Aggregations,omitempty"`
+	Highlights   []*DocHighlight      `protobuf:"bytes,4,rep,name=Highlights,proto3" json:"Highlights,omitempty"` //Results中有匹配词的文档的高亮片段
+}
+
+func (x *SearchResult) Reset() {
+	*x = SearchResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_index_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SearchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResult) ProtoMessage() {}
+
+func (x *SearchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_index_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResult.ProtoReflect.Descriptor instead.
+func (*SearchResult) Descriptor() ([]byte, []int) {
+	return file_index_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SearchResult) GetResults() []*doc.Document {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *SearchResult) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *SearchResult) GetAggregations() []*AggregationResult {
+	if x != nil {
+		return x.Aggregations
+	}
+	return nil
+}
+
+func (x *SearchResult) GetHighlights() []*DocHighlight {
+	if x != nil {
+		return x.Highlights
+	}
+	return nil
+}
+
+type GetDocResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Doc *doc.Document `protobuf:"bytes,1,opt,name=Doc,proto3" json:"Doc,omitempty"` //文档不存在时为空
+}
+
+func (x *GetDocResult) Reset() {
+	*x = GetDocResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_index_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetDocResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDocResult) ProtoMessage() {}
+
+func (x *GetDocResult) ProtoReflect() protoreflect.Message {
+	mi := &file_index_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDocResult.ProtoReflect.Descriptor instead.
+func (*GetDocResult) Descriptor() ([]byte, []int) {
+	return file_index_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetDocResult) GetDoc() *doc.Document {
+	if x != nil {
+		return x.Doc
+	}
+	return nil
+}
+
+type MultiGetRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Index  string   `protobuf:"bytes,1,opt,name=Index,proto3" json:"Index,omitempty"` //索引名称，为空表示默认索引
+	DocIds []string `protobuf:"bytes,2,rep,name=DocIds,proto3" json:"DocIds,omitempty"`
+}
+
+func (x *MultiGetRequest) Reset() {
+	*x = MultiGetRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_index_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MultiGetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MultiGetRequest) ProtoMessage() {}
+
+func (x *MultiGetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_index_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MultiGetRequest.ProtoReflect.Descriptor instead.
+func (*MultiGetRequest) Descriptor() ([]byte, []int) {
+	return file_index_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *MultiGetRequest) GetIndex() string {
+	if x != nil {
+		return x.Index
+	}
+	return ""
+}
+
+func (x *MultiGetRequest) GetDocIds() []string {
+	if x != nil {
+		return x.DocIds
+	}
+	return nil
+}
+
+type MultiGetResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Docs []*doc.Document `protobuf:"bytes,1,rep,name=Docs,proto3" json:"Docs,omitempty"` //按DocIds的顺序，不存在的文档不返回
+}
+
+func (x *MultiGetResult) Reset() {
+	*x = MultiGetResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_index_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MultiGetResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MultiGetResult) ProtoMessage() {}
+
+func (x *MultiGetResult) ProtoReflect() protoreflect.Message {
+	mi := &file_index_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MultiGetResult.ProtoReflect.Descriptor instead.
+func (*MultiGetResult) Descriptor() ([]byte, []int) {
+	return file_index_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *MultiGetResult) GetDocs() []*doc.Document {
+	if x != nil {
+		return x.Docs
+	}
+	return nil
+}
+
+type CountRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Index string `protobuf:"bytes,1,opt,name=Index,proto3" json:"Index,omitempty"` //索引名称，为空表示默认索引
+}
+
+func (x *CountRequest) Reset() {
+	*x = CountRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_index_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountRequest) ProtoMessage() {}
+
+func (x *CountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_index_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountRequest.ProtoReflect.Descriptor instead.
+func (*CountRequest) Descriptor() ([]byte, []int) {
+	return file_index_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CountRequest) GetIndex() string {
+	if x != nil {
+		return x.Index
+	}
+	return ""
+}
+
+type CreateIndexRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name           string `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`                      //索引名称，只能包含字母、数字、下划线和中划线
+	IndexType      int32  `protobuf:"varint,2,opt,name=IndexType,proto3" json:"IndexType,omitempty"`           //倒排索引类型，1是跳表，2是roaring bitmap，0使用节点的配置
+	DocNumEstimate int32  `protobuf:"varint,3,opt,name=DocNumEstimate,proto3" json:"DocNumEstimate,omitempty"` //文档数量预估值，0使用节点的配置
+	Schema         string `protobuf:"bytes,4,opt,name=Schema,proto3" json:"Schema,omitempty"`                  //YAML或JSON格式的schema，为空时不校验文档
+}
+
+func (x *CreateIndexRequest) Reset() {
+	*x = CreateIndexRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_index_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateIndexRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateIndexRequest) ProtoMessage() {}
+
+func (x *CreateIndexRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_index_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateIndexRequest.ProtoReflect.Descriptor instead.
+func (*CreateIndexRequest) Descriptor() ([]byte, []int) {
+	return file_index_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *CreateIndexRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateIndexRequest) GetIndexType() int32 {
+	if x != nil {
+		return x.IndexType
+	}
+	return 0
+}
+
+func (x *CreateIndexRequest) GetDocNumEstimate() int32 {
+	if x != nil {
+		return x.DocNumEstimate
+	}
+	return 0
+}
+
+func (x *CreateIndexRequest) GetSchema() string {
+	if x != nil {
+		return x.Schema
+	}
+	return ""
+}
+
+type IndexName struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+}
+
+func (x *IndexName) Reset() {
+	*x = IndexName{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_index_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IndexName) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IndexName) ProtoMessage() {}
+
+func (x *IndexName) ProtoReflect() protoreflect.Message {
+	mi := &file_index_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IndexName.ProtoReflect.Descriptor instead.
+func (*IndexName) Descriptor() ([]byte, []int) {
+	return file_index_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *IndexName) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type ListIndexRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListIndexRequest) Reset() {
+	*x = ListIndexRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_index_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListIndexRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListIndexRequest) ProtoMessage() {}
+
+func (x *ListIndexRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_index_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListIndexRequest.ProtoReflect.Descriptor instead.
+func (*ListIndexRequest) Descriptor() ([]byte, []int) {
+	return file_index_proto_rawDescGZIP(), []int{20}
+}
+
+type IndexInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name  string `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	Count int32  `protobuf:"varint,2,opt,name=Count,proto3" json:"Count,omitempty"` //文档数量
+}
+
+func (x *IndexInfo) Reset() {
+	*x = IndexInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_index_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IndexInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IndexInfo) ProtoMessage() {}
+
+func (x *IndexInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_index_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IndexInfo.ProtoReflect.Descriptor instead.
+func (*IndexInfo) Descriptor() ([]byte, []int) {
+	return file_index_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *IndexInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *IndexInfo) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type IndexList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Indexes []*IndexInfo `protobuf:"bytes,1,rep,name=Indexes,proto3" json:"Indexes,omitempty"`
+}
+
+func (x *IndexList) Reset() {
+	*x = IndexList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_index_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IndexList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IndexList) ProtoMessage() {}
+
+func (x *IndexList) ProtoReflect() protoreflect.Message {
+	mi := &file_index_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IndexList.ProtoReflect.Descriptor instead.
+func (*IndexList) Descriptor() ([]byte, []int) {
+	return file_index_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *IndexList) GetIndexes() []*IndexInfo {
+	if x != nil {
+		return x.Indexes
+	}
+	return nil
+}
+
+type BulkItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id       string `protobuf:"bytes,1,opt,name=Id,proto3" json:"Id,omitempty"`
+	Ok       bool   `protobuf:"varint,2,opt,name=Ok,proto3" json:"Ok,omitempty"`
+	Error    string `protobuf:"bytes,3,opt,name=Error,proto3" json:"Error,omitempty"`        //失败原因
+	Conflict bool   `protobuf:"varint,4,opt,name=Conflict,proto3" json:"Conflict,omitempty"` //写入条件不满足
+	Version  uint64 `protobuf:"varint,5,opt,name=Version,proto3" json:"Version,omitempty"`   //添加后文档的版本，冲突时是文档当前的版本
+}
+
+func (x *BulkItem) Reset() {
+	*x = BulkItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_index_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BulkItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkItem) ProtoMessage() {}
+
+func (x *BulkItem) ProtoReflect() protoreflect.Message {
+	mi := &file_index_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkItem.ProtoReflect.Descriptor instead.
+func (*BulkItem) Descriptor() ([]byte, []int) {
+	return file_index_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *BulkItem) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BulkItem) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *BulkItem) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *BulkItem) GetConflict() bool {
+	if x != nil {
+		return x.Conflict
+	}
+	return false
+}
+
+func (x *BulkItem) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type BulkAddResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Count int32       `protobuf:"varint,1,opt,name=Count,proto3" json:"Count,omitempty"` //成功添加的文档数
+	Items []*BulkItem `protobuf:"bytes,2,rep,name=Items,proto3" json:"Items,omitempty"`  //与请求中的文档一一对应
+}
+
+func (x *BulkAddResult) Reset() {
+	*x = BulkAddResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_index_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BulkAddResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkAddResult) ProtoMessage() {}
+
+func (x *BulkAddResult) ProtoReflect() protoreflect.Message {
+	mi := &file_index_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkAddResult.ProtoReflect.Descriptor instead.
+func (*BulkAddResult) Descriptor() ([]byte, []int) {
+	return file_index_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *BulkAddResult) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *BulkAddResult) GetItems() []*BulkItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+// 输入提示，用词典中以Prefix开头的词补全
+type SuggestRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Index       string   `protobuf:"bytes,1,opt,name=Index,proto3" json:"Index,omitempty"`             //索引名称，为空表示默认索引
+	Prefix      string   `protobuf:"bytes,2,opt,name=Prefix,proto3" json:"Prefix,omitempty"`           //用户已输入的部分，与索引中分析后的词比较
+	Fields      []string `protobuf:"bytes,3,rep,name=Fields,proto3" json:"Fields,omitempty"`           //从哪些字段的词中补全
+	Size        int32    `protobuf:"varint,4,opt,name=Size,proto3" json:"Size,omitempty"`              //最多返回的提示数量，0表示使用默认值10，负数表示全部
+	Fuzzy       bool     `protobuf:"varint,5,opt,name=Fuzzy,proto3" json:"Fuzzy,omitempty"`            //是否允许前缀有拼写错误
+	Fuzziness   int32    `protobuf:"varint,6,opt,name=Fuzziness,proto3" json:"Fuzziness,omitempty"`    //前缀的最大编辑距离，0表示根据长度自动选择
+	WeightField string   `protobuf:"bytes,7,opt,name=WeightField,proto3" json:"WeightField,omitempty"` //权重使用的数值字段，取包含该词的文档中的最大值，为空时按文档数
+}
+
+func (x *SuggestRequest) Reset() {
+	*x = SuggestRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_index_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SuggestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestRequest) ProtoMessage() {}
+
+func (x *SuggestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_index_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestRequest.ProtoReflect.Descriptor instead.
+func (*SuggestRequest) Descriptor() ([]byte, []int) {
+	return file_index_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *SuggestRequest) GetIndex() string {
+	if x != nil {
+		return x.Index
+	}
+	return ""
+}
+
+func (x *SuggestRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *SuggestRequest) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+func (x *SuggestRequest) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *SuggestRequest) GetFuzzy() bool {
+	if x != nil {
+		return x.Fuzzy
+	}
+	return false
+}
+
+func (x *SuggestRequest) GetFuzziness() int32 {
+	if x != nil {
+		return x.Fuzziness
+	}
+	return 0
+}
+
+func (x *SuggestRequest) GetWeightField() string {
+	if x != nil {
+		return x.WeightField
+	}
+	return ""
+}
+
+type Suggestion struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Text     string  `protobuf:"bytes,1,opt,name=Text,proto3" json:"Text,omitempty"` //补全后的词
+	Field    string  `protobuf:"bytes,2,opt,name=Field,proto3" json:"Field,omitempty"`
+	Weight   float64 `protobuf:"fixed64,3,opt,name=Weight,proto3" json:"Weight,omitempty"`
+	Distance int32   `protobuf:"varint,4,opt,name=Distance,proto3" json:"Distance,omitempty"` //与前缀的编辑距离，精确匹配为0
+}
+
+func (x *Suggestion) Reset() {
+	*x = Suggestion{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_index_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Suggestion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Suggestion) ProtoMessage() {}
+
+func (x *Suggestion) ProtoReflect() protoreflect.Message {
+	mi := &file_index_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Suggestion.ProtoReflect.Descriptor instead.
+func (*Suggestion) Descriptor() ([]byte, []int) {
+	return file_index_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *Suggestion) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Suggestion) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *Suggestion) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *Suggestion) GetDistance() int32 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+type SuggestResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Suggestions []*Suggestion `protobuf:"bytes,1,rep,name=Suggestions,proto3" json:"Suggestions,omitempty"` //按编辑距离升序，相同时按权重降序
+}
+
+func (x *SuggestResult) Reset() {
+	*x = SuggestResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_index_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SuggestResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestResult) ProtoMessage() {}
+
+func (x *SuggestResult) ProtoReflect() protoreflect.Message {
+	mi := &file_index_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestResult.ProtoReflect.Descriptor instead.
+func (*SuggestResult) Descriptor() ([]byte, []int) {
+	return file_index_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *SuggestResult) GetSuggestions() []*Suggestion {
+	if x != nil {
+		return x.Suggestions
+	}
+	return nil
+}
+
+var File_index_proto protoreflect.FileDescriptor
+
+var file_index_proto_rawDesc = []byte{
+	0x0a, 0x0b, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0d, 0x69,
+	0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x1a, 0x0f, 0x74, 0x79,
+	0x70, 0x65, 0x73, 0x2f, 0x64, 0x6f, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x16, 0x74,
+	0x79, 0x70, 0x65, 0x73, 0x2f, 0x74, 0x65, 0x72, 0x6d, 0x5f, 0x71, 0x75, 0x65, 0x72, 0x79, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x11, 0x75, 0x74, 0x69, 0x6c, 0x2f, 0x62, 0x69, 0x74, 0x6d,
+	0x61, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x45, 0x0a, 0x09, 0x43, 0x6f, 0x6e, 0x64,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x49, 0x66, 0x56, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x49, 0x66, 0x56, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x49, 0x66, 0x41, 0x62, 0x73, 0x65, 0x6e, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x49, 0x66, 0x41, 0x62, 0x73, 0x65, 0x6e, 0x74, 0x22,
+	0x6b, 0x0a, 0x05, 0x44, 0x6f, 0x63, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x44, 0x6f, 0x63, 0x49,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x44, 0x6f, 0x63, 0x49, 0x64, 0x12, 0x14,
+	0x0a, 0x05, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x49,
+	0x6e, 0x64, 0x65, 0x78, 0x12, 0x36, 0x0a, 0x09, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x09, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x80, 0x01, 0x0a,
+	0x0d, 0x41, 0x64, 0x64, 0x44, 0x6f, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14,
+	0x0a, 0x05, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x49,
+	0x6e, 0x64, 0x65, 0x78, 0x12, 0x21, 0x0a, 0x03, 0x44, 0x6f, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0f, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65,
+	0x6e, 0x74, 0x52, 0x03, 0x44, 0x6f, 0x63, 0x12, 0x36, 0x0a, 0x09, 0x43, 0x6f, 0x6e, 0x64, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x69, 0x6e, 0x64,
+	0x65, 0x78, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x43, 0x6f, 0x6e, 0x64, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x22,
+	0x5b, 0x0a, 0x0d, 0x41, 0x66, 0x66, 0x65, 0x63, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x12, 0x14, 0x0a, 0x05, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x05, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x43, 0x6f, 0x6e, 0x66, 0x6c, 0x69,
+	0x63, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x43, 0x6f, 0x6e, 0x66, 0x6c, 0x69,
+	0x63, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0xdf, 0x01, 0x0a,
+	0x0b, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3e, 0x0a, 0x04,
+	0x54, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x2a, 0x2e, 0x69, 0x6e, 0x64,
+	0x65, 0x78, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x41, 0x67, 0x67, 0x72, 0x65,
+	0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x49, 0x6e,
+	0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x49, 0x6e,
+	0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x22, 0x36, 0x0a, 0x0f, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x12, 0x09, 0x0a, 0x05, 0x54, 0x45, 0x52,
+	0x4d, 0x53, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09, 0x48, 0x49, 0x53, 0x54, 0x4f, 0x47, 0x52, 0x41,
+	0x4d, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x53, 0x54, 0x41, 0x54, 0x53, 0x10, 0x02, 0x22, 0x44,
+	0x0a, 0x06, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x4b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x4b, 0x65, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x46, 0x72,
+	0x6f, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x14,
+	0x0a, 0x05, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x43,
+	0x6f, 0x75, 0x6e, 0x74, 0x22, 0xb6, 0x01, 0x0a, 0x11, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x2f,
+	0x0a, 0x07, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x15, 0x2e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x52, 0x07, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x12,
+	0x14, 0x0a, 0x05, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05,
+	0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x4d, 0x69, 0x6e, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x03, 0x4d, 0x69, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x4d, 0x61, 0x78, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x4d, 0x61, 0x78, 0x12, 0x10, 0x0a, 0x03, 0x53, 0x75, 0x6d,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x53, 0x75, 0x6d, 0x12, 0x10, 0x0a, 0x03, 0x41,
+	0x76, 0x67, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x41, 0x76, 0x67, 0x22, 0x9d, 0x01,
+	0x0a, 0x09, 0x48, 0x69, 0x67, 0x68, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x46,
+	0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x46, 0x69, 0x65,
+	0x6c, 0x64, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x50, 0x72, 0x65, 0x54, 0x61, 0x67, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x50, 0x72, 0x65, 0x54, 0x61, 0x67, 0x12, 0x18, 0x0a, 0x07, 0x50,
+	0x6f, 0x73, 0x74, 0x54, 0x61, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x50, 0x6f,
+	0x73, 0x74, 0x54, 0x61, 0x67, 0x12, 0x22, 0x0a, 0x0c, 0x46, 0x72, 0x61, 0x67, 0x6d, 0x65, 0x6e,
+	0x74, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x46, 0x72, 0x61,
+	0x67, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x4e, 0x75, 0x6d,
+	0x46, 0x72, 0x61, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0c, 0x4e, 0x75, 0x6d, 0x46, 0x72, 0x61, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x22, 0x44, 0x0a,
+	0x0e, 0x48, 0x69, 0x67, 0x68, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x12,
+	0x14, 0x0a, 0x05, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x46, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x46, 0x72, 0x61, 0x67, 0x6d, 0x65, 0x6e,
+	0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x46, 0x72, 0x61, 0x67, 0x6d, 0x65,
+	0x6e, 0x74, 0x73, 0x22, 0x55, 0x0a, 0x0c, 0x44, 0x6f, 0x63, 0x48, 0x69, 0x67, 0x68, 0x6c, 0x69,
+	0x67, 0x68, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x02, 0x49, 0x64, 0x12, 0x35, 0x0a, 0x06, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x48, 0x69, 0x67, 0x68, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x46, 0x69, 0x65,
+	0x6c, 0x64, 0x52, 0x06, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x22, 0xd3, 0x03, 0x0a, 0x10, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x44, 0x6f, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x14, 0x0a, 0x05, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x14, 0x0a, 0x05, 0x44, 0x6f, 0x63, 0x49, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x44, 0x6f, 0x63, 0x49, 0x64, 0x12, 0x2a, 0x0a, 0x08, 0x4b,
+	0x65, 0x79, 0x77, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e,
+	0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x4b, 0x65, 0x79, 0x57, 0x6f, 0x72, 0x64, 0x52, 0x08, 0x4b,
+	0x65, 0x79, 0x77, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x2f, 0x0a, 0x08, 0x4e, 0x75, 0x6d, 0x65, 0x72,
+	0x69, 0x63, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x74, 0x79, 0x70, 0x65,
+	0x73, 0x2e, 0x4e, 0x75, 0x6d, 0x65, 0x72, 0x69, 0x63, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x52, 0x08,
+	0x4e, 0x75, 0x6d, 0x65, 0x72, 0x69, 0x63, 0x73, 0x12, 0x26, 0x0a, 0x05, 0x54, 0x65, 0x78, 0x74,
+	0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e,
+	0x54, 0x65, 0x78, 0x74, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x52, 0x05, 0x54, 0x65, 0x78, 0x74, 0x73,
+	0x12, 0x22, 0x0a, 0x0c, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73,
+	0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x46, 0x69,
+	0x65, 0x6c, 0x64, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x53, 0x65, 0x74, 0x42, 0x69, 0x74, 0x73, 0x18,
+	0x07, 0x20, 0x03, 0x28, 0x05, 0x52, 0x07, 0x53, 0x65, 0x74, 0x42, 0x69, 0x74, 0x73, 0x12, 0x1c,
+	0x0a, 0x09, 0x43, 0x6c, 0x65, 0x61, 0x72, 0x42, 0x69, 0x74, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28,
+	0x05, 0x52, 0x09, 0x43, 0x6c, 0x65, 0x61, 0x72, 0x42, 0x69, 0x74, 0x73, 0x12, 0x14, 0x0a, 0x05,
+	0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x42, 0x79, 0x74,
+	0x65, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x42, 0x79, 0x74, 0x65,
+	0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x42,
+	0x79, 0x74, 0x65, 0x73, 0x12, 0x36, 0x0a, 0x09, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x09, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08,
+	0x45, 0x78, 0x70, 0x69, 0x72, 0x65, 0x41, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08,
+	0x45, 0x78, 0x70, 0x69, 0x72, 0x65, 0x41, 0x74, 0x12, 0x26, 0x0a, 0x0e, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x45, 0x78, 0x70, 0x69, 0x72, 0x65, 0x41, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x45, 0x78, 0x70, 0x69, 0x72, 0x65, 0x41, 0x74,
+	0x22, 0xb3, 0x03, 0x0a, 0x0d, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x26, 0x0a, 0x05, 0x51, 0x75, 0x65, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x10, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x54, 0x65, 0x72, 0x6d, 0x51, 0x75,
+	0x65, 0x72, 0x79, 0x52, 0x05, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12, 0x24, 0x0a, 0x06, 0x4f, 0x6e,
+	0x46, 0x6c, 0x61, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x75, 0x74, 0x69,
+	0x6c, 0x2e, 0x42, 0x69, 0x74, 0x6d, 0x61, 0x70, 0x52, 0x06, 0x4f, 0x6e, 0x46, 0x6c, 0x61, 0x67,
+	0x12, 0x26, 0x0a, 0x07, 0x4f, 0x66, 0x66, 0x46, 0x6c, 0x61, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0c, 0x2e, 0x75, 0x74, 0x69, 0x6c, 0x2e, 0x42, 0x69, 0x74, 0x6d, 0x61, 0x70, 0x52,
+	0x07, 0x4f, 0x66, 0x66, 0x46, 0x6c, 0x61, 0x67, 0x12, 0x26, 0x0a, 0x07, 0x4f, 0x72, 0x46, 0x6c,
+	0x61, 0x67, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x75, 0x74, 0x69, 0x6c,
+	0x2e, 0x42, 0x69, 0x74, 0x6d, 0x61, 0x70, 0x52, 0x07, 0x4f, 0x72, 0x46, 0x6c, 0x61, 0x67, 0x73,
+	0x12, 0x12, 0x0a, 0x04, 0x54, 0x6f, 0x70, 0x4b, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04,
+	0x54, 0x6f, 0x70, 0x4b, 0x12, 0x16, 0x0a, 0x06, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x14, 0x0a, 0x05,
+	0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x4c, 0x69, 0x6d,
+	0x69, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x43, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x43, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x49, 0x6e,
+	0x64, 0x65, 0x78, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x49, 0x6e, 0x64, 0x65, 0x78,
+	0x12, 0x3e, 0x0a, 0x0c, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x0c, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x12, 0x36, 0x0a, 0x09, 0x48, 0x69, 0x67, 0x68, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x18, 0x0b, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x48, 0x69, 0x67, 0x68, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x52, 0x09, 0x48,
+	0x69, 0x67, 0x68, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x42, 0x61, 0x74,
+	0x63, 0x68, 0x53, 0x69, 0x7a, 0x65, 0x22, 0x8f, 0x01, 0x0a, 0x0b, 0x53, 0x65, 0x61, 0x72, 0x63,
+	0x68, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x23, 0x0a, 0x04, 0x44, 0x6f, 0x63, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x44, 0x6f, 0x63,
+	0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x04, 0x44, 0x6f, 0x63, 0x73, 0x12, 0x3b, 0x0a, 0x0a, 0x48,
+	0x69, 0x67, 0x68, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x1b, 0x2e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x44, 0x6f, 0x63, 0x48, 0x69, 0x67, 0x68, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x52, 0x0a, 0x48, 0x69,
+	0x67, 0x68, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x4e, 0x65, 0x78, 0x74,
+	0x43, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x4e, 0x65,
+	0x78, 0x74, 0x43, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x22, 0xdc, 0x01, 0x0a, 0x0c, 0x53, 0x65, 0x61,
+	0x72, 0x63, 0x68, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x29, 0x0a, 0x07, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x74, 0x79, 0x70,
+	0x65, 0x73, 0x2e, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x07, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x4e, 0x65, 0x78, 0x74, 0x43, 0x75, 0x72, 0x73,
+	0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x4e, 0x65, 0x78, 0x74, 0x43, 0x75,
+	0x72, 0x73, 0x6f, 0x72, 0x12, 0x44, 0x0a, 0x0c, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x69, 0x6e, 0x64,
+	0x65, 0x78, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x41, 0x67, 0x67, 0x72, 0x65,
+	0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x0c, 0x41, 0x67,
+	0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x3b, 0x0a, 0x0a, 0x48, 0x69,
+	0x67, 0x68, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b,
+	0x2e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x44,
+	0x6f, 0x63, 0x48, 0x69, 0x67, 0x68, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x52, 0x0a, 0x48, 0x69, 0x67,
+	0x68, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x22, 0x31, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x44, 0x6f,
+	0x63, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x21, 0x0a, 0x03, 0x44, 0x6f, 0x63, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x44, 0x6f, 0x63,
+	0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x03, 0x44, 0x6f, 0x63, 0x22, 0x3f, 0x0a, 0x0f, 0x4d, 0x75,
+	0x6c, 0x74, 0x69, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a,
+	0x05, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x49, 0x6e,
+	0x64, 0x65, 0x78, 0x12, 0x16, 0x0a, 0x06, 0x44, 0x6f, 0x63, 0x49, 0x64, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x06, 0x44, 0x6f, 0x63, 0x49, 0x64, 0x73, 0x22, 0x35, 0x0a, 0x0e, 0x4d,
+	0x75, 0x6c, 0x74, 0x69, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x23, 0x0a,
+	0x04, 0x44, 0x6f, 0x63, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x74, 0x79,
+	0x70, 0x65, 0x73, 0x2e, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x04, 0x44, 0x6f,
+	0x63, 0x73, 0x22, 0x24, 0x0a, 0x0c, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x22, 0x86, 0x01, 0x0a, 0x12, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4e,
+	0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x54, 0x79, 0x70, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x26, 0x0a, 0x0e, 0x44, 0x6f, 0x63, 0x4e, 0x75, 0x6d, 0x45, 0x73, 0x74, 0x69, 0x6d,
+	0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x44, 0x6f, 0x63, 0x4e, 0x75,
+	0x6d, 0x45, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x63, 0x68,
+	0x65, 0x6d, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x53, 0x63, 0x68, 0x65, 0x6d,
+	0x61, 0x22, 0x1f, 0x0a, 0x09, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4e, 0x61,
+	0x6d, 0x65, 0x22, 0x12, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x35, 0x0a, 0x09, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x49,
+	0x6e, 0x66, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x3f, 0x0a,
+	0x09, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x32, 0x0a, 0x07, 0x49, 0x6e,
+	0x64, 0x65, 0x78, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x69, 0x6e,
+	0x64, 0x65, 0x78, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x49, 0x6e, 0x64, 0x65,
+	0x78, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x07, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x65, 0x73, 0x22, 0x76,
+	0x0a, 0x08, 0x42, 0x75, 0x6c, 0x6b, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x4f, 0x6b,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x02, 0x4f, 0x6b, 0x12, 0x14, 0x0a, 0x05, 0x45, 0x72,
+	0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72,
+	0x12, 0x1a, 0x0a, 0x08, 0x43, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x08, 0x43, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x12, 0x18, 0x0a, 0x07,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x54, 0x0a, 0x0d, 0x42, 0x75, 0x6c, 0x6b, 0x41, 0x64,
+	0x64, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2d, 0x0a,
+	0x05, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x69,
+	0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x42, 0x75, 0x6c,
+	0x6b, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x22, 0xc0, 0x01, 0x0a,
+	0x0e, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x14, 0x0a, 0x05, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x16, 0x0a, 0x06, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x16, 0x0a,
+	0x06, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x46,
+	0x69, 0x65, 0x6c, 0x64, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x04, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x46, 0x75, 0x7a,
+	0x7a, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x46, 0x75, 0x7a, 0x7a, 0x79, 0x12,
+	0x1c, 0x0a, 0x09, 0x46, 0x75, 0x7a, 0x7a, 0x69, 0x6e, 0x65, 0x73, 0x73, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x09, 0x46, 0x75, 0x7a, 0x7a, 0x69, 0x6e, 0x65, 0x73, 0x73, 0x12, 0x20, 0x0a,
+	0x0b, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x22,
+	0x6a, 0x0a, 0x0a, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a,
+	0x04, 0x54, 0x65, 0x78, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x54, 0x65, 0x78,
+	0x74, 0x12, 0x14, 0x0a, 0x05, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x57, 0x65, 0x69, 0x67, 0x68,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12,
+	0x1a, 0x0a, 0x08, 0x44, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x08, 0x44, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x22, 0x4c, 0x0a, 0x0d, 0x53,
+	0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x3b, 0x0a, 0x0b,
+	0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x19, 0x2e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x53, 0x75,
+	0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x32, 0xec, 0x07, 0x0a, 0x0c, 0x49, 0x6e,
+	0x64, 0x65, 0x78, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3f, 0x0a, 0x09, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x44, 0x6f, 0x63, 0x12, 0x14, 0x2e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x44, 0x6f, 0x63, 0x49, 0x64, 0x1a, 0x1c, 0x2e,
+	0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x41, 0x66,
+	0x66, 0x65, 0x63, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x37, 0x0a, 0x06, 0x41,
+	0x64, 0x64, 0x44, 0x6f, 0x63, 0x12, 0x0f, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x44, 0x6f,
+	0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x1a, 0x1c, 0x2e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x41, 0x66, 0x66, 0x65, 0x63, 0x74, 0x65, 0x64, 0x43,
+	0x6f, 0x75, 0x6e, 0x74, 0x12, 0x46, 0x0a, 0x08, 0x41, 0x64, 0x64, 0x44, 0x6f, 0x63, 0x54, 0x6f,
+	0x12, 0x1c, 0x2e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x2e, 0x41, 0x64, 0x64, 0x44, 0x6f, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c,
+	0x2e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x41,
+	0x66, 0x66, 0x65, 0x63, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x47, 0x0a, 0x07,
+	0x42, 0x75, 0x6c, 0x6b, 0x41, 0x64, 0x64, 0x12, 0x1c, 0x2e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x41, 0x64, 0x64, 0x44, 0x6f, 0x63, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x42, 0x75, 0x6c, 0x6b, 0x41, 0x64, 0x64, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x28, 0x01, 0x12, 0x4a, 0x0a, 0x09, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x44,
+	0x6f, 0x63, 0x12, 0x1f, 0x2e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x44, 0x6f, 0x63, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x41, 0x66, 0x66, 0x65, 0x63, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x12, 0x43, 0x0a, 0x06, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x12, 0x1c, 0x2e, 0x69, 0x6e,
+	0x64, 0x65, 0x78, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x61, 0x72,
+	0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x69, 0x6e, 0x64, 0x65,
+	0x78, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x4a, 0x0a, 0x0c, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68,
+	0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x1c, 0x2e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x30, 0x01, 0x12, 0x42, 0x0a, 0x05, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1b, 0x2e, 0x69, 0x6e,
+	0x64, 0x65, 0x78, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x69, 0x6e, 0x64, 0x65, 0x78,
+	0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x41, 0x66, 0x66, 0x65, 0x63, 0x74, 0x65,
+	0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x4e, 0x0a, 0x0b, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x21, 0x2e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x64, 0x65,
+	0x78, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x69, 0x6e, 0x64, 0x65, 0x78,
+	0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x41, 0x66, 0x66, 0x65, 0x63, 0x74, 0x65,
+	0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x45, 0x0a, 0x0b, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x18, 0x2e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x4e, 0x61, 0x6d, 0x65, 0x1a,
+	0x1c, 0x2e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x41, 0x66, 0x66, 0x65, 0x63, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x46, 0x0a,
+	0x09, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x1f, 0x2e, 0x69, 0x6e, 0x64,
+	0x65, 0x78, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x49,
+	0x6e, 0x64, 0x65, 0x78, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x69, 0x6e,
+	0x64, 0x65, 0x78, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x49, 0x6e, 0x64, 0x65,
+	0x78, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x46, 0x0a, 0x07, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74,
+	0x12, 0x1d, 0x2e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x2e, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1c, 0x2e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x3b, 0x0a,
+	0x06, 0x47, 0x65, 0x74, 0x44, 0x6f, 0x63, 0x12, 0x14, 0x2e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x44, 0x6f, 0x63, 0x49, 0x64, 0x1a, 0x1b, 0x2e,
+	0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x47, 0x65,
+	0x74, 0x44, 0x6f, 0x63, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x4c, 0x0a, 0x0b, 0x4d, 0x75,
+	0x6c, 0x74, 0x69, 0x47, 0x65, 0x74, 0x44, 0x6f, 0x63, 0x12, 0x1e, 0x2e, 0x69, 0x6e, 0x64, 0x65,
+	0x78, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x47,
+	0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x69, 0x6e, 0x64, 0x65,
+	0x78, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x47,
+	0x65, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x42, 0x18, 0x5a, 0x16, 0x52, 0x65, 0x73, 0x65,
+	0x61, 0x72, 0x63, 0x68, 0x2f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_index_proto_rawDescOnce sync.Once
+	file_index_proto_rawDescData = file_index_proto_rawDesc
+)
+
+func file_index_proto_rawDescGZIP() []byte {
+	file_index_proto_rawDescOnce.Do(func() {
+		file_index_proto_rawDescData = protoimpl.X.CompressGZIP(file_index_proto_rawDescData)
+	})
+	return file_index_proto_rawDescData
+}
+
+var file_index_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_index_proto_msgTypes = make([]protoimpl.MessageInfo, 28)
+var file_index_proto_goTypes = []interface{}{
+	(Aggregation_AggregationType)(0), // 0: index_service.Aggregation.AggregationType
+	(*Condition)(nil),                // 1: index_service.Condition
+	(*DocId)(nil),                    // 2: index_service.DocId
+	(*AddDocRequest)(nil),            // 3: index_service.AddDocRequest
+	(*AffectedCount)(nil),            // 4: index_service.AffectedCount
+	(*Aggregation)(nil),              // 5: index_service.Aggregation
+	(*Bucket)(nil),                   // 6: index_service.Bucket
+	(*AggregationResult)(nil),        // 7: index_service.AggregationResult
+	(*Highlight)(nil),                // 8: index_service.Highlight
+	(*HighlightField)(nil),           // 9: index_service.HighlightField
+	(*DocHighlight)(nil),             // 10: index_service.DocHighlight
+	(*UpdateDocRequest)(nil),         // 11: index_service.UpdateDocRequest
+	(*SearchRequest)(nil),            // 12: index_service.SearchRequest
+	(*SearchBatch)(nil),              // 13: index_service.SearchBatch
+	(*SearchResult)(nil),             // 14: index_service.SearchResult
+	(*GetDocResult)(nil),             // 15: index_service.GetDocResult
+	(*MultiGetRequest)(nil),          // 16: index_service.MultiGetRequest
+	(*MultiGetResult)(nil),           // 17: index_service.MultiGetResult
+	(*CountRequest)(nil),             // 18: index_service.CountRequest
+	(*CreateIndexRequest)(nil),       // 19: index_service.CreateIndexRequest
+	(*IndexName)(nil),                // 20: index_service.IndexName
+	(*ListIndexRequest)(nil),         // 21: index_service.ListIndexRequest
+	(*IndexInfo)(nil),                // 22: index_service.IndexInfo
+	(*IndexList)(nil),                // 23: index_service.IndexList
+	(*BulkItem)(nil),                 // 24: index_service.BulkItem
+	(*BulkAddResult)(nil),            // 25: index_service.BulkAddResult
+	(*SuggestRequest)(nil),           // 26: index_service.SuggestRequest
+	(*Suggestion)(nil),               // 27: index_service.Suggestion
+	(*SuggestResult)(nil),            // 28: index_service.SuggestResult
+	(*doc.Document)(nil),             // 29: types.Document
+	(*doc.KeyWord)(nil),              // 30: types.KeyWord
+	(*doc.NumericField)(nil),         // 31: types.NumericField
+	(*doc.TextField)(nil),            // 32: types.TextField
+	(*term_query.TermQuery)(nil),     // 33: types.TermQuery
+	(*util.Bitmap)(nil),              // 34: util.Bitmap
+}
+var file_index_proto_depIdxs = []int32{
+	1,  // 0: index_service.DocId.Condition:type_name -> index_service.Condition
+	29, // 1: index_service.AddDocRequest.Doc:type_name -> types.Document
+	1,  // 2: index_service.AddDocRequest.Condition:type_name -> index_service.Condition
+	0,  // 3: index_service.Aggregation.Type:type_name -> index_service.Aggregation.AggregationType
+	6,  // 4: index_service.AggregationResult.Buckets:type_name -> index_service.Bucket
+	9,  // 5: index_service.DocHighlight.Fields:type_name -> index_service.HighlightField
+	30, // 6: index_service.UpdateDocRequest.Keywords:type_name -> types.KeyWord
+	31, // 7: index_service.UpdateDocRequest.Numerics:type_name -> types.NumericField
+	32, // 8: index_service.UpdateDocRequest.Texts:type_name -> types.TextField
+	1,  // 9: index_service.UpdateDocRequest.Condition:type_name -> index_service.Condition
+	33, // 10: index_service.SearchRequest.Query:type_name -> types.TermQuery
+	34, // 11: index_service.SearchRequest.OnFlag:type_name -> util.Bitmap
+	34, // 12: index_service.SearchRequest.OffFlag:type_name -> util.Bitmap
+	34, // 13: index_service.SearchRequest.OrFlags:type_name -> util.Bitmap
+	5,  // 14: index_service.SearchRequest.Aggregations:type_name -> index_service.Aggregation
+	8,  // 15: index_service.SearchRequest.Highlight:type_name -> index_service.Highlight
+	29, // 16: index_service.SearchBatch.Docs:type_name -> types.Document
+	10, // 17: index_service.SearchBatch.Highlights:type_name -> index_service.DocHighlight
+	29, // 18: index_service.SearchResult.Results:type_name -> types.Document
+	7,  // 19: index_service.SearchResult.Aggregations:type_name -> index_service.AggregationResult
+	10, // 20: index_service.SearchResult.Highlights:type_name -> index_service.DocHighlight
+	29, // 21: index_service.GetDocResult.Doc:type_name -> types.Document
+	29, // 22: index_service.MultiGetResult.Docs:type_name -> types.Document
+	22, // 23: index_service.IndexList.Indexes:type_name -> index_service.IndexInfo
+	24, // 24: index_service.BulkAddResult.Items:type_name -> index_service.BulkItem
+	27, // 25: index_service.SuggestResult.Suggestions:type_name -> index_service.Suggestion
+	2,  // 26: index_service.IndexService.DeleteDoc:input_type -> index_service.DocId
+	29, // 27: index_service.IndexService.AddDoc:input_type -> types.Document
+	3,  // 28: index_service.IndexService.AddDocTo:input_type -> index_service.AddDocRequest
+	3,  // 29: index_service.IndexService.BulkAdd:input_type -> index_service.AddDocRequest
+	11, // 30: index_service.IndexService.UpdateDoc:input_type -> index_service.UpdateDocRequest
+	12, // 31: index_service.IndexService.Search:input_type -> index_service.SearchRequest
+	12, // 32: index_service.IndexService.SearchStream:input_type -> index_service.SearchRequest
+	18, // 33: index_service.IndexService.Count:input_type -> index_service.CountRequest
+	19, // 34: index_service.IndexService.CreateIndex:input_type -> index_service.CreateIndexRequest
+	20, // 35: index_service.IndexService.DeleteIndex:input_type -> index_service.IndexName
+	21, // 36: index_service.IndexService.ListIndex:input_type -> index_service.ListIndexRequest
+	26, // 37: index_service.IndexService.Suggest:input_type -> index_service.SuggestRequest
+	2,  // 38: index_service.IndexService.GetDoc:input_type -> index_service.DocId
+	16, // 39: index_service.IndexService.MultiGetDoc:input_type -> index_service.MultiGetRequest
+	4,  // 40: index_service.IndexService.DeleteDoc:output_type -> index_service.AffectedCount
+	4,  // 41: index_service.IndexService.AddDoc:output_type -> index_service.AffectedCount
+	4,  // 42: index_service.IndexService.AddDocTo:output_type -> index_service.AffectedCount
+	25, // 43: index_service.IndexService.BulkAdd:output_type -> index_service.BulkAddResult
+	4,  // 44: index_service.IndexService.UpdateDoc:output_type -> index_service.AffectedCount
+	14, // 45: index_service.IndexService.Search:output_type -> index_service.SearchResult
+	13, // 46: index_service.IndexService.SearchStream:output_type -> index_service.SearchBatch
+	4,  // 47: index_service.IndexService.Count:output_type -> index_service.AffectedCount
+	4,  // 48: index_service.IndexService.CreateIndex:output_type -> index_service.AffectedCount
+	4,  // 49: index_service.IndexService.DeleteIndex:output_type -> index_service.AffectedCount
+	23, // 50: index_service.IndexService.ListIndex:output_type -> index_service.IndexList
+	28, // 51: index_service.IndexService.Suggest:output_type -> index_service.SuggestResult
+	15, // 52: index_service.IndexService.GetDoc:output_type -> index_service.GetDocResult
+	17, // 53: index_service.IndexService.MultiGetDoc:output_type -> index_service.MultiGetResult
+	40, // [40:54] is the sub-list for method output_type
+	26, // [26:40] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
 }
 
 func init() { file_index_proto_init() }
@@ -374,7 +2380,7 @@ func file_index_proto_init() {
 	}
 	if !protoimpl.UnsafeEnabled {
 		file_index_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DocId); i {
+			switch v := v.(*Condition); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -386,7 +2392,7 @@ func file_index_proto_init() {
 			}
 		}
 		file_index_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AffectedCount); i {
+			switch v := v.(*DocId); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -398,7 +2404,7 @@ func file_index_proto_init() {
 			}
 		}
 		file_index_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SearchRequest); i {
+			switch v := v.(*AddDocRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -410,7 +2416,7 @@ func file_index_proto_init() {
 			}
 		}
 		file_index_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SearchResult); i {
+			switch v := v.(*AffectedCount); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -422,6 +2428,162 @@ func file_index_proto_init() {
 			}
 		}
 		file_index_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Aggregation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_index_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Bucket); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_index_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AggregationResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_index_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Highlight); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_index_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HighlightField); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_index_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DocHighlight); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_index_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateDocRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_index_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SearchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_index_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SearchBatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_index_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SearchResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_index_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetDocResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_index_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MultiGetRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_index_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MultiGetResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_index_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*CountRequest); i {
 			case 0:
 				return &v.state
@@ -433,19 +2595,140 @@ func file_index_proto_init() {
 				return nil
 			}
 		}
+		file_index_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateIndexRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_index_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IndexName); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_index_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListIndexRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_index_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IndexInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_index_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IndexList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_index_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BulkItem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_index_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BulkAddResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_index_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SuggestRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_index_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Suggestion); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_index_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SuggestResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_index_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   5,
+			NumEnums:      1,
+			NumMessages:   28,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_index_proto_goTypes,
 		DependencyIndexes: file_index_proto_depIdxs,
+		EnumInfos:         file_index_proto_enumTypes,
 		MessageInfos:      file_index_proto_msgTypes,
 	}.Build()
 	File_index_proto = out.File
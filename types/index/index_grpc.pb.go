@@ -25,8 +25,18 @@ const _ = grpc.SupportPackageIsVersion7
 type IndexServiceClient interface {
 	DeleteDoc(ctx context.Context, in *DocId, opts ...grpc.CallOption) (*AffectedCount, error)
 	AddDoc(ctx context.Context, in *doc.Document, opts ...grpc.CallOption) (*AffectedCount, error)
+	AddDocTo(ctx context.Context, in *AddDocRequest, opts ...grpc.CallOption) (*AffectedCount, error)
+	BulkAdd(ctx context.Context, opts ...grpc.CallOption) (IndexService_BulkAddClient, error)
+	UpdateDoc(ctx context.Context, in *UpdateDocRequest, opts ...grpc.CallOption) (*AffectedCount, error)
 	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResult, error)
+	SearchStream(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (IndexService_SearchStreamClient, error)
 	Count(ctx context.Context, in *CountRequest, opts ...grpc.CallOption) (*AffectedCount, error)
+	CreateIndex(ctx context.Context, in *CreateIndexRequest, opts ...grpc.CallOption) (*AffectedCount, error)
+	DeleteIndex(ctx context.Context, in *IndexName, opts ...grpc.CallOption) (*AffectedCount, error)
+	ListIndex(ctx context.Context, in *ListIndexRequest, opts ...grpc.CallOption) (*IndexList, error)
+	Suggest(ctx context.Context, in *SuggestRequest, opts ...grpc.CallOption) (*SuggestResult, error)
+	GetDoc(ctx context.Context, in *DocId, opts ...grpc.CallOption) (*GetDocResult, error)
+	MultiGetDoc(ctx context.Context, in *MultiGetRequest, opts ...grpc.CallOption) (*MultiGetResult, error)
 }
 
 type indexServiceClient struct {
@@ -55,6 +65,58 @@ func (c *indexServiceClient) AddDoc(ctx context.Context, in *doc.Document, opts
 	return out, nil
 }
 
+func (c *indexServiceClient) AddDocTo(ctx context.Context, in *AddDocRequest, opts ...grpc.CallOption) (*AffectedCount, error) {
+	out := new(AffectedCount)
+	err := c.cc.Invoke(ctx, "/index_service.IndexService/AddDocTo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *indexServiceClient) BulkAdd(ctx context.Context, opts ...grpc.CallOption) (IndexService_BulkAddClient, error) {
+	stream, err := c.cc.NewStream(ctx, &IndexService_ServiceDesc.Streams[0], "/index_service.IndexService/BulkAdd", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &indexServiceBulkAddClient{stream}
+	return x, nil
+}
+
+type IndexService_BulkAddClient interface {
+	Send(*AddDocRequest) error
+	CloseAndRecv() (*BulkAddResult, error)
+	grpc.ClientStream
+}
+
+type indexServiceBulkAddClient struct {
+	grpc.ClientStream
+}
+
+func (x *indexServiceBulkAddClient) Send(m *AddDocRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *indexServiceBulkAddClient) CloseAndRecv() (*BulkAddResult, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(BulkAddResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *indexServiceClient) UpdateDoc(ctx context.Context, in *UpdateDocRequest, opts ...grpc.CallOption) (*AffectedCount, error) {
+	out := new(AffectedCount)
+	err := c.cc.Invoke(ctx, "/index_service.IndexService/UpdateDoc", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *indexServiceClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResult, error) {
 	out := new(SearchResult)
 	err := c.cc.Invoke(ctx, "/index_service.IndexService/Search", in, out, opts...)
@@ -64,6 +126,38 @@ func (c *indexServiceClient) Search(ctx context.Context, in *SearchRequest, opts
 	return out, nil
 }
 
+func (c *indexServiceClient) SearchStream(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (IndexService_SearchStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &IndexService_ServiceDesc.Streams[1], "/index_service.IndexService/SearchStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &indexServiceSearchStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type IndexService_SearchStreamClient interface {
+	Recv() (*SearchBatch, error)
+	grpc.ClientStream
+}
+
+type indexServiceSearchStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *indexServiceSearchStreamClient) Recv() (*SearchBatch, error) {
+	m := new(SearchBatch)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *indexServiceClient) Count(ctx context.Context, in *CountRequest, opts ...grpc.CallOption) (*AffectedCount, error) {
 	out := new(AffectedCount)
 	err := c.cc.Invoke(ctx, "/index_service.IndexService/Count", in, out, opts...)
@@ -73,14 +167,78 @@ func (c *indexServiceClient) Count(ctx context.Context, in *CountRequest, opts .
 	return out, nil
 }
 
+func (c *indexServiceClient) CreateIndex(ctx context.Context, in *CreateIndexRequest, opts ...grpc.CallOption) (*AffectedCount, error) {
+	out := new(AffectedCount)
+	err := c.cc.Invoke(ctx, "/index_service.IndexService/CreateIndex", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *indexServiceClient) DeleteIndex(ctx context.Context, in *IndexName, opts ...grpc.CallOption) (*AffectedCount, error) {
+	out := new(AffectedCount)
+	err := c.cc.Invoke(ctx, "/index_service.IndexService/DeleteIndex", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *indexServiceClient) ListIndex(ctx context.Context, in *ListIndexRequest, opts ...grpc.CallOption) (*IndexList, error) {
+	out := new(IndexList)
+	err := c.cc.Invoke(ctx, "/index_service.IndexService/ListIndex", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *indexServiceClient) Suggest(ctx context.Context, in *SuggestRequest, opts ...grpc.CallOption) (*SuggestResult, error) {
+	out := new(SuggestResult)
+	err := c.cc.Invoke(ctx, "/index_service.IndexService/Suggest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *indexServiceClient) GetDoc(ctx context.Context, in *DocId, opts ...grpc.CallOption) (*GetDocResult, error) {
+	out := new(GetDocResult)
+	err := c.cc.Invoke(ctx, "/index_service.IndexService/GetDoc", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *indexServiceClient) MultiGetDoc(ctx context.Context, in *MultiGetRequest, opts ...grpc.CallOption) (*MultiGetResult, error) {
+	out := new(MultiGetResult)
+	err := c.cc.Invoke(ctx, "/index_service.IndexService/MultiGetDoc", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // IndexServiceServer is the server API for IndexService service.
 // All implementations must embed UnimplementedIndexServiceServer
 // for forward compatibility
 type IndexServiceServer interface {
 	DeleteDoc(context.Context, *DocId) (*AffectedCount, error)
 	AddDoc(context.Context, *doc.Document) (*AffectedCount, error)
+	AddDocTo(context.Context, *AddDocRequest) (*AffectedCount, error)
+	BulkAdd(IndexService_BulkAddServer) error
+	UpdateDoc(context.Context, *UpdateDocRequest) (*AffectedCount, error)
 	Search(context.Context, *SearchRequest) (*SearchResult, error)
+	SearchStream(*SearchRequest, IndexService_SearchStreamServer) error
 	Count(context.Context, *CountRequest) (*AffectedCount, error)
+	CreateIndex(context.Context, *CreateIndexRequest) (*AffectedCount, error)
+	DeleteIndex(context.Context, *IndexName) (*AffectedCount, error)
+	ListIndex(context.Context, *ListIndexRequest) (*IndexList, error)
+	Suggest(context.Context, *SuggestRequest) (*SuggestResult, error)
+	GetDoc(context.Context, *DocId) (*GetDocResult, error)
+	MultiGetDoc(context.Context, *MultiGetRequest) (*MultiGetResult, error)
 	//mustEmbedUnimplementedIndexServiceServer()
 }
 
@@ -94,12 +252,42 @@ func (UnimplementedIndexServiceServer) DeleteDoc(context.Context, *DocId) (*Affe
 func (UnimplementedIndexServiceServer) AddDoc(context.Context, *doc.Document) (*AffectedCount, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AddDoc not implemented")
 }
+func (UnimplementedIndexServiceServer) AddDocTo(context.Context, *AddDocRequest) (*AffectedCount, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddDocTo not implemented")
+}
+func (UnimplementedIndexServiceServer) BulkAdd(IndexService_BulkAddServer) error {
+	return status.Errorf(codes.Unimplemented, "method BulkAdd not implemented")
+}
+func (UnimplementedIndexServiceServer) UpdateDoc(context.Context, *UpdateDocRequest) (*AffectedCount, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateDoc not implemented")
+}
 func (UnimplementedIndexServiceServer) Search(context.Context, *SearchRequest) (*SearchResult, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Search not implemented")
 }
+func (UnimplementedIndexServiceServer) SearchStream(*SearchRequest, IndexService_SearchStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method SearchStream not implemented")
+}
 func (UnimplementedIndexServiceServer) Count(context.Context, *CountRequest) (*AffectedCount, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Count not implemented")
 }
+func (UnimplementedIndexServiceServer) CreateIndex(context.Context, *CreateIndexRequest) (*AffectedCount, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateIndex not implemented")
+}
+func (UnimplementedIndexServiceServer) DeleteIndex(context.Context, *IndexName) (*AffectedCount, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteIndex not implemented")
+}
+func (UnimplementedIndexServiceServer) ListIndex(context.Context, *ListIndexRequest) (*IndexList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListIndex not implemented")
+}
+func (UnimplementedIndexServiceServer) Suggest(context.Context, *SuggestRequest) (*SuggestResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Suggest not implemented")
+}
+func (UnimplementedIndexServiceServer) GetDoc(context.Context, *DocId) (*GetDocResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDoc not implemented")
+}
+func (UnimplementedIndexServiceServer) MultiGetDoc(context.Context, *MultiGetRequest) (*MultiGetResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MultiGetDoc not implemented")
+}
 func (UnimplementedIndexServiceServer) mustEmbedUnimplementedIndexServiceServer() {}
 
 // UnsafeIndexServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -149,6 +337,68 @@ func _IndexService_AddDoc_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _IndexService_AddDocTo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddDocRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IndexServiceServer).AddDocTo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/index_service.IndexService/AddDocTo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IndexServiceServer).AddDocTo(ctx, req.(*AddDocRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IndexService_BulkAdd_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(IndexServiceServer).BulkAdd(&indexServiceBulkAddServer{stream})
+}
+
+type IndexService_BulkAddServer interface {
+	SendAndClose(*BulkAddResult) error
+	Recv() (*AddDocRequest, error)
+	grpc.ServerStream
+}
+
+type indexServiceBulkAddServer struct {
+	grpc.ServerStream
+}
+
+func (x *indexServiceBulkAddServer) SendAndClose(m *BulkAddResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *indexServiceBulkAddServer) Recv() (*AddDocRequest, error) {
+	m := new(AddDocRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _IndexService_UpdateDoc_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateDocRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IndexServiceServer).UpdateDoc(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/index_service.IndexService/UpdateDoc",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IndexServiceServer).UpdateDoc(ctx, req.(*UpdateDocRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _IndexService_Search_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SearchRequest)
 	if err := dec(in); err != nil {
@@ -167,6 +417,27 @@ func _IndexService_Search_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _IndexService_SearchStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(IndexServiceServer).SearchStream(m, &indexServiceSearchStreamServer{stream})
+}
+
+type IndexService_SearchStreamServer interface {
+	Send(*SearchBatch) error
+	grpc.ServerStream
+}
+
+type indexServiceSearchStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *indexServiceSearchStreamServer) Send(m *SearchBatch) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _IndexService_Count_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CountRequest)
 	if err := dec(in); err != nil {
@@ -185,6 +456,114 @@ func _IndexService_Count_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _IndexService_CreateIndex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateIndexRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IndexServiceServer).CreateIndex(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/index_service.IndexService/CreateIndex",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IndexServiceServer).CreateIndex(ctx, req.(*CreateIndexRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IndexService_DeleteIndex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IndexName)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IndexServiceServer).DeleteIndex(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/index_service.IndexService/DeleteIndex",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IndexServiceServer).DeleteIndex(ctx, req.(*IndexName))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IndexService_ListIndex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListIndexRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IndexServiceServer).ListIndex(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/index_service.IndexService/ListIndex",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IndexServiceServer).ListIndex(ctx, req.(*ListIndexRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IndexService_Suggest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IndexServiceServer).Suggest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/index_service.IndexService/Suggest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IndexServiceServer).Suggest(ctx, req.(*SuggestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IndexService_GetDoc_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DocId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IndexServiceServer).GetDoc(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/index_service.IndexService/GetDoc",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IndexServiceServer).GetDoc(ctx, req.(*DocId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IndexService_MultiGetDoc_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MultiGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IndexServiceServer).MultiGetDoc(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/index_service.IndexService/MultiGetDoc",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IndexServiceServer).MultiGetDoc(ctx, req.(*MultiGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // IndexService_ServiceDesc is the grpc.ServiceDesc for IndexService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -200,6 +579,14 @@ var IndexService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "AddDoc",
 			Handler:    _IndexService_AddDoc_Handler,
 		},
+		{
+			MethodName: "AddDocTo",
+			Handler:    _IndexService_AddDocTo_Handler,
+		},
+		{
+			MethodName: "UpdateDoc",
+			Handler:    _IndexService_UpdateDoc_Handler,
+		},
 		{
 			MethodName: "Search",
 			Handler:    _IndexService_Search_Handler,
@@ -208,7 +595,42 @@ var IndexService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Count",
 			Handler:    _IndexService_Count_Handler,
 		},
+		{
+			MethodName: "CreateIndex",
+			Handler:    _IndexService_CreateIndex_Handler,
+		},
+		{
+			MethodName: "DeleteIndex",
+			Handler:    _IndexService_DeleteIndex_Handler,
+		},
+		{
+			MethodName: "ListIndex",
+			Handler:    _IndexService_ListIndex_Handler,
+		},
+		{
+			MethodName: "Suggest",
+			Handler:    _IndexService_Suggest_Handler,
+		},
+		{
+			MethodName: "GetDoc",
+			Handler:    _IndexService_GetDoc_Handler,
+		},
+		{
+			MethodName: "MultiGetDoc",
+			Handler:    _IndexService_MultiGetDoc_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "BulkAdd",
+			Handler:       _IndexService_BulkAdd_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "SearchStream",
+			Handler:       _IndexService_SearchStream_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "index.proto",
 }
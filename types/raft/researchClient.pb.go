@@ -25,7 +25,7 @@ type ResearchRequest struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ReqType *int64   `protobuf:"varint,1,req,name=reqType" json:"reqType,omitempty"` // 1: add, 2: delete, 3: search
+	ReqType *int64   `protobuf:"varint,1,req,name=reqType" json:"reqType,omitempty"` // 1: add, 2: delete, 3: search, 4: update
 	Args    []string `protobuf:"bytes,2,rep,name=args" json:"args,omitempty"`
 }
 
@@ -0,0 +1,410 @@
+package term_query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// 查询语句解析，把用户输入的查询语句转为TermQuery
+// 语法：
+//
+//	query   := or
+//	or      := and (OR and)*
+//	and     := unary (AND? unary)*        相邻的条件之间省略AND
+//	unary   := NOT unary | primary
+//	primary := '(' or ')' | field ':' value | value
+//	value   := word | prefix* | wild*card? | word~ | word~fuzziness | "phrase" | "phrase"~slop | range
+//	range   := ('['|'{') bound TO bound (']'|'}') | (>|>=|<|<=) bound
+//	bound   := number | date | *
+//
+// 优先级从高到低为NOT、AND、OR，AND、OR、NOT必须大写，否则当作普通的词
+// NOT的条件从同一个AND里的其他条件中排除，所以同一个AND里至少要有一个不带NOT的条件
+// 短语中的词必须按顺序相邻出现，~slop表示词之间最多插入slop个词，短语内可以用\"和\\转义
+// 词以*结尾是前缀查询，其他位置带有*或?是通配符查询，以~结尾是模糊查询，~后面可以指定最大编辑距离
+// 范围查询中[]包含边界，{}不包含边界，*表示不限，日期格式为2006-01-02，按unix毫秒时间戳比较
+// 例如：title:go AND (tag:db OR tag:kv) NOT lang:java AND price:[10 TO 50] AND date:>=2026-01-01
+
+// 解析错误，Pos是出错位置(从0开始的字符偏移)
+type ParseError struct {
+	Pos int
+	Msg string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("parse query failed at position %d: %s", e.Pos, e.Msg)
+}
+
+type tokenType int
+
+const (
+	tokenEOF tokenType = iota
+	tokenWord
+	tokenPhrase
+	tokenColon
+	tokenLParen
+	tokenRParen
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenLBracket // [或{
+	tokenRBracket // ]或}
+)
+
+type token struct {
+	typ  tokenType
+	val  string
+	pos  int
+	slop int // 短语的slop
+}
+
+type Parser struct {
+	defaultField string // 没有写field时使用的field
+	tokens       []token
+	cur          int
+}
+
+func NewParser() *Parser {
+	return &Parser{}
+}
+
+func (p *Parser) WithDefaultField(field string) *Parser {
+	p.defaultField = field
+	return p
+}
+
+// 使用默认配置解析查询语句
+func Parse(query string) (*TermQuery, error) {
+	return NewParser().Parse(query)
+}
+
+// 解析查询语句
+func (p *Parser) Parse(query string) (*TermQuery, error) {
+	tokens, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+	p.tokens, p.cur = tokens, 0
+	if p.peek().typ == tokenEOF {
+		return nil, &ParseError{Pos: 0, Msg: "empty query"}
+	}
+	q, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.typ != tokenEOF {
+		return nil, &ParseError{Pos: tok.pos, Msg: fmt.Sprintf("unexpected %s", tok.describe())}
+	}
+	return q, nil
+}
+
+func (p *Parser) peek() token {
+	return p.tokens[p.cur]
+}
+
+func (p *Parser) next() token {
+	tok := p.tokens[p.cur]
+	if tok.typ != tokenEOF {
+		p.cur++
+	}
+	return tok
+}
+
+func (p *Parser) parseOr() (*TermQuery, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	res := []*TermQuery{first}
+	for p.peek().typ == tokenOr {
+		p.next()
+		q, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, q)
+	}
+	if len(res) == 1 {
+		return first, nil
+	}
+	return &TermQuery{Should: res}, nil
+}
+
+func (p *Parser) parseAnd() (*TermQuery, error) {
+	start := p.peek()
+	positives := make([]*TermQuery, 0, 2)
+	negatives := make([]*TermQuery, 0)
+	for {
+		q, negated, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if negated {
+			negatives = append(negatives, q)
+		} else {
+			positives = append(positives, q)
+		}
+
+		tok := p.peek()
+		if tok.typ == tokenAnd {
+			p.next()
+		} else if !tok.startsUnary() {
+			break
+		}
+	}
+	if len(positives) == 0 {
+		return nil, &ParseError{Pos: start.pos, Msg: "NOT must be used together with a condition without NOT"}
+	}
+	if len(negatives) > 0 {
+		return &TermQuery{Must: positives, MustNot: negatives}, nil
+	}
+	if len(positives) == 1 {
+		return positives[0], nil
+	}
+	return &TermQuery{Must: positives}, nil
+}
+
+// 解析一个条件，negated表示条件前有奇数个NOT
+func (p *Parser) parseUnary() (*TermQuery, bool, error) {
+	if p.peek().typ == tokenNot {
+		p.next()
+		q, negated, err := p.parseUnary()
+		return q, !negated, err
+	}
+	q, err := p.parsePrimary()
+	return q, false, err
+}
+
+func (p *Parser) parsePrimary() (*TermQuery, error) {
+	tok := p.next()
+	switch tok.typ {
+	case tokenLParen:
+		q, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if end := p.next(); end.typ != tokenRParen {
+			return nil, &ParseError{Pos: end.pos, Msg: fmt.Sprintf("expect ')' to close '(' at position %d, got %s", tok.pos, end.describe())}
+		}
+		return q, nil
+	case tokenWord:
+		if p.peek().typ == tokenColon {
+			p.next()
+			value := p.next()
+			if value.typ == tokenLBracket {
+				return p.parseRange(tok.val, value)
+			}
+			if value.typ != tokenWord && value.typ != tokenPhrase {
+				return nil, &ParseError{Pos: value.pos, Msg: fmt.Sprintf("expect value after '%s:', got %s", tok.val, value.describe())}
+			}
+			return p.term(tok.val, value)
+		}
+		return p.term(p.defaultField, tok)
+	case tokenPhrase:
+		return p.term(p.defaultField, tok)
+	case tokenLBracket:
+		return p.parseRange(p.defaultField, tok)
+	}
+	return nil, &ParseError{Pos: tok.pos, Msg: fmt.Sprintf("unexpected %s", tok.describe())}
+}
+
+// 解析[from TO to]形式的范围，open是左括号
+func (p *Parser) parseRange(field string, open token) (*TermQuery, error) {
+	if len(field) == 0 {
+		return nil, &ParseError{Pos: open.pos, Msg: "missing field for range"}
+	}
+	from := p.next()
+	if from.typ != tokenWord {
+		return nil, &ParseError{Pos: from.pos, Msg: fmt.Sprintf("expect lower bound, got %s", from.describe())}
+	}
+	if to := p.next(); to.typ != tokenWord || to.val != "TO" {
+		return nil, &ParseError{Pos: to.pos, Msg: fmt.Sprintf("expect 'TO', got %s", to.describe())}
+	}
+	to := p.next()
+	if to.typ != tokenWord {
+		return nil, &ParseError{Pos: to.pos, Msg: fmt.Sprintf("expect upper bound, got %s", to.describe())}
+	}
+	end := p.next()
+	if end.typ != tokenRBracket {
+		return nil, &ParseError{Pos: end.pos, Msg: fmt.Sprintf("expect ']' or '}' to close range at position %d, got %s", open.pos, end.describe())}
+	}
+	r := &Range{Field: field, ExcludeFrom: open.val == "{", ExcludeTo: end.val == "}"}
+	var err error
+	if r.From, err = parseBound(from.val, from.pos); err != nil {
+		return nil, err
+	}
+	if r.To, err = parseBound(to.val, to.pos); err != nil {
+		return nil, err
+	}
+	return &TermQuery{Range: r}, nil
+}
+
+// 解析范围的边界，*表示不限
+func parseBound(value string, pos int) (*float64, error) {
+	if value == "*" {
+		return nil, nil
+	}
+	if v, err := strconv.ParseFloat(value, 64); err == nil {
+		return &v, nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		v := float64(t.UnixMilli())
+		return &v, nil
+	}
+	return nil, &ParseError{Pos: pos, Msg: fmt.Sprintf("invalid number or date '%s'", value)}
+}
+
+// 生成叶子结点
+func (p *Parser) term(field string, value token) (*TermQuery, error) {
+	if len(field) == 0 {
+		return nil, &ParseError{Pos: value.pos, Msg: fmt.Sprintf("missing field for '%s'", value.val)}
+	}
+	if value.typ == tokenWord {
+		return wordQuery(field, value)
+	}
+	words := strings.Fields(value.val)
+	if len(words) == 0 {
+		return nil, &ParseError{Pos: value.pos, Msg: "empty phrase"}
+	}
+	if len(words) == 1 {
+		return NewTermQuery(field, words[0]), nil
+	}
+	return NewPhraseQuery(field, value.slop, words...), nil
+}
+
+// 根据词中的*、?、~生成前缀、通配符、模糊查询，否则生成关键词查询
+func wordQuery(field string, value token) (*TermQuery, error) {
+	word := value.val
+	// 比较运算符生成单边的范围
+	for _, op := range []string{">=", "<=", ">", "<"} {
+		if !strings.HasPrefix(word, op) {
+			continue
+		}
+		bound, err := parseBound(word[len(op):], value.pos+len(op))
+		if err != nil {
+			return nil, err
+		}
+		if bound == nil {
+			return nil, &ParseError{Pos: value.pos + len(op), Msg: fmt.Sprintf("expect number or date after '%s'", op)}
+		}
+		r := &Range{Field: field}
+		switch op {
+		case ">=", ">":
+			r.From, r.ExcludeFrom = bound, op == ">"
+		default:
+			r.To, r.ExcludeTo = bound, op == "<"
+		}
+		return &TermQuery{Range: r}, nil
+	}
+	if i := strings.LastIndexByte(word, '~'); i > 0 {
+		fuzziness := 0
+		for _, r := range word[i+1:] {
+			if r < '0' || r > '9' {
+				return nil, &ParseError{Pos: value.pos + len([]rune(word[:i])), Msg: "expect number after '~'"}
+			}
+			fuzziness = fuzziness*10 + int(r-'0')
+		}
+		return NewFuzzyQuery(field, word[:i], fuzziness), nil
+	}
+	if i := strings.IndexAny(word, "*?"); i >= 0 {
+		if i == len(word)-1 && word[i] == '*' {
+			return NewPrefixQuery(field, word[:i]), nil
+		}
+		return NewWildcardQuery(field, word), nil
+	}
+	return NewTermQuery(field, word), nil
+}
+
+// 能否作为一个条件的开始，用于判断是否省略了AND
+func (t token) startsUnary() bool {
+	switch t.typ {
+	case tokenWord, tokenPhrase, tokenLParen, tokenLBracket, tokenNot:
+		return true
+	}
+	return false
+}
+
+func (t token) describe() string {
+	switch t.typ {
+	case tokenEOF:
+		return "end of query"
+	case tokenWord:
+		return fmt.Sprintf("word '%s'", t.val)
+	case tokenPhrase:
+		return fmt.Sprintf("phrase \"%s\"", t.val)
+	}
+	return fmt.Sprintf("'%s'", t.val)
+}
+
+// 词法分析
+func lex(query string) ([]token, error) {
+	runes := []rune(query)
+	tokens := make([]token, 0, 16)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{typ: tokenLParen, val: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{typ: tokenRParen, val: ")", pos: i})
+			i++
+		case r == ':':
+			tokens = append(tokens, token{typ: tokenColon, val: ":", pos: i})
+			i++
+		case r == '[' || r == '{':
+			tokens = append(tokens, token{typ: tokenLBracket, val: string(r), pos: i})
+			i++
+		case r == ']' || r == '}':
+			tokens = append(tokens, token{typ: tokenRBracket, val: string(r), pos: i})
+			i++
+		case r == '"':
+			start := i
+			phrase := strings.Builder{}
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				phrase.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, &ParseError{Pos: start, Msg: "unterminated phrase"}
+			}
+			i++
+			tok := token{typ: tokenPhrase, val: phrase.String(), pos: start}
+			if i < len(runes) && runes[i] == '~' {
+				// 短语后面的~slop
+				slopPos := i
+				for i++; i < len(runes) && runes[i] >= '0' && runes[i] <= '9'; i++ {
+					tok.slop = tok.slop*10 + int(runes[i]-'0')
+				}
+				if i == slopPos+1 {
+					return nil, &ParseError{Pos: slopPos, Msg: "expect number after '~'"}
+				}
+			}
+			tokens = append(tokens, tok)
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune(`():"[]{}`, runes[i]) {
+				i++
+			}
+			word := string(runes[start:i])
+			tok := token{typ: tokenWord, val: word, pos: start}
+			switch word {
+			case "AND":
+				tok.typ = tokenAnd
+			case "OR":
+				tok.typ = tokenOr
+			case "NOT":
+				tok.typ = tokenNot
+			}
+			tokens = append(tokens, tok)
+		}
+	}
+	return append(tokens, token{typ: tokenEOF, pos: len(runes)}), nil
+}
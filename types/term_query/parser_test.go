@@ -0,0 +1,97 @@
+package term_query
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 把表达式中的分隔符换成:，方便对比
+func expr(q *TermQuery) string {
+	return strings.ReplaceAll(q.ToString(), "\001", ":")
+}
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		query, want string
+	}{
+		{`title:go`, `title:go`},
+		{`title:go tag:db`, `(title:go&tag:db)`},
+		{`title:go AND (tag:db OR tag:kv) NOT lang:java`, `((title:go&(tag:db|tag:kv))&!lang:java)`},
+		{`title:a OR title:b tag:c`, `(title:a|(title:b&tag:c))`},
+		{`NOT NOT title:a`, `title:a`},
+		{`title:"hello world"~2`, `title:"hello world"~2`},
+		{`title:"single"`, `title:single`},
+		{`title:"say \"hi\" now"`, `title:"say "hi" now"`},
+		{`title:go*`, `title:go*`},
+		{`title:g?o*x`, `title:g?o*x`},
+		{`title:gopher~`, `title:gopher~`},
+		{`title:gopher~1`, `title:gopher~1`},
+		{`price:[10 TO 50]`, `price:[10,50]`},
+		{`price:{10 TO *]`, `price:(10,*]`},
+		{`price:<5`, `price:[*,5)`},
+		{`price:>=5`, `price:[5,*]`},
+	}
+	for _, c := range cases {
+		q, err := Parse(c.query)
+		if err != nil {
+			t.Errorf("%s: %s", c.query, err)
+			continue
+		}
+		if got := expr(q); got != c.want {
+			t.Errorf("%s: got %s, want %s", c.query, got, c.want)
+		}
+	}
+}
+
+func TestParseDefaultField(t *testing.T) {
+	q, err := NewParser().WithDefaultField("body").Parse(`go "a b" [1 TO 2]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := expr(q); got != `(body:go&body:"a b"&body:[1,2])` {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	q, err := Parse(`date:>=2026-01-01`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := float64(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli())
+	if r := q.Range; r == nil || r.From == nil || *r.From != want || r.To != nil {
+		t.Errorf("got %s", expr(q))
+	}
+}
+
+func TestParseError(t *testing.T) {
+	cases := []struct {
+		query string
+		pos   int
+	}{
+		{``, 0},
+		{`go`, 0},
+		{`title:`, 6},
+		{`(title:a`, 8},
+		{`NOT title:a`, 0},
+		{`price:[a TO 5]`, 7},
+		{`price:[1 5]`, 9},
+		{`[1 TO 2]`, 0},
+		{`"abc`, 0},
+		{`title:a~x`, 7},
+		{`title:a )`, 8},
+	}
+	for _, c := range cases {
+		_, err := Parse(c.query)
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("%q: got %v, want ParseError", c.query, err)
+			continue
+		}
+		if parseErr.Pos != c.pos {
+			t.Errorf("%q: error at %d, want %d: %s", c.query, parseErr.Pos, c.pos, err)
+		}
+	}
+}
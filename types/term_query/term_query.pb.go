@@ -21,14 +21,67 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type MultiTerm_MultiTermType int32
+
+const (
+	MultiTerm_PREFIX   MultiTerm_MultiTermType = 0 //前缀
+	MultiTerm_WILDCARD MultiTerm_MultiTermType = 1 //通配符，*匹配任意个字符，?匹配一个字符
+	MultiTerm_FUZZY    MultiTerm_MultiTermType = 2 //编辑距离
+)
+
+// Enum value maps for MultiTerm_MultiTermType.
+var (
+	MultiTerm_MultiTermType_name = map[int32]string{
+		0: "PREFIX",
+		1: "WILDCARD",
+		2: "FUZZY",
+	}
+	MultiTerm_MultiTermType_value = map[string]int32{
+		"PREFIX":   0,
+		"WILDCARD": 1,
+		"FUZZY":    2,
+	}
+)
+
+func (x MultiTerm_MultiTermType) Enum() *MultiTerm_MultiTermType {
+	p := new(MultiTerm_MultiTermType)
+	*p = x
+	return p
+}
+
+func (x MultiTerm_MultiTermType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MultiTerm_MultiTermType) Descriptor() protoreflect.EnumDescriptor {
+	return file_term_query_proto_enumTypes[0].Descriptor()
+}
+
+func (MultiTerm_MultiTermType) Type() protoreflect.EnumType {
+	return &file_term_query_proto_enumTypes[0]
+}
+
+func (x MultiTerm_MultiTermType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MultiTerm_MultiTermType.Descriptor instead.
+func (MultiTerm_MultiTermType) EnumDescriptor() ([]byte, []int) {
+	return file_term_query_proto_rawDescGZIP(), []int{3, 0}
+}
+
 type TermQuery struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Keyword *doc.KeyWord `protobuf:"bytes,1,opt,name=Keyword,proto3" json:"Keyword,omitempty"` //Keyword类型引用自doc.proto
-	Must    []*TermQuery `protobuf:"bytes,2,rep,name=Must,proto3" json:"Must,omitempty"`
-	Should  []*TermQuery `protobuf:"bytes,3,rep,name=Should,proto3" json:"Should,omitempty"`
+	Keyword   *doc.KeyWord `protobuf:"bytes,1,opt,name=Keyword,proto3" json:"Keyword,omitempty"` //Keyword类型引用自doc.proto
+	Must      []*TermQuery `protobuf:"bytes,2,rep,name=Must,proto3" json:"Must,omitempty"`
+	Should    []*TermQuery `protobuf:"bytes,3,rep,name=Should,proto3" json:"Should,omitempty"`
+	MustNot   []*TermQuery `protobuf:"bytes,4,rep,name=MustNot,proto3" json:"MustNot,omitempty"`     //从Keyword、Must、Should的结果中排除，单独使用时没有结果
+	Phrase    *Phrase      `protobuf:"bytes,5,opt,name=Phrase,proto3" json:"Phrase,omitempty"`       //短语，和Keyword一样是叶子结点
+	MultiTerm *MultiTerm   `protobuf:"bytes,6,opt,name=MultiTerm,proto3" json:"MultiTerm,omitempty"` //前缀、通配符、模糊查询，展开成多个关键词求并集，也是叶子结点
+	Range     *Range       `protobuf:"bytes,7,opt,name=Range,proto3" json:"Range,omitempty"`         //数值或日期范围，也是叶子结点
 }
 
 func (x *TermQuery) Reset() {
@@ -84,12 +137,272 @@ func (x *TermQuery) GetShould() []*TermQuery {
 	return nil
 }
 
+func (x *TermQuery) GetMustNot() []*TermQuery {
+	if x != nil {
+		return x.MustNot
+	}
+	return nil
+}
+
+func (x *TermQuery) GetPhrase() *Phrase {
+	if x != nil {
+		return x.Phrase
+	}
+	return nil
+}
+
+func (x *TermQuery) GetMultiTerm() *MultiTerm {
+	if x != nil {
+		return x.MultiTerm
+	}
+	return nil
+}
+
+func (x *TermQuery) GetRange() *Range {
+	if x != nil {
+		return x.Range
+	}
+	return nil
+}
+
+// 数值字段的范围
+type Range struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Field       string   `protobuf:"bytes,1,opt,name=Field,proto3" json:"Field,omitempty"`
+	From        *float64 `protobuf:"fixed64,2,opt,name=From,proto3,oneof" json:"From,omitempty"`        //下界，为空表示不限
+	To          *float64 `protobuf:"fixed64,3,opt,name=To,proto3,oneof" json:"To,omitempty"`            //上界，为空表示不限
+	ExcludeFrom bool     `protobuf:"varint,4,opt,name=ExcludeFrom,proto3" json:"ExcludeFrom,omitempty"` //不包含下界
+	ExcludeTo   bool     `protobuf:"varint,5,opt,name=ExcludeTo,proto3" json:"ExcludeTo,omitempty"`     //不包含上界
+}
+
+func (x *Range) Reset() {
+	*x = Range{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_term_query_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Range) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Range) ProtoMessage() {}
+
+func (x *Range) ProtoReflect() protoreflect.Message {
+	mi := &file_term_query_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Range.ProtoReflect.Descriptor instead.
+func (*Range) Descriptor() ([]byte, []int) {
+	return file_term_query_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Range) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *Range) GetFrom() float64 {
+	if x != nil && x.From != nil {
+		return *x.From
+	}
+	return 0
+}
+
+func (x *Range) GetTo() float64 {
+	if x != nil && x.To != nil {
+		return *x.To
+	}
+	return 0
+}
+
+func (x *Range) GetExcludeFrom() bool {
+	if x != nil {
+		return x.ExcludeFrom
+	}
+	return false
+}
+
+func (x *Range) GetExcludeTo() bool {
+	if x != nil {
+		return x.ExcludeTo
+	}
+	return false
+}
+
+// 同一Field中按顺序出现的多个词
+type Phrase struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Field string   `protobuf:"bytes,1,opt,name=Field,proto3" json:"Field,omitempty"`
+	Words []string `protobuf:"bytes,2,rep,name=Words,proto3" json:"Words,omitempty"`
+	Slop  int32    `protobuf:"varint,3,opt,name=Slop,proto3" json:"Slop,omitempty"` //词之间最多插入的词数之和，0表示词必须相邻
+}
+
+func (x *Phrase) Reset() {
+	*x = Phrase{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_term_query_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Phrase) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Phrase) ProtoMessage() {}
+
+func (x *Phrase) ProtoReflect() protoreflect.Message {
+	mi := &file_term_query_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Phrase.ProtoReflect.Descriptor instead.
+func (*Phrase) Descriptor() ([]byte, []int) {
+	return file_term_query_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Phrase) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *Phrase) GetWords() []string {
+	if x != nil {
+		return x.Words
+	}
+	return nil
+}
+
+func (x *Phrase) GetSlop() int32 {
+	if x != nil {
+		return x.Slop
+	}
+	return 0
+}
+
+// 从词典中展开成多个关键词的查询
+type MultiTerm struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type          MultiTerm_MultiTermType `protobuf:"varint,1,opt,name=Type,proto3,enum=types.MultiTerm_MultiTermType" json:"Type,omitempty"`
+	Field         string                  `protobuf:"bytes,2,opt,name=Field,proto3" json:"Field,omitempty"`
+	Pattern       string                  `protobuf:"bytes,3,opt,name=Pattern,proto3" json:"Pattern,omitempty"`              //前缀、通配符表达式或模糊查询的词
+	Fuzziness     int32                   `protobuf:"varint,4,opt,name=Fuzziness,proto3" json:"Fuzziness,omitempty"`         //模糊查询的最大编辑距离，0表示根据词长自动选择
+	MaxExpansions int32                   `protobuf:"varint,5,opt,name=MaxExpansions,proto3" json:"MaxExpansions,omitempty"` //最多展开的关键词数量，0表示使用默认值
+	PrefixLength  int32                   `protobuf:"varint,6,opt,name=PrefixLength,proto3" json:"PrefixLength,omitempty"`   //模糊查询的词开头必须相同的字符数，只在这个前缀下查找，0表示不要求
+}
+
+func (x *MultiTerm) Reset() {
+	*x = MultiTerm{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_term_query_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MultiTerm) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MultiTerm) ProtoMessage() {}
+
+func (x *MultiTerm) ProtoReflect() protoreflect.Message {
+	mi := &file_term_query_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MultiTerm.ProtoReflect.Descriptor instead.
+func (*MultiTerm) Descriptor() ([]byte, []int) {
+	return file_term_query_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *MultiTerm) GetType() MultiTerm_MultiTermType {
+	if x != nil {
+		return x.Type
+	}
+	return MultiTerm_PREFIX
+}
+
+func (x *MultiTerm) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *MultiTerm) GetPattern() string {
+	if x != nil {
+		return x.Pattern
+	}
+	return ""
+}
+
+func (x *MultiTerm) GetFuzziness() int32 {
+	if x != nil {
+		return x.Fuzziness
+	}
+	return 0
+}
+
+func (x *MultiTerm) GetMaxExpansions() int32 {
+	if x != nil {
+		return x.MaxExpansions
+	}
+	return 0
+}
+
+func (x *MultiTerm) GetPrefixLength() int32 {
+	if x != nil {
+		return x.PrefixLength
+	}
+	return 0
+}
+
 var File_term_query_proto protoreflect.FileDescriptor
 
 var file_term_query_proto_rawDesc = []byte{
 	0x0a, 0x10, 0x74, 0x65, 0x72, 0x6d, 0x5f, 0x71, 0x75, 0x65, 0x72, 0x79, 0x2e, 0x70, 0x72, 0x6f,
 	0x74, 0x6f, 0x12, 0x05, 0x74, 0x79, 0x70, 0x65, 0x73, 0x1a, 0x0f, 0x74, 0x79, 0x70, 0x65, 0x73,
-	0x2f, 0x64, 0x6f, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x85, 0x01, 0x0a, 0x09, 0x54,
+	0x2f, 0x64, 0x6f, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xac, 0x02, 0x0a, 0x09, 0x54,
 	0x65, 0x72, 0x6d, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12, 0x28, 0x0a, 0x07, 0x4b, 0x65, 0x79, 0x77,
 	0x6f, 0x72, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x74, 0x79, 0x70, 0x65,
 	0x73, 0x2e, 0x4b, 0x65, 0x79, 0x57, 0x6f, 0x72, 0x64, 0x52, 0x07, 0x4b, 0x65, 0x79, 0x77, 0x6f,
@@ -98,8 +411,51 @@ var file_term_query_proto_rawDesc = []byte{
 	0x72, 0x79, 0x52, 0x04, 0x4d, 0x75, 0x73, 0x74, 0x12, 0x28, 0x0a, 0x06, 0x53, 0x68, 0x6f, 0x75,
 	0x6c, 0x64, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73,
 	0x2e, 0x54, 0x65, 0x72, 0x6d, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x06, 0x53, 0x68, 0x6f, 0x75,
-	0x6c, 0x64, 0x42, 0x0e, 0x5a, 0x0c, 0x2e, 0x2f, 0x74, 0x65, 0x72, 0x6d, 0x5f, 0x71, 0x75, 0x65,
-	0x72, 0x79, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6c, 0x64, 0x12, 0x2a, 0x0a, 0x07, 0x4d, 0x75, 0x73, 0x74, 0x4e, 0x6f, 0x74, 0x18, 0x04, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x54, 0x65, 0x72, 0x6d,
+	0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x07, 0x4d, 0x75, 0x73, 0x74, 0x4e, 0x6f, 0x74, 0x12, 0x25,
+	0x0a, 0x06, 0x50, 0x68, 0x72, 0x61, 0x73, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d,
+	0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x50, 0x68, 0x72, 0x61, 0x73, 0x65, 0x52, 0x06, 0x50,
+	0x68, 0x72, 0x61, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x09, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x54, 0x65,
+	0x72, 0x6d, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73,
+	0x2e, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x54, 0x65, 0x72, 0x6d, 0x52, 0x09, 0x4d, 0x75, 0x6c, 0x74,
+	0x69, 0x54, 0x65, 0x72, 0x6d, 0x12, 0x22, 0x0a, 0x05, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x52, 0x61, 0x6e,
+	0x67, 0x65, 0x52, 0x05, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x22, 0x9b, 0x01, 0x0a, 0x05, 0x52, 0x61,
+	0x6e, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x17, 0x0a, 0x04, 0x46, 0x72, 0x6f,
+	0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x04, 0x46, 0x72, 0x6f, 0x6d, 0x88,
+	0x01, 0x01, 0x12, 0x13, 0x0a, 0x02, 0x54, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x48, 0x01,
+	0x52, 0x02, 0x54, 0x6f, 0x88, 0x01, 0x01, 0x12, 0x20, 0x0a, 0x0b, 0x45, 0x78, 0x63, 0x6c, 0x75,
+	0x64, 0x65, 0x46, 0x72, 0x6f, 0x6d, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x45, 0x78,
+	0x63, 0x6c, 0x75, 0x64, 0x65, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x1c, 0x0a, 0x09, 0x45, 0x78, 0x63,
+	0x6c, 0x75, 0x64, 0x65, 0x54, 0x6f, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x45, 0x78,
+	0x63, 0x6c, 0x75, 0x64, 0x65, 0x54, 0x6f, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x46, 0x72, 0x6f, 0x6d,
+	0x42, 0x05, 0x0a, 0x03, 0x5f, 0x54, 0x6f, 0x22, 0x48, 0x0a, 0x06, 0x50, 0x68, 0x72, 0x61, 0x73,
+	0x65, 0x12, 0x14, 0x0a, 0x05, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x57, 0x6f, 0x72, 0x64, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x57, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x12, 0x0a,
+	0x04, 0x53, 0x6c, 0x6f, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x53, 0x6c, 0x6f,
+	0x70, 0x22, 0x8d, 0x02, 0x0a, 0x09, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x54, 0x65, 0x72, 0x6d, 0x12,
+	0x32, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e,
+	0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x54, 0x65, 0x72, 0x6d, 0x2e,
+	0x4d, 0x75, 0x6c, 0x74, 0x69, 0x54, 0x65, 0x72, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x54,
+	0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x50, 0x61, 0x74,
+	0x74, 0x65, 0x72, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x50, 0x61, 0x74, 0x74,
+	0x65, 0x72, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x46, 0x75, 0x7a, 0x7a, 0x69, 0x6e, 0x65, 0x73, 0x73,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x46, 0x75, 0x7a, 0x7a, 0x69, 0x6e, 0x65, 0x73,
+	0x73, 0x12, 0x24, 0x0a, 0x0d, 0x4d, 0x61, 0x78, 0x45, 0x78, 0x70, 0x61, 0x6e, 0x73, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x4d, 0x61, 0x78, 0x45, 0x78, 0x70,
+	0x61, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x50, 0x72, 0x65, 0x66, 0x69,
+	0x78, 0x4c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x50,
+	0x72, 0x65, 0x66, 0x69, 0x78, 0x4c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x22, 0x34, 0x0a, 0x0d, 0x4d,
+	0x75, 0x6c, 0x74, 0x69, 0x54, 0x65, 0x72, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0a, 0x0a, 0x06,
+	0x50, 0x52, 0x45, 0x46, 0x49, 0x58, 0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x57, 0x49, 0x4c, 0x44,
+	0x43, 0x41, 0x52, 0x44, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x46, 0x55, 0x5a, 0x5a, 0x59, 0x10,
+	0x02, 0x42, 0x1b, 0x5a, 0x19, 0x52, 0x65, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x2f, 0x74, 0x79,
+	0x70, 0x65, 0x73, 0x2f, 0x74, 0x65, 0x72, 0x6d, 0x5f, 0x71, 0x75, 0x65, 0x72, 0x79, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -114,20 +470,30 @@ func file_term_query_proto_rawDescGZIP() []byte {
 	return file_term_query_proto_rawDescData
 }
 
-var file_term_query_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_term_query_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_term_query_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_term_query_proto_goTypes = []interface{}{
-	(*TermQuery)(nil),   // 0: types.TermQuery
-	(*doc.KeyWord)(nil), // 1: types.KeyWord
+	(MultiTerm_MultiTermType)(0), // 0: types.MultiTerm.MultiTermType
+	(*TermQuery)(nil),            // 1: types.TermQuery
+	(*Range)(nil),                // 2: types.Range
+	(*Phrase)(nil),               // 3: types.Phrase
+	(*MultiTerm)(nil),            // 4: types.MultiTerm
+	(*doc.KeyWord)(nil),          // 5: types.KeyWord
 }
 var file_term_query_proto_depIdxs = []int32{
-	1, // 0: types.TermQuery.Keyword:type_name -> types.KeyWord
-	0, // 1: types.TermQuery.Must:type_name -> types.TermQuery
-	0, // 2: types.TermQuery.Should:type_name -> types.TermQuery
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	5, // 0: types.TermQuery.Keyword:type_name -> types.KeyWord
+	1, // 1: types.TermQuery.Must:type_name -> types.TermQuery
+	1, // 2: types.TermQuery.Should:type_name -> types.TermQuery
+	1, // 3: types.TermQuery.MustNot:type_name -> types.TermQuery
+	3, // 4: types.TermQuery.Phrase:type_name -> types.Phrase
+	4, // 5: types.TermQuery.MultiTerm:type_name -> types.MultiTerm
+	2, // 6: types.TermQuery.Range:type_name -> types.Range
+	0, // 7: types.MultiTerm.Type:type_name -> types.MultiTerm.MultiTermType
+	8, // [8:8] is the sub-list for method output_type
+	8, // [8:8] is the sub-list for method input_type
+	8, // [8:8] is the sub-list for extension type_name
+	8, // [8:8] is the sub-list for extension extendee
+	0, // [0:8] is the sub-list for field type_name
 }
 
 func init() { file_term_query_proto_init() }
@@ -148,19 +514,57 @@ func file_term_query_proto_init() {
 				return nil
 			}
 		}
+		file_term_query_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Range); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_term_query_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Phrase); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_term_query_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MultiTerm); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
+	file_term_query_proto_msgTypes[1].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_term_query_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   1,
+			NumEnums:      1,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_term_query_proto_goTypes,
 		DependencyIndexes: file_term_query_proto_depIdxs,
+		EnumInfos:         file_term_query_proto_enumTypes,
 		MessageInfos:      file_term_query_proto_msgTypes,
 	}.Build()
 	File_term_query_proto = out.File
@@ -1,13 +1,21 @@
 package term_query
 
-import "strings"
+import (
+	"strconv"
+	"strings"
+	"time"
+)
 import "Research/types/doc"
 
-// 每个TermQuery只有一个属性有效
+// 每个TermQuery的Keyword、Phrase、MultiTerm、Range、Must、Should只有一个属性有效，MustNot可以和它们同时使用
 //type TermQuery struct {
 //	KeyWord *KeyWord
 //	Should  []*TermQuery
 //	Must    []*TermQuery
+//	MustNot []*TermQuery
+//	Phrase  *Phrase
+//	MultiTerm *MultiTerm
+//	Range   *Range
 //}
 
 // 创建叶子结点的
@@ -17,8 +25,57 @@ func NewTermQuery(field, word string) *TermQuery {
 	}
 }
 
+// 创建短语叶子结点，slop是词之间最多插入的词数之和
+func NewPhraseQuery(field string, slop int, words ...string) *TermQuery {
+	return &TermQuery{
+		Phrase: &Phrase{Field: field, Words: words, Slop: int32(slop)},
+	}
+}
+
+// 创建前缀查询的叶子结点
+func NewPrefixQuery(field, prefix string) *TermQuery {
+	return &TermQuery{
+		MultiTerm: &MultiTerm{Type: MultiTerm_PREFIX, Field: field, Pattern: prefix},
+	}
+}
+
+// 创建通配符查询的叶子结点，*匹配任意个字符，?匹配一个字符
+func NewWildcardQuery(field, pattern string) *TermQuery {
+	return &TermQuery{
+		MultiTerm: &MultiTerm{Type: MultiTerm_WILDCARD, Field: field, Pattern: pattern},
+	}
+}
+
+// 创建模糊查询的叶子结点，fuzziness是最大编辑距离，0表示根据词长自动选择
+func NewFuzzyQuery(field, word string, fuzziness int) *TermQuery {
+	return &TermQuery{
+		MultiTerm: &MultiTerm{Type: MultiTerm_FUZZY, Field: field, Pattern: word, Fuzziness: int32(fuzziness)},
+	}
+}
+
+// 创建数值范围查询的叶子结点，包含上下界，from或to为nil表示不限
+func NewRangeQuery(field string, from, to *float64) *TermQuery {
+	return &TermQuery{
+		Range: &Range{Field: field, From: from, To: to},
+	}
+}
+
+// 创建日期范围查询的叶子结点，包含上下界，from或to为零值表示不限
+func NewDateRangeQuery(field string, from, to time.Time) *TermQuery {
+	r := &Range{Field: field}
+	if !from.IsZero() {
+		v := float64(from.UnixMilli())
+		r.From = &v
+	}
+	if !to.IsZero() {
+		v := float64(to.UnixMilli())
+		r.To = &v
+	}
+	return &TermQuery{Range: r}
+}
+
 func (q *TermQuery) Empty() bool {
-	return q.Keyword == nil && len(q.Must) == 0 && len(q.Should) == 0
+	return q.Keyword == nil && q.Phrase == nil && q.MultiTerm == nil && q.Range == nil && len(q.Must) == 0 && len(q.Should) == 0 && len(q.MustNot) == 0
 }
 
 func (m *TermQuery) Or(querys ...*TermQuery) *TermQuery {
@@ -59,13 +116,50 @@ func (m *TermQuery) And(querys ...*TermQuery) *TermQuery {
 	return &TermQuery{Must: res}
 }
 
+// 从自身的结果中排除满足任意一个querys的文档
+func (m *TermQuery) Not(querys ...*TermQuery) *TermQuery {
+	if len(querys) == 0 {
+		return m
+	}
+	res := make([]*TermQuery, 0, len(querys))
+	for _, val := range querys {
+		p := val
+		res = append(res, p)
+	}
+
+	return &TermQuery{Must: []*TermQuery{m}, MustNot: res}
+}
+
 // 返回TermQuery的条件表达式
 func (m *TermQuery) ToString() string {
+	if len(m.MustNot) > 0 {
+		//排除的条件用!标记
+		res := strings.Builder{}
+		res.WriteByte('(')
+		if positive := (&TermQuery{Keyword: m.Keyword, Phrase: m.Phrase, MultiTerm: m.MultiTerm, Range: m.Range, Must: m.Must, Should: m.Should}); !positive.Empty() {
+			res.WriteString(positive.ToString())
+		}
+		for _, val := range m.MustNot {
+			res.WriteString("&!")
+			res.WriteString(val.ToString())
+		}
+		res.WriteByte(')')
+		return res.String()
+	}
 	//1、判断是否是叶子结点
 	if m.Keyword != nil {
 		//1.1、是叶子结点，直接返回keyword的tostring
 		return m.Keyword.ToString()
 	}
+	if m.Phrase != nil {
+		return m.Phrase.ToString()
+	}
+	if m.MultiTerm != nil {
+		return m.MultiTerm.ToString()
+	}
+	if m.Range != nil {
+		return m.Range.ToString()
+	}
 	//2、判断哪一个属性有效
 	if len(m.Must) > 0 {
 		return mustOrShould(m.Must, '&')
@@ -92,3 +186,81 @@ func mustOrShould(query []*TermQuery, ch byte) string {
 	str = str[:len(str)-1] + ")"
 	return str
 }
+
+// 短语的表达式，形如field\001"w1 w2"~slop
+func (p *Phrase) ToString() string {
+	res := strings.Builder{}
+	res.WriteString(p.Field)
+	res.WriteString("\001\"")
+	res.WriteString(strings.Join(p.Words, " "))
+	res.WriteByte('"')
+	if p.Slop > 0 {
+		res.WriteByte('~')
+		res.WriteString(strconv.Itoa(int(p.Slop)))
+	}
+	return res.String()
+}
+
+// 短语中每个词对应的倒排索引key
+func (p *Phrase) Keys() []string {
+	keys := make([]string, 0, len(p.Words))
+	for _, word := range p.Words {
+		keys = append(keys, (&doc.KeyWord{Field: p.Field, Word: word}).ToString())
+	}
+	return keys
+}
+
+// 前缀查询形如field\001go*，通配符查询直接使用表达式，模糊查询形如field\001word~fuzziness
+func (t *MultiTerm) ToString() string {
+	switch t.Type {
+	case MultiTerm_PREFIX:
+		return t.Field + "\001" + t.Pattern + "*"
+	case MultiTerm_FUZZY:
+		if t.Fuzziness > 0 {
+			return t.Field + "\001" + t.Pattern + "~" + strconv.Itoa(int(t.Fuzziness))
+		}
+		return t.Field + "\001" + t.Pattern + "~"
+	default:
+		return t.Field + "\001" + t.Pattern
+	}
+}
+
+// 范围查询形如field\001[from,to]，(和)表示不包含边界，*表示不限
+func (r *Range) ToString() string {
+	res := strings.Builder{}
+	res.WriteString(r.Field)
+	res.WriteByte('\001')
+	if r.ExcludeFrom {
+		res.WriteByte('(')
+	} else {
+		res.WriteByte('[')
+	}
+	if r.From != nil {
+		res.WriteString(strconv.FormatFloat(*r.From, 'g', -1, 64))
+	} else {
+		res.WriteByte('*')
+	}
+	res.WriteByte(',')
+	if r.To != nil {
+		res.WriteString(strconv.FormatFloat(*r.To, 'g', -1, 64))
+	} else {
+		res.WriteByte('*')
+	}
+	if r.ExcludeTo {
+		res.WriteByte(')')
+	} else {
+		res.WriteByte(']')
+	}
+	return res.String()
+}
+
+// 判断数值是否在范围内
+func (r *Range) Contains(value float64) bool {
+	if r.From != nil && (value < *r.From || r.ExcludeFrom && value == *r.From) {
+		return false
+	}
+	if r.To != nil && (value > *r.To || r.ExcludeTo && value == *r.To) {
+		return false
+	}
+	return true
+}
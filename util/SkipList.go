@@ -2,7 +2,7 @@ package util
 
 import "github.com/huandu/skiplist"
 
-// 求多个SkipList的交集
+// 求多个SkipList的交集，有一个为nil时交集为空
 func IntersectionOfSkipList(lists ...*skiplist.SkipList) *skiplist.SkipList {
 	if len(lists) == 0 {
 		return nil
@@ -15,6 +15,9 @@ func IntersectionOfSkipList(lists ...*skiplist.SkipList) *skiplist.SkipList {
 	// 1、给每个调表创建指针
 	currNode := make([]*skiplist.Element, len(lists))
 	for i, list := range lists {
+		if list == nil {
+			return nil
+		}
 		currNode[i] = list.Front()
 	}
 
@@ -26,6 +29,10 @@ func IntersectionOfSkipList(lists ...*skiplist.SkipList) *skiplist.SkipList {
 
 		// 2、每次寻找各个跳表中的当前最大值以及数量
 		for _, node := range currNode {
+			// 如果存在一个跳表走到尽头，则结束
+			if node == nil {
+				return res
+			}
 			if node.Key().(uint64) > maxValue {
 				maxValue = node.Key().(uint64)
 				cnt = 1
@@ -39,20 +46,12 @@ func IntersectionOfSkipList(lists ...*skiplist.SkipList) *skiplist.SkipList {
 			res.Set(currNode[0].Key(), currNode[0].Value)
 			// 跳表全部后移一位
 			for i := 0; i < len(currNode); i++ {
-				// 如果存在一个跳表走到尽头，则结束
-				if currNode[i] == nil {
-					return res
-				}
 				currNode[i] = currNode[i].Next()
 			}
 		} else {
 			//3.1、并非所有值相等，小于最大值的后移一位
 			for i := 0; i < len(currNode); i++ {
 				if currNode[i].Key().(uint64) < maxValue {
-					// 如果存在一个跳表走到尽头，则结束
-					if currNode[i] == nil {
-						return res
-					}
 					currNode[i] = currNode[i].Next()
 				}
 			}
@@ -90,3 +89,27 @@ func UnionsetOfSkipList(lists ...*skiplist.SkipList) *skiplist.SkipList {
 	}
 	return res
 }
+
+// 求差集，list中去掉出现在任意一个excludes中的元素
+func DifferenceOfSkipList(list *skiplist.SkipList, excludes ...*skiplist.SkipList) *skiplist.SkipList {
+	if list == nil {
+		return nil
+	}
+
+	res := skiplist.New(skiplist.Uint64) // 返回值
+	node := list.Front()
+	for node != nil {
+		excluded := false
+		for _, exclude := range excludes {
+			if exclude != nil && exclude.Get(node.Key()) != nil {
+				excluded = true
+				break
+			}
+		}
+		if !excluded {
+			res.Set(node.Key(), node.Value)
+		}
+		node = node.Next()
+	}
+	return res
+}
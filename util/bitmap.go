@@ -1,5 +1,10 @@
 package util
 
+import (
+	"bytes"
+	"encoding/gob"
+)
+
 type Bitmap struct {
 	bits []uint64 // 小端存储
 	cap  int      // bit数
@@ -36,6 +41,23 @@ func (m *Bitmap) SetBit(index int) bool {
 	return true
 }
 
+// 下标从1开始，超出容量时不需要清除
+func (m *Bitmap) ClearBit(index int) bool {
+	if index <= 0 {
+		return false
+	}
+
+	pos := index / m.code    // 获取bits第几个数字
+	offset := index % m.code // 获取uint内的偏移量
+
+	if pos >= len(m.bits) {
+		return true
+	}
+
+	m.bits[pos] &^= 1 << (m.code - 1 - offset)
+	return true
+}
+
 func (m *Bitmap) GetBit(index int) (int, bool) {
 	if index >= m.cap {
 		return 0, false
@@ -47,6 +69,11 @@ func (m *Bitmap) GetBit(index int) (int, bool) {
 	return int(m.bits[pos] >> (m.code - 1 - offset) & 1), true
 }
 
+// bit数
+func (m *Bitmap) Cap() int {
+	return m.cap
+}
+
 // 多个Bitmap求交集
 func IntersectionOfBitmaps(bitmaps ...*Bitmap) *Bitmap {
 	if len(bitmaps) < 2 {
@@ -101,3 +128,25 @@ func (m *Bitmap) IsEqual(b *Bitmap) bool {
 	}
 	return true
 }
+
+// gob编码时使用，Bitmap的字段不导出，需要自定义编解码
+type gobBitmap struct {
+	Bits []uint64
+	Cap  int
+	Code int
+}
+
+func (m *Bitmap) GobEncode() ([]byte, error) {
+	var buffer bytes.Buffer
+	err := gob.NewEncoder(&buffer).Encode(gobBitmap{Bits: m.bits, Cap: m.cap, Code: m.code})
+	return buffer.Bytes(), err
+}
+
+func (m *Bitmap) GobDecode(data []byte) error {
+	var b gobBitmap
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&b); err != nil {
+		return err
+	}
+	m.bits, m.cap, m.code = b.Bits, b.Cap, b.Code
+	return nil
+}